@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"ferreteria-inventario-ventas/internal/domain"
+	"ferreteria-inventario-ventas/internal/service"
+	"ferreteria-inventario-ventas/internal/tabular"
+)
+
+// runImport implementa el subcomando:
+//
+//	api import [-dry-run] [-formato csv|xlsx] archivo
+//
+// Imprime el reporte en JSON y devuelve el código de salida
+// (1 si hay filas con errores o el archivo no se pudo leer).
+func runImport(products *service.ProductService, args []string) int {
+
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "solo validar, sin guardar")
+	format := fs.String("formato", "", "csv o xlsx (por defecto según la extensión)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "uso: api import [-dry-run] [-formato csv|xlsx] archivo")
+		return 2
+	}
+
+	path := fs.Arg(0)
+	if *format == "" {
+		*format = tabular.FormatFromName(path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	table, err := tabular.Read(*format, data)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "no se pudo leer el archivo:", err)
+		return 1
+	}
+
+	// En la auditoría queda como hecho desde la consola
+	ctx := domain.WithAuditActor(context.Background(), domain.AuditActor{Actor: "consola"})
+
+	report, err := products.Import(ctx, table, *dryRun)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	enc.Encode(report)
+
+	if len(report.Errores) > 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"log"
+	"time"
+)
+
+// associationInterval es cada cuánto se recalculan los productos que se compran juntos.
+const associationInterval = 24 * time.Hour
+
+// priceInterval es cada cuánto se aplican los cambios de precio programados que ya llegaron a su fecha.
+const priceInterval = time.Minute
+
+// runEvery ejecuta job al arrancar y luego cada interval, registrando el resultado en el log.
+// Un error no detiene el proceso: se reintenta en la siguiente vuelta.
+// Si no hubo nada que hacer (resultado nil y sin error) no se registra.
+func runEvery(interval time.Duration, name string, job func() (any, error)) {
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		result, err := job()
+		if err != nil {
+			log.Printf("%s: %v", name, err)
+		} else if result != nil {
+			log.Printf("%s: %+v", name, result)
+		}
+		<-ticker.C
+	}
+}
@@ -7,17 +7,47 @@ package main
 // @BasePath /api
 
 import (
+	"context"
 	"log"
 	"net/http"
+	"os"
+	"strconv"
+	"time"
+	_ "time/tzdata" // Zonas horarias incluidas en el binario (Windows no las trae)
 
+	"ferreteria-inventario-ventas/internal/domain"
 	"ferreteria-inventario-ventas/internal/service"
 	"ferreteria-inventario-ventas/internal/storage/sqlite"
 	httptransport "ferreteria-inventario-ventas/internal/transport/http"
 	"ferreteria-inventario-ventas/internal/transport/http/http_handlers"
 )
 
+// defaultTimezone es la zona horaria de la tienda si no se configura FERRETERIA_TZ.
+const defaultTimezone = "America/Guayaquil"
+
 func main() {
 
+	// 0️⃣ Zona horaria de la tienda: define dónde empieza y termina cada día
+	// en los reportes y filtros. Las fechas se guardan en UTC.
+	tz := os.Getenv("FERRETERIA_TZ")
+	if tz == "" {
+		tz = defaultTimezone
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		log.Fatalf("zona horaria %q inválida: %v", tz, err)
+	}
+	time.Local = loc
+
+	// Impuesto (%) que se suma al neto de cada venta; 0 si los precios ya lo incluyen
+	tasaImpuesto := 0.0
+	if v := os.Getenv("FERRETERIA_IMPUESTO"); v != "" {
+		tasaImpuesto, err = strconv.ParseFloat(v, 64)
+		if err != nil || tasaImpuesto < 0 || tasaImpuesto > 100 {
+			log.Fatalf("tasa de impuesto %q inválida: debe ser un porcentaje entre 0 y 100", v)
+		}
+	}
+
 	// 1️⃣ Abrir base de datos SQLite (archivo data.db)
 	db, err := sqlite.OpenDB("data.db")
 	if err != nil {
@@ -34,17 +64,82 @@ func main() {
 	clientRepo := sqlite.NewClientRepo(db)
 	productRepo := sqlite.NewProductRepo(db)
 	saleRepo := sqlite.NewSaleRepo(db)
+	categoryRepo := sqlite.NewCategoryRepo(db)
+	brandRepo := sqlite.NewBrandRepo(db)
+	receiptRepo := sqlite.NewReceiptRepo(db)
+	associationRepo := sqlite.NewAssociationRepo(db)
+	auditRepo := sqlite.NewAuditRepo(db)
+	priceRepo := sqlite.NewPriceRepo(db)
+	priceListRepo := sqlite.NewPriceListRepo(db)
+	promotionRepo := sqlite.NewPromotionRepo(db)
+	userRepo := sqlite.NewUserRepo(db)
 
 	// 4️⃣ Crear servicios (lógica de negocio)
-	clientService := service.NewClientService(clientRepo)
-	productService := service.NewProductService(productRepo)
-	saleService := service.NewSaleService(saleRepo)
+	auditService := service.NewAuditService(auditRepo)
+	clientService := service.NewClientService(clientRepo, auditService)
+	productService := service.NewProductService(productRepo, auditService)
+	userService := service.NewUserService(userRepo, auditService)
+	saleService := service.NewSaleService(saleRepo, auditService, userService, tasaImpuesto)
+	categoryService := service.NewCategoryService(categoryRepo)
+	brandService := service.NewBrandService(brandRepo)
+	receiptService := service.NewReceiptService(receiptRepo, auditService)
+	associationService := service.NewAssociationService(associationRepo)
+	dashboardService := service.NewDashboardService(saleRepo, productRepo)
+	priceService := service.NewPriceService(priceRepo, auditService)
+	priceListService := service.NewPriceListService(priceListRepo, auditService)
+	promotionService := service.NewPromotionService(promotionRepo, auditService)
+
+	// Subcomandos de línea de comandos (ej: api import -dry-run productos.xlsx)
+	if len(os.Args) > 1 {
+		code := -1
+		switch os.Args[1] {
+		case "import":
+			code = runImport(productService, os.Args[2:])
+		case "rebuild-summary":
+			code = runRebuildSummary(saleService, os.Args[2:])
+		}
+		if code >= 0 {
+			db.Close()
+			os.Exit(code)
+		}
+	}
+
+	// Resumen diario de ventas: se arma con el historial la primera vez
+	// o si cambió la zona horaria de la tienda
+	if result, err := saleService.EnsureDailySales(); err != nil {
+		log.Fatal(err)
+	} else if result != nil {
+		log.Printf("resumen diario de ventas reconstruido: %+v", *result)
+	}
+
+	// Procesos periódicos en segundo plano
+	go runEvery(associationInterval, "asociaciones de productos", func() (any, error) {
+		return associationService.Rebuild()
+	})
+	go runEvery(priceInterval, "precios programados", func() (any, error) {
+		ctx := domain.WithAuditActor(context.Background(), domain.AuditActor{Actor: "precios programados"})
+		applied, err := priceService.ApplyDue(ctx)
+		if len(applied) == 0 {
+			return nil, err
+		}
+		return applied, err
+	})
 
 	// 5️⃣ Crear handlers HTTP
 	h := &http_handlers.Handlers{
-		ClientsSvc:  clientService,
-		ProductsSvc: productService,
-		SalesSvc:    saleService,
+		ClientsSvc:      clientService,
+		ProductsSvc:     productService,
+		SalesSvc:        saleService,
+		CategoriesSvc:   categoryService,
+		BrandsSvc:       brandService,
+		ReceiptsSvc:     receiptService,
+		AssociationsSvc: associationService,
+		DashboardSvc:    dashboardService,
+		AuditSvc:        auditService,
+		PricesSvc:       priceService,
+		PriceListsSvc:   priceListService,
+		PromotionsSvc:   promotionService,
+		UsersSvc:        userService,
 	}
 
 	// 6️⃣ Crear router
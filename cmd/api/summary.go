@@ -0,0 +1,35 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"ferreteria-inventario-ventas/internal/service"
+)
+
+// runRebuildSummary implementa el subcomando:
+//
+//	api rebuild-summary
+//
+// Recalcula el resumen diario de ventas por producto con todo el historial
+// e imprime el resultado en JSON.
+func runRebuildSummary(sales *service.SaleService, args []string) int {
+
+	if len(args) != 0 {
+		fmt.Fprintln(os.Stderr, "uso: api rebuild-summary")
+		return 2
+	}
+
+	result, err := sales.RebuildDailySales()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	enc.Encode(result)
+
+	return 0
+}
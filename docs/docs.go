@@ -15,9 +15,97 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/products": {
+        "/api/audit": {
+            "get": {
+                "description": "Altas, cambios y bajas de productos, clientes, ventas y cambios de precio con actor (usuario de la sesión), ID de petición (header X-Request-ID),\nfecha y los campos que cambiaron ({\"precio\": {\"antes\": 5, \"despues\": 6}}). Por defecto los más recientes primero.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Audit"
+                ],
+                "summary": "Auditoría de cambios",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Usuario que hizo el cambio",
+                        "name": "actor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "create, update, delete, import o barcodes",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "product, client, sale o price_change",
+                        "name": "entity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID del registro",
+                        "name": "entity_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID de la petición",
+                        "name": "request_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Desde (YYYY-MM-DD o RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Hasta, inclusive si es YYYY-MM-DD",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filas por página (por defecto 50, máximo 500)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor devuelto en next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campo de orden: id, fecha",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc o desc",
+                        "name": "order",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Page-ferreteria-inventario-ventas_internal_domain_AuditEntry"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/brands": {
             "get": {
-                "description": "GET lista productos, POST crea producto",
+                "description": "GET lista marcas, POST crea marca, PUT /api/brands/{id} actualiza, DELETE /api/brands/{id} elimina una marca sin productos",
                 "consumes": [
                     "application/json"
                 ],
@@ -25,16 +113,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Products"
+                    "Brands"
                 ],
-                "summary": "Listar o crear productos",
+                "summary": "Marcas",
                 "parameters": [
                     {
-                        "description": "Producto (solo POST)",
-                        "name": "product",
+                        "description": "Marca (POST/PUT)",
+                        "name": "brand",
                         "in": "body",
                         "schema": {
-                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Product"
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Brand"
                         }
                     }
                 ],
@@ -44,20 +132,20 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Product"
+                                "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Brand"
                             }
                         }
                     },
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Product"
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Brand"
                         }
                     }
                 }
             },
             "post": {
-                "description": "GET lista productos, POST crea producto",
+                "description": "GET lista marcas, POST crea marca, PUT /api/brands/{id} actualiza, DELETE /api/brands/{id} elimina una marca sin productos",
                 "consumes": [
                     "application/json"
                 ],
@@ -65,16 +153,16 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Products"
+                    "Brands"
                 ],
-                "summary": "Listar o crear productos",
+                "summary": "Marcas",
                 "parameters": [
                     {
-                        "description": "Producto (solo POST)",
-                        "name": "product",
+                        "description": "Marca (POST/PUT)",
+                        "name": "brand",
                         "in": "body",
                         "schema": {
-                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Product"
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Brand"
                         }
                     }
                 ],
@@ -84,67 +172,104 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Product"
+                                "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Brand"
                             }
                         }
                     },
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Product"
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Brand"
                         }
                     }
                 }
             }
         },
-        "/api/report/top-productos": {
-            "get": {
-                "description": "Devuelve los 5 productos más vendidos",
+        "/api/brands/{id}": {
+            "put": {
+                "description": "GET lista marcas, POST crea marca, PUT /api/brands/{id} actualiza, DELETE /api/brands/{id} elimina una marca sin productos",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Report"
+                    "Brands"
+                ],
+                "summary": "Marcas",
+                "parameters": [
+                    {
+                        "description": "Marca (POST/PUT)",
+                        "name": "brand",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Brand"
+                        }
+                    }
                 ],
-                "summary": "Top productos vendidos",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "type": "object",
-                                "additionalProperties": true
+                                "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Brand"
                             }
                         }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Brand"
+                        }
                     }
                 }
-            }
-        },
-        "/api/report/ventas-hoy": {
-            "get": {
-                "description": "Devuelve cantidad de ventas y total del día",
+            },
+            "delete": {
+                "description": "GET lista marcas, POST crea marca, PUT /api/brands/{id} actualiza, DELETE /api/brands/{id} elimina una marca sin productos",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Report"
+                    "Brands"
+                ],
+                "summary": "Marcas",
+                "parameters": [
+                    {
+                        "description": "Marca (POST/PUT)",
+                        "name": "brand",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Brand"
+                        }
+                    }
                 ],
-                "summary": "Ventas del día",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Brand"
+                            }
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Brand"
                         }
                     }
                 }
             }
         },
-        "/api/sales": {
+        "/api/categories": {
             "get": {
-                "description": "GET lista ventas, POST crea venta",
+                "description": "GET lista categorías (formato=arbol devuelve el árbol anidado), POST crea categoría,\nPUT /api/categories/{id} actualiza (puede mover a otro padre), DELETE /api/categories/{id} elimina una categoría vacía",
                 "consumes": [
                     "application/json"
                 ],
@@ -152,16 +277,22 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Sales"
+                    "Categories"
                 ],
-                "summary": "Listar o crear ventas",
+                "summary": "Árbol de categorías",
                 "parameters": [
                     {
-                        "description": "Venta (solo POST)",
-                        "name": "sale",
+                        "type": "string",
+                        "description": "plano (por defecto) o arbol",
+                        "name": "formato",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Categoría (POST/PUT)",
+                        "name": "category",
                         "in": "body",
                         "schema": {
-                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Sale"
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Category"
                         }
                     }
                 ],
@@ -171,20 +302,20 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Sale"
+                                "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Category"
                             }
                         }
                     },
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Sale"
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Category"
                         }
                     }
                 }
             },
             "post": {
-                "description": "GET lista ventas, POST crea venta",
+                "description": "GET lista categorías (formato=arbol devuelve el árbol anidado), POST crea categoría,\nPUT /api/categories/{id} actualiza (puede mover a otro padre), DELETE /api/categories/{id} elimina una categoría vacía",
                 "consumes": [
                     "application/json"
                 ],
@@ -192,16 +323,22 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Sales"
+                    "Categories"
                 ],
-                "summary": "Listar o crear ventas",
+                "summary": "Árbol de categorías",
                 "parameters": [
                     {
-                        "description": "Venta (solo POST)",
-                        "name": "sale",
+                        "type": "string",
+                        "description": "plano (por defecto) o arbol",
+                        "name": "formato",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Categoría (POST/PUT)",
+                        "name": "category",
                         "in": "body",
                         "schema": {
-                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Sale"
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Category"
                         }
                     }
                 ],
@@ -211,51 +348,68 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Sale"
+                                "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Category"
                             }
                         }
                     },
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Sale"
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Category"
                         }
                     }
                 }
             }
         },
-        "/api/sales/{id}": {
-            "get": {
-                "description": "Devuelve una venta con sus items",
+        "/api/categories/{id}": {
+            "put": {
+                "description": "GET lista categorías (formato=arbol devuelve el árbol anidado), POST crea categoría,\nPUT /api/categories/{id} actualiza (puede mover a otro padre), DELETE /api/categories/{id} elimina una categoría vacía",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Sales"
+                    "Categories"
                 ],
-                "summary": "Obtener detalle de venta",
+                "summary": "Árbol de categorías",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID de la venta",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "type": "string",
+                        "description": "plano (por defecto) o arbol",
+                        "name": "formato",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Categoría (POST/PUT)",
+                        "name": "category",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Category"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Sale"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Category"
+                            }
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Category"
                         }
                     }
                 }
-            }
-        },
-        "/clients": {
-            "get": {
-                "description": "GET lista clientes, POST crea cliente",
+            },
+            "delete": {
+                "description": "GET lista categorías (formato=arbol devuelve el árbol anidado), POST crea categoría,\nPUT /api/categories/{id} actualiza (puede mover a otro padre), DELETE /api/categories/{id} elimina una categoría vacía",
                 "consumes": [
                     "application/json"
                 ],
@@ -263,16 +417,22 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Clients"
+                    "Categories"
                 ],
-                "summary": "Listar o crear clientes",
+                "summary": "Árbol de categorías",
                 "parameters": [
                     {
-                        "description": "Cliente (solo POST)",
-                        "name": "client",
+                        "type": "string",
+                        "description": "plano (por defecto) o arbol",
+                        "name": "formato",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Categoría (POST/PUT)",
+                        "name": "category",
                         "in": "body",
                         "schema": {
-                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Client"
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Category"
                         }
                     }
                 ],
@@ -282,20 +442,22 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Client"
+                                "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Category"
                             }
                         }
                     },
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Client"
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Category"
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "GET lista clientes, POST crea cliente",
+            }
+        },
+        "/api/clients/{id}/price-list": {
+            "put": {
+                "description": "Con price_list_id asigna la lista; con null la quita (el cliente paga el precio de cada producto)",
                 "consumes": [
                     "application/json"
                 ],
@@ -305,128 +467,5087 @@ const docTemplate = `{
                 "tags": [
                     "Clients"
                 ],
-                "summary": "Listar o crear clientes",
+                "summary": "Asignar lista de precios a un cliente",
                 "parameters": [
                     {
-                        "description": "Cliente (solo POST)",
-                        "name": "client",
+                        "type": "integer",
+                        "description": "ID del cliente",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Lista de precios",
+                        "name": "body",
                         "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_transport_http_http_handlers.clientPriceListInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Client"
                         }
                     }
+                }
+            }
+        },
+        "/api/clients/{id}/sales": {
+            "get": {
+                "description": "Resumen (compras, unidades, total, ticket promedio, primera y última compra), productos comprados\nordenados por monto y las compras paginadas (más recientes primero) en el rango pedido.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Clients"
+                ],
+                "summary": "Historial de compras de un cliente",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID del cliente",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Desde (YYYY-MM-DD o RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Hasta (YYYY-MM-DD incluye ese día, o RFC3339 exclusivo)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Compras por página (por defecto 50, máximo 500)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor devuelto en compras.next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Client"
-                            }
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.ClientHistory"
                         }
                     },
-                    "201": {
-                        "description": "Created",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Client"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "ferreteria-inventario-ventas_internal_domain.Client": {
-            "type": "object",
-            "properties": {
-                "cedula": {
-                    "description": "Número de cédula (único)",
-                    "type": "string"
-                },
-                "email": {
-                    "description": "Correo electrónico",
-                    "type": "string"
-                },
-                "id": {
-                    "description": "Identificador único en la base de datos",
-                    "type": "integer"
-                },
-                "nombre": {
-                    "description": "Nombre completo del cliente",
-                    "type": "string"
-                }
-            }
         },
-        "ferreteria-inventario-ventas_internal_domain.Product": {
-            "type": "object",
-            "properties": {
-                "id": {
-                    "description": "Identificador único en la base de datos",
-                    "type": "integer"
-                },
-                "nombre": {
-                    "description": "Nombre del producto",
-                    "type": "string"
-                },
-                "precio": {
-                    "description": "Precio unitario del producto",
-                    "type": "number"
-                },
-                "stock": {
-                    "description": "Cantidad disponible en inventario",
-                    "type": "integer"
-                }
-            }
+        "/api/dashboard": {
+            "get": {
+                "description": "Ventas de hoy, de la semana (desde el lunes) y del mes, comparadas con el mismo tramo del período anterior,\ncantidad de productos con stock bajo y los más vendidos del mes, en una sola llamada.\nLas secciones se consultan en paralelo con un tiempo máximo cada una; si alguna falla queda en null\ny el motivo aparece en errores.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report"
+                ],
+                "summary": "Tablero de la pantalla principal",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Umbral de stock bajo (por defecto 5)",
+                        "name": "stock_lt",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Dashboard"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/discount-approvals": {
+            "post": {
+                "description": "Con usuario y password de un supervisor o gerente genera un token de un solo uso, válido 15 minutos, que el cajero envía\nen autorizacion.token al crear la venta. Autoriza hasta descuento_max (por defecto y como máximo, el de su rol).\n401 si las credenciales no son válidas, 403 si el usuario no es supervisor ni gerente.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Aprobar un descuento de antemano",
+                "parameters": [
+                    {
+                        "description": "Credenciales del supervisor",
+                        "name": "approval",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_transport_http_http_handlers.discountApprovalInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.DiscountApproval"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/export/clients": {
+            "get": {
+                "description": "Descarga los clientes con los mismos filtros que GET /api/clients",
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "Export"
+                ],
+                "summary": "Exportar clientes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "csv (por defecto), xlsx o ndjson",
+                        "name": "formato",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Nombre contiene",
+                        "name": "nombre",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cédula empieza con",
+                        "name": "cedula",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Email contiene",
+                        "name": "email",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/export/inventory-valuation": {
+            "get": {
+                "description": "Descarga una fila por producto con stock, con los mismos parámetros que /api/report/inventory-valuation",
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "Export"
+                ],
+                "summary": "Exportar valorización del inventario",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "csv (por defecto), xlsx o ndjson",
+                        "name": "formato",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fecha de corte: YYYY-MM-DD (al cierre de ese día) o RFC3339",
+                        "name": "as_of",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Categoría (incluye subcategorías)",
+                        "name": "category_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/export/products": {
+            "get": {
+                "description": "Descarga el catálogo con los mismos filtros que GET /api/products. La columna codigos_barras usa \";\" como separador, igual que la importación",
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "Export"
+                ],
+                "summary": "Exportar productos",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "csv (por defecto), xlsx o ndjson",
+                        "name": "formato",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Nombre contiene",
+                        "name": "nombre",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Stock menor a N",
+                        "name": "stock_lt",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Precio mínimo",
+                        "name": "precio_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Precio máximo",
+                        "name": "precio_max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Categoría (incluye subcategorías)",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Marca",
+                        "name": "brand_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/export/rfm": {
+            "get": {
+                "description": "Descarga una fila por cliente con los mismos parámetros que /api/report/rfm (para campañas de marketing)",
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "Export"
+                ],
+                "summary": "Exportar segmentación RFM",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "csv (por defecto), xlsx o ndjson",
+                        "name": "formato",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Desde (YYYY-MM-DD o RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Hasta (YYYY-MM-DD incluye ese día, o RFC3339 exclusivo)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Solo los clientes de este segmento",
+                        "name": "segment",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/export/sale-items": {
+            "get": {
+                "description": "Descarga una fila por producto vendido, con los datos de la venta, con los mismos filtros que GET /api/sales",
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "Export"
+                ],
+                "summary": "Exportar líneas de venta",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "csv (por defecto), xlsx o ndjson",
+                        "name": "formato",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Cliente",
+                        "name": "client_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Desde (YYYY-MM-DD o RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Hasta (YYYY-MM-DD incluye ese día, o RFC3339 exclusivo)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/export/sales": {
+            "get": {
+                "description": "Descarga una fila por venta con los mismos filtros que GET /api/sales",
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "Export"
+                ],
+                "summary": "Exportar ventas (cabeceras)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "csv (por defecto), xlsx o ndjson",
+                        "name": "formato",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Cliente",
+                        "name": "client_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Desde (YYYY-MM-DD o RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Hasta (YYYY-MM-DD incluye ese día, o RFC3339 exclusivo)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/price-changes": {
+            "get": {
+                "description": "GET lista los cambios (por defecto los más recientes primero), GET /api/price-changes/{id} devuelve uno,\nPOST programa un precio fijo o un porcentaje para un producto desde una fecha (vacía o pasada: se aplica ya),\nDELETE /api/price-changes/{id} cancela un cambio pendiente (409 si ya se aplicó o canceló).\nLos cambios pendientes se aplican solos cada minuto; el historial registra el momento en que se aplicaron.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Prices"
+                ],
+                "summary": "Cambios de precio programados",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Cambios de un producto",
+                        "name": "product_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "pendiente, aplicado o cancelado",
+                        "name": "estado",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filas por página (por defecto 50, máximo 500)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor devuelto en next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campo de orden: id, desde",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc o desc",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Cambio (solo POST): precio o porcentaje",
+                        "name": "change",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/internal_transport_http_http_handlers.priceChangeInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Page-ferreteria-inventario-ventas_internal_domain_PriceChange"
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.PriceChange"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "GET lista los cambios (por defecto los más recientes primero), GET /api/price-changes/{id} devuelve uno,\nPOST programa un precio fijo o un porcentaje para un producto desde una fecha (vacía o pasada: se aplica ya),\nDELETE /api/price-changes/{id} cancela un cambio pendiente (409 si ya se aplicó o canceló).\nLos cambios pendientes se aplican solos cada minuto; el historial registra el momento en que se aplicaron.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Prices"
+                ],
+                "summary": "Cambios de precio programados",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Cambios de un producto",
+                        "name": "product_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "pendiente, aplicado o cancelado",
+                        "name": "estado",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filas por página (por defecto 50, máximo 500)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor devuelto en next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campo de orden: id, desde",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc o desc",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Cambio (solo POST): precio o porcentaje",
+                        "name": "change",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/internal_transport_http_http_handlers.priceChangeInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Page-ferreteria-inventario-ventas_internal_domain_PriceChange"
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.PriceChange"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/price-changes/bulk": {
+            "post": {
+                "description": "Programa un porcentaje (negativo para rebajar) para todos los productos de una categoría (incluye subcategorías)\no de un proveedor (productos recibidos de él en alguna recepción), uno de los dos. El porcentaje se calcula\nsobre el precio que tenga cada producto al aplicarse y se redondea a centavos. Sin desde, o con una fecha pasada, se aplica ya.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Prices"
+                ],
+                "summary": "Aumento masivo de precios",
+                "parameters": [
+                    {
+                        "description": "Aumento masivo",
+                        "name": "bulk",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_transport_http_http_handlers.bulkPriceInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.BulkPriceResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/price-changes/{id}": {
+            "get": {
+                "description": "GET lista los cambios (por defecto los más recientes primero), GET /api/price-changes/{id} devuelve uno,\nPOST programa un precio fijo o un porcentaje para un producto desde una fecha (vacía o pasada: se aplica ya),\nDELETE /api/price-changes/{id} cancela un cambio pendiente (409 si ya se aplicó o canceló).\nLos cambios pendientes se aplican solos cada minuto; el historial registra el momento en que se aplicaron.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Prices"
+                ],
+                "summary": "Cambios de precio programados",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Cambios de un producto",
+                        "name": "product_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "pendiente, aplicado o cancelado",
+                        "name": "estado",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filas por página (por defecto 50, máximo 500)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor devuelto en next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campo de orden: id, desde",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc o desc",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Cambio (solo POST): precio o porcentaje",
+                        "name": "change",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/internal_transport_http_http_handlers.priceChangeInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Page-ferreteria-inventario-ventas_internal_domain_PriceChange"
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.PriceChange"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "GET lista los cambios (por defecto los más recientes primero), GET /api/price-changes/{id} devuelve uno,\nPOST programa un precio fijo o un porcentaje para un producto desde una fecha (vacía o pasada: se aplica ya),\nDELETE /api/price-changes/{id} cancela un cambio pendiente (409 si ya se aplicó o canceló).\nLos cambios pendientes se aplican solos cada minuto; el historial registra el momento en que se aplicaron.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Prices"
+                ],
+                "summary": "Cambios de precio programados",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Cambios de un producto",
+                        "name": "product_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "pendiente, aplicado o cancelado",
+                        "name": "estado",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filas por página (por defecto 50, máximo 500)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor devuelto en next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campo de orden: id, desde",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc o desc",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Cambio (solo POST): precio o porcentaje",
+                        "name": "change",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/internal_transport_http_http_handlers.priceChangeInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Page-ferreteria-inventario-ventas_internal_domain_PriceChange"
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.PriceChange"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/price-lists": {
+            "get": {
+                "description": "GET lista todas con sus reglas, GET /api/price-lists/{id} devuelve una, POST crea, PUT /api/price-lists/{id} reemplaza nombre, descripción y reglas,\nDELETE /api/price-lists/{id} elimina una lista sin clientes asignados ni ventas (409 si se usó).\nCada regla aplica a un producto (product_id), una categoría con sus subcategorías (category_id) o a todos los productos (ninguno),\ndesde cantidad_min unidades de la línea, con precio fijo (solo por producto) o porcentaje sobre el precio del producto (negativo = descuento).\nAl vender gana la regla más específica (producto, categoría más cercana, general) y dentro de ella la escala más alta alcanzada.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "PriceLists"
+                ],
+                "summary": "Listas de precios",
+                "parameters": [
+                    {
+                        "description": "Lista (POST/PUT)",
+                        "name": "list",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.PriceList"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.PriceList"
+                            }
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.PriceList"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "GET lista todas con sus reglas, GET /api/price-lists/{id} devuelve una, POST crea, PUT /api/price-lists/{id} reemplaza nombre, descripción y reglas,\nDELETE /api/price-lists/{id} elimina una lista sin clientes asignados ni ventas (409 si se usó).\nCada regla aplica a un producto (product_id), una categoría con sus subcategorías (category_id) o a todos los productos (ninguno),\ndesde cantidad_min unidades de la línea, con precio fijo (solo por producto) o porcentaje sobre el precio del producto (negativo = descuento).\nAl vender gana la regla más específica (producto, categoría más cercana, general) y dentro de ella la escala más alta alcanzada.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "PriceLists"
+                ],
+                "summary": "Listas de precios",
+                "parameters": [
+                    {
+                        "description": "Lista (POST/PUT)",
+                        "name": "list",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.PriceList"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.PriceList"
+                            }
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.PriceList"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/price-lists/{id}": {
+            "get": {
+                "description": "GET lista todas con sus reglas, GET /api/price-lists/{id} devuelve una, POST crea, PUT /api/price-lists/{id} reemplaza nombre, descripción y reglas,\nDELETE /api/price-lists/{id} elimina una lista sin clientes asignados ni ventas (409 si se usó).\nCada regla aplica a un producto (product_id), una categoría con sus subcategorías (category_id) o a todos los productos (ninguno),\ndesde cantidad_min unidades de la línea, con precio fijo (solo por producto) o porcentaje sobre el precio del producto (negativo = descuento).\nAl vender gana la regla más específica (producto, categoría más cercana, general) y dentro de ella la escala más alta alcanzada.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "PriceLists"
+                ],
+                "summary": "Listas de precios",
+                "parameters": [
+                    {
+                        "description": "Lista (POST/PUT)",
+                        "name": "list",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.PriceList"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.PriceList"
+                            }
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.PriceList"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "GET lista todas con sus reglas, GET /api/price-lists/{id} devuelve una, POST crea, PUT /api/price-lists/{id} reemplaza nombre, descripción y reglas,\nDELETE /api/price-lists/{id} elimina una lista sin clientes asignados ni ventas (409 si se usó).\nCada regla aplica a un producto (product_id), una categoría con sus subcategorías (category_id) o a todos los productos (ninguno),\ndesde cantidad_min unidades de la línea, con precio fijo (solo por producto) o porcentaje sobre el precio del producto (negativo = descuento).\nAl vender gana la regla más específica (producto, categoría más cercana, general) y dentro de ella la escala más alta alcanzada.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "PriceLists"
+                ],
+                "summary": "Listas de precios",
+                "parameters": [
+                    {
+                        "description": "Lista (POST/PUT)",
+                        "name": "list",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.PriceList"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.PriceList"
+                            }
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.PriceList"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "GET lista todas con sus reglas, GET /api/price-lists/{id} devuelve una, POST crea, PUT /api/price-lists/{id} reemplaza nombre, descripción y reglas,\nDELETE /api/price-lists/{id} elimina una lista sin clientes asignados ni ventas (409 si se usó).\nCada regla aplica a un producto (product_id), una categoría con sus subcategorías (category_id) o a todos los productos (ninguno),\ndesde cantidad_min unidades de la línea, con precio fijo (solo por producto) o porcentaje sobre el precio del producto (negativo = descuento).\nAl vender gana la regla más específica (producto, categoría más cercana, general) y dentro de ella la escala más alta alcanzada.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "PriceLists"
+                ],
+                "summary": "Listas de precios",
+                "parameters": [
+                    {
+                        "description": "Lista (POST/PUT)",
+                        "name": "list",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.PriceList"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.PriceList"
+                            }
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.PriceList"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/products": {
+            "get": {
+                "description": "GET lista productos paginados con filtros, POST crea producto",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Listar o crear productos",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Filas por página (por defecto 50, máximo 500)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor devuelto en next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campo de orden: id, nombre, stock, precio",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc o desc",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Nombre contiene",
+                        "name": "nombre",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Stock menor a N",
+                        "name": "stock_lt",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Precio mínimo",
+                        "name": "precio_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Precio máximo",
+                        "name": "precio_max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Categoría (incluye subcategorías)",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Marca",
+                        "name": "brand_id",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Producto (solo POST)",
+                        "name": "product",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Product"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Page-ferreteria-inventario-ventas_internal_domain_Product"
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Product"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "GET lista productos paginados con filtros, POST crea producto",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Listar o crear productos",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Filas por página (por defecto 50, máximo 500)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor devuelto en next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campo de orden: id, nombre, stock, precio",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc o desc",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Nombre contiene",
+                        "name": "nombre",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Stock menor a N",
+                        "name": "stock_lt",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Precio mínimo",
+                        "name": "precio_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Precio máximo",
+                        "name": "precio_max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Categoría (incluye subcategorías)",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Marca",
+                        "name": "brand_id",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Producto (solo POST)",
+                        "name": "product",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Product"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Page-ferreteria-inventario-ventas_internal_domain_Product"
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Product"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/products/barcodes": {
+            "post": {
+                "description": "Asigna códigos a productos (por product_id o sku). Valida el dígito verificador; si alguna fila falla no se asigna ninguna y se devuelven los errores por fila",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Asignar códigos de barras en bloque",
+                "parameters": [
+                    {
+                        "description": "Asignaciones",
+                        "name": "barcodes",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.BarcodeAssignment"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "integer"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/products/by-barcode/{code}": {
+            "get": {
+                "description": "Devuelve el producto de un código EAN-13, UPC-A o EAN-8 (pensado para lectores USB en la pantalla de ventas)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Buscar producto por código de barras",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Código de barras",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Product"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/products/import": {
+            "post": {
+                "description": "Crea o actualiza productos (por sku y si no por nombre). Las columnas se reconocen por la cabecera: nombre, sku, stock, precio, codigo_barras, categoria, marca. Con dry_run=true solo valida; sin él guarda todas las filas o ninguna. El archivo va en el campo \"archivo\" (multipart) o como cuerpo con Content-Type text/csv o de XLSX",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Importar productos desde CSV o XLSX",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Archivo .csv o .xlsx",
+                        "name": "archivo",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Solo validar, sin guardar",
+                        "name": "dry_run",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "csv o xlsx (si no se deduce del archivo)",
+                        "name": "formato",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.ImportReport"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.ImportReport"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/products/search": {
+            "get": {
+                "description": "Búsqueda de texto completo por nombre: ignora tildes y mayúsculas, cada palabra se busca como prefijo y los resultados vienen ordenados por relevancia",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Buscar productos",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Texto a buscar (ej: tornillo autoperforante 1/2)",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Máximo de resultados (por defecto 20, máximo 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Product"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/products/{id}/prices": {
+            "get": {
+                "description": "Sin at devuelve el historial (más reciente primero) y los cambios programados pendientes.\nCon at devuelve el precio que regía en esa fecha (una fecha sin hora toma el precio al final de ese día).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Prices"
+                ],
+                "summary": "Historial de precios de un producto",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID del producto",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fecha (YYYY-MM-DD o RFC3339)",
+                        "name": "at",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.PriceAt"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/products/{id}/recommendations": {
+            "get": {
+                "description": "Sugerencias para la pantalla de ventas: productos que aparecen en las mismas ventas que el producto\n(último año, recalculado cada día) más de lo esperado por azar (lift \u003e 1), ordenados por confianza.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Productos que se compran juntos",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID del producto",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Máximo de sugerencias (por defecto 5, máximo 20)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Solo productos con stock (por defecto true)",
+                        "name": "in_stock",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Recommendation"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/promotions": {
+            "get": {
+                "description": "GET lista todas (las más recientes primero; vigentes=true solo las que se aplicarían a una venta ahora), GET /api/promotions/{id} devuelve una,\nPOST crea, PUT /api/promotions/{id} reemplaza, DELETE /api/promotions/{id} elimina una promoción sin ventas (409 si ya hizo descuentos: desactivarla con activa=false).\ntipo: porcentaje (descuento por línea desde cantidad_min unidades), nxm (lleva compra + gratis y paga compra, por línea)\no monto (descuento fijo sobre la venta si los productos alcanzados suman monto_min). Rige desde ` + "`" + `desde` + "`" + ` hasta ` + "`" + `hasta` + "`" + ` (exclusivo, opcional).\nobjetivos: productos, categorías (con subcategorías) o marcas; sin objetivos alcanza a todo. El producto debe cumplir cada tipo indicado.\nSe evalúan al crear la venta de mayor a menor prioridad; una promoción no acumulable no se suma a otros descuentos.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Promotions"
+                ],
+                "summary": "Promociones",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Solo vigentes",
+                        "name": "vigentes",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Promoción (POST/PUT)",
+                        "name": "promotion",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Promotion"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Promotion"
+                            }
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Promotion"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "GET lista todas (las más recientes primero; vigentes=true solo las que se aplicarían a una venta ahora), GET /api/promotions/{id} devuelve una,\nPOST crea, PUT /api/promotions/{id} reemplaza, DELETE /api/promotions/{id} elimina una promoción sin ventas (409 si ya hizo descuentos: desactivarla con activa=false).\ntipo: porcentaje (descuento por línea desde cantidad_min unidades), nxm (lleva compra + gratis y paga compra, por línea)\no monto (descuento fijo sobre la venta si los productos alcanzados suman monto_min). Rige desde ` + "`" + `desde` + "`" + ` hasta ` + "`" + `hasta` + "`" + ` (exclusivo, opcional).\nobjetivos: productos, categorías (con subcategorías) o marcas; sin objetivos alcanza a todo. El producto debe cumplir cada tipo indicado.\nSe evalúan al crear la venta de mayor a menor prioridad; una promoción no acumulable no se suma a otros descuentos.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Promotions"
+                ],
+                "summary": "Promociones",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Solo vigentes",
+                        "name": "vigentes",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Promoción (POST/PUT)",
+                        "name": "promotion",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Promotion"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Promotion"
+                            }
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Promotion"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/promotions/{id}": {
+            "get": {
+                "description": "GET lista todas (las más recientes primero; vigentes=true solo las que se aplicarían a una venta ahora), GET /api/promotions/{id} devuelve una,\nPOST crea, PUT /api/promotions/{id} reemplaza, DELETE /api/promotions/{id} elimina una promoción sin ventas (409 si ya hizo descuentos: desactivarla con activa=false).\ntipo: porcentaje (descuento por línea desde cantidad_min unidades), nxm (lleva compra + gratis y paga compra, por línea)\no monto (descuento fijo sobre la venta si los productos alcanzados suman monto_min). Rige desde ` + "`" + `desde` + "`" + ` hasta ` + "`" + `hasta` + "`" + ` (exclusivo, opcional).\nobjetivos: productos, categorías (con subcategorías) o marcas; sin objetivos alcanza a todo. El producto debe cumplir cada tipo indicado.\nSe evalúan al crear la venta de mayor a menor prioridad; una promoción no acumulable no se suma a otros descuentos.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Promotions"
+                ],
+                "summary": "Promociones",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Solo vigentes",
+                        "name": "vigentes",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Promoción (POST/PUT)",
+                        "name": "promotion",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Promotion"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Promotion"
+                            }
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Promotion"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "GET lista todas (las más recientes primero; vigentes=true solo las que se aplicarían a una venta ahora), GET /api/promotions/{id} devuelve una,\nPOST crea, PUT /api/promotions/{id} reemplaza, DELETE /api/promotions/{id} elimina una promoción sin ventas (409 si ya hizo descuentos: desactivarla con activa=false).\ntipo: porcentaje (descuento por línea desde cantidad_min unidades), nxm (lleva compra + gratis y paga compra, por línea)\no monto (descuento fijo sobre la venta si los productos alcanzados suman monto_min). Rige desde ` + "`" + `desde` + "`" + ` hasta ` + "`" + `hasta` + "`" + ` (exclusivo, opcional).\nobjetivos: productos, categorías (con subcategorías) o marcas; sin objetivos alcanza a todo. El producto debe cumplir cada tipo indicado.\nSe evalúan al crear la venta de mayor a menor prioridad; una promoción no acumulable no se suma a otros descuentos.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Promotions"
+                ],
+                "summary": "Promociones",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Solo vigentes",
+                        "name": "vigentes",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Promoción (POST/PUT)",
+                        "name": "promotion",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Promotion"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Promotion"
+                            }
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Promotion"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "GET lista todas (las más recientes primero; vigentes=true solo las que se aplicarían a una venta ahora), GET /api/promotions/{id} devuelve una,\nPOST crea, PUT /api/promotions/{id} reemplaza, DELETE /api/promotions/{id} elimina una promoción sin ventas (409 si ya hizo descuentos: desactivarla con activa=false).\ntipo: porcentaje (descuento por línea desde cantidad_min unidades), nxm (lleva compra + gratis y paga compra, por línea)\no monto (descuento fijo sobre la venta si los productos alcanzados suman monto_min). Rige desde ` + "`" + `desde` + "`" + ` hasta ` + "`" + `hasta` + "`" + ` (exclusivo, opcional).\nobjetivos: productos, categorías (con subcategorías) o marcas; sin objetivos alcanza a todo. El producto debe cumplir cada tipo indicado.\nSe evalúan al crear la venta de mayor a menor prioridad; una promoción no acumulable no se suma a otros descuentos.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Promotions"
+                ],
+                "summary": "Promociones",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Solo vigentes",
+                        "name": "vigentes",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Promoción (POST/PUT)",
+                        "name": "promotion",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Promotion"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Promotion"
+                            }
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Promotion"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/receipts": {
+            "get": {
+                "description": "GET lista recepciones paginadas, GET /api/receipts/{id} devuelve el detalle,\nPOST registra una recepción: suma stock y recalcula el costo promedio ponderado de cada producto (todo o nada)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Receipts"
+                ],
+                "summary": "Recepciones de mercadería",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Filas por página (por defecto 50, máximo 500)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor devuelto en next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campo de orden: id, fecha, total",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc o desc",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Proveedor contiene",
+                        "name": "proveedor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Desde (YYYY-MM-DD o RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Hasta (YYYY-MM-DD incluye ese día, o RFC3339 exclusivo)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Recepción (solo POST)",
+                        "name": "receipt",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Receipt"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Page-ferreteria-inventario-ventas_internal_domain_Receipt"
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Receipt"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "GET lista recepciones paginadas, GET /api/receipts/{id} devuelve el detalle,\nPOST registra una recepción: suma stock y recalcula el costo promedio ponderado de cada producto (todo o nada)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Receipts"
+                ],
+                "summary": "Recepciones de mercadería",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Filas por página (por defecto 50, máximo 500)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor devuelto en next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campo de orden: id, fecha, total",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc o desc",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Proveedor contiene",
+                        "name": "proveedor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Desde (YYYY-MM-DD o RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Hasta (YYYY-MM-DD incluye ese día, o RFC3339 exclusivo)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Recepción (solo POST)",
+                        "name": "receipt",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Receipt"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Page-ferreteria-inventario-ventas_internal_domain_Receipt"
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Receipt"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/receipts/{id}": {
+            "get": {
+                "description": "GET lista recepciones paginadas, GET /api/receipts/{id} devuelve el detalle,\nPOST registra una recepción: suma stock y recalcula el costo promedio ponderado de cada producto (todo o nada)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Receipts"
+                ],
+                "summary": "Recepciones de mercadería",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Filas por página (por defecto 50, máximo 500)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor devuelto en next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campo de orden: id, fecha, total",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc o desc",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Proveedor contiene",
+                        "name": "proveedor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Desde (YYYY-MM-DD o RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Hasta (YYYY-MM-DD incluye ese día, o RFC3339 exclusivo)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Recepción (solo POST)",
+                        "name": "receipt",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Receipt"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Page-ferreteria-inventario-ventas_internal_domain_Receipt"
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Receipt"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/report/abc": {
+            "get": {
+                "description": "Clasifica todos los productos por su aporte a la métrica en el rango (por defecto los últimos 12 meses):\nA hasta el 80 % acumulado, B hasta el 95 % y C el resto, incluidos los que no se vendieron.\nDevuelve el resumen por clase y cada producto con su participación y acumulado.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report"
+                ],
+                "summary": "Análisis ABC de productos",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Desde (YYYY-MM-DD o RFC3339); por defecto 12 meses antes de to",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Hasta (YYYY-MM-DD incluye ese día, o RFC3339 exclusivo); por defecto ahora",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "revenue (por defecto), units o margin",
+                        "name": "metric",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Categoría (incluye subcategorías)",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "% acumulado que cierra la clase A (por defecto 80)",
+                        "name": "a",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "% acumulado que cierra la clase B (por defecto 95)",
+                        "name": "b",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.ABCReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/report/dead-stock": {
+            "get": {
+                "description": "Productos con stock que no se venden desde la fecha de corte (por defecto hace 180 días),\ncon su última venta y el capital inmovilizado al costo promedio. No incluye productos creados después del corte.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report"
+                ],
+                "summary": "Stock sin movimiento",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Días sin ventas (por defecto 180)",
+                        "name": "days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fecha de corte (YYYY-MM-DD o RFC3339); reemplaza a days",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Categoría (incluye subcategorías)",
+                        "name": "category_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.DeadStockReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/report/discounts": {
+            "get": {
+                "description": "Por cajero (usuario de la sesión con que se hizo la venta): ventas, bruto, ventas con descuento manual, cantidad y monto de descuentos,\nporcentaje sobre el bruto y cuántas ventas necesitaron autorización de un supervisor. Por defecto los últimos 30 días con hoy.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report"
+                ],
+                "summary": "Descuentos manuales por cajero",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Desde (YYYY-MM-DD o RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Hasta (YYYY-MM-DD incluye ese día, o RFC3339 exclusivo)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.DiscountReport"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/report/heatmap": {
+            "get": {
+                "description": "Matriz de 7 días (lunes a domingo) × 24 horas en hora local de la tienda: ventas y monto cobrado, con promedio y mediana por ocurrencia (cada lunes de 10 a 11 del rango cuenta, aunque no tenga ventas). Por defecto los últimos 84 días completos",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report"
+                ],
+                "summary": "Mapa de calor de ventas por día y hora",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Desde (YYYY-MM-DD o RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Hasta (YYYY-MM-DD incluye ese día, o RFC3339 exclusivo)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.SalesHeatmap"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/report/inventory-valuation": {
+            "get": {
+                "description": "Cantidad, costo promedio, precio, valor al costo y valor a precio de venta de cada producto con stock,\ncon subtotales por categoría y total general.\nCon as_of valoriza el stock a esa fecha, reconstruido desde las ventas y los movimientos de stock\n(el precio de venta es el actual).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report"
+                ],
+                "summary": "Valorización del inventario",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Fecha de corte: YYYY-MM-DD (al cierre de ese día) o RFC3339. Sin fecha = stock actual",
+                        "name": "as_of",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Categoría (incluye subcategorías)",
+                        "name": "category_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.InventoryValuation"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/report/margins": {
+            "get": {
+                "description": "Ingresos netos (descuentos repartidos, sin impuestos), costo de lo vendido al momento de cada venta, utilidad y margen %.\nAgrupa por venta (más recientes primero), producto o categoría (incluye subcategorías). Por día, semana o mes usar /api/report/sales-summary.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report"
+                ],
+                "summary": "Utilidad bruta y margen",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "sale, product o category",
+                        "name": "group_by",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Desde (YYYY-MM-DD o RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Hasta (YYYY-MM-DD incluye ese día, o RFC3339 exclusivo)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filas (por defecto 100, máximo 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.MarginRow"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/report/reorder": {
+            "get": {
+                "description": "Estima la demanda diaria de cada producto con el historial de ventas (días completos hasta ayer)\npor promedio móvil (sma) o suavizado exponencial (ses), opcionalmente con estacionalidad (season=7 para el patrón semanal).\nDevuelve días de cobertura con el stock actual, stock de seguridad para el nivel de servicio,\npunto de reorden y cantidad sugerida a pedir para cubrir el plazo de entrega más cover_days.\nOrdenado por días de cobertura (los que se agotan antes primero).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report"
+                ],
+                "summary": "Velocidad de venta y reposición",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Días de historial (por defecto 90, máximo 730)",
+                        "name": "days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "sma (por defecto) o ses",
+                        "name": "method",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Días del promedio móvil (por defecto 28)",
+                        "name": "window",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Factor de suavizado de ses, 0 a 1 (por defecto 0.3)",
+                        "name": "alpha",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Largo de la temporada en días (7 = semana); sin valor no hay estacionalidad",
+                        "name": "season",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Plazo de entrega del proveedor en días (por defecto 7)",
+                        "name": "lead_time",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Días de demanda a cubrir además del plazo (por defecto 30)",
+                        "name": "cover_days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Nivel de servicio para el stock de seguridad, 0.5 a 0.999 (por defecto 0.95)",
+                        "name": "service_level",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Categoría (incluye subcategorías)",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Solo productos a reponer",
+                        "name": "only_reorder",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.ReorderReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/report/rfm": {
+            "get": {
+                "description": "Recencia (días desde la última compra), frecuencia y monto de las compras en el rango (por defecto los últimos 12 meses)\nde cada cliente que compró alguna vez, con puntajes de 1 a 5 por quintiles y segmento:\nchampion, loyal, potential_loyalist, new, need_attention, cant_lose, at_risk, hibernating o lost.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report"
+                ],
+                "summary": "Segmentación RFM de clientes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Desde (YYYY-MM-DD o RFC3339); por defecto 12 meses antes de to",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Hasta (YYYY-MM-DD incluye ese día, o RFC3339 exclusivo); por defecto hoy",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Solo los clientes de este segmento",
+                        "name": "segment",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.RFMReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/report/sales-summary": {
+            "get": {
+                "description": "Cantidad de ventas, bruto, descuentos, impuestos, neto, total y ticket promedio por día, semana (lunes a domingo) o mes. Incluye los períodos sin ventas",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report"
+                ],
+                "summary": "Resumen de ventas por período",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Desde (YYYY-MM-DD o RFC3339)",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Hasta (YYYY-MM-DD incluye ese día, o RFC3339 exclusivo)",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "day (por defecto), week o month",
+                        "name": "group_by",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.SalesSummary"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/report/top-productos": {
+            "get": {
+                "description": "Ranking de productos (agrupado por ID) por unidades, monto vendido o utilidad bruta, con rango de fechas y filtro de categoría. Con order=asc devuelve los menos vendidos, incluyendo los que no tienen ventas.\nCon group_by=category o group_by=brand agrega las ventas por categoría (sumando subcategorías) o por marca.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report"
+                ],
+                "summary": "Top productos vendidos",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "product (por defecto), category o brand",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filas (por defecto 5, máximo 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "units (por defecto), revenue o margin",
+                        "name": "metric",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "desc (por defecto) o asc",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Categoría (incluye subcategorías)",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Desde (YYYY-MM-DD o RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Hasta (YYYY-MM-DD incluye ese día, o RFC3339 exclusivo)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.GroupSales"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/report/ventas-hoy": {
+            "get": {
+                "description": "Devuelve cantidad de ventas y totales del día",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report"
+                ],
+                "summary": "Ventas del día",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.SalesTotals"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/roles": {
+            "get": {
+                "description": "GET lista los roles con el descuento manual máximo (%) que dan sin autorización.\nPUT /api/roles/{nombre} { \"descuento_max\": 10 } crea el rol o cambia su máximo (0 a 100); requiere una sesión de supervisor o gerente.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Roles y descuento máximo",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e de POST /api/sessions (PUT)",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Descuento máximo (PUT)",
+                        "name": "role",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Role"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Role"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/roles/{nombre}": {
+            "put": {
+                "description": "GET lista los roles con el descuento manual máximo (%) que dan sin autorización.\nPUT /api/roles/{nombre} { \"descuento_max\": 10 } crea el rol o cambia su máximo (0 a 100); requiere una sesión de supervisor o gerente.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Roles y descuento máximo",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e de POST /api/sessions (PUT)",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Rol (PUT)",
+                        "name": "nombre",
+                        "in": "path"
+                    },
+                    {
+                        "description": "Descuento máximo (PUT)",
+                        "name": "role",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Role"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Role"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/sales": {
+            "get": {
+                "description": "GET lista ventas, POST crea venta.\nSi se envía el header Idempotency-Key, un reintento con la misma llave devuelve la venta original\n(header Idempotent-Replayed: true) y una llave reutilizada con otro cuerpo responde 422.\nDescuentos manuales: descuento_manual en cada item o en la venta, con porcentaje o monto y motivo; se aplican después de las promociones.\nEl cajero es el usuario de la sesión (header Authorization): los descuentos manuales requieren sesión (401 sin ella) y el cajero puede dar hasta el\ndescuento máximo de su rol; por encima hace falta autorizacion con usuario y password de un supervisor o gerente o un token\nde POST /api/discount-approvals (403 si no alcanza o el usuario no es supervisor, 401 si las credenciales no son válidas).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sales"
+                ],
+                "summary": "Listar o crear ventas",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Llave única por intento de venta (solo POST)",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filas por página (por defecto 50, máximo 500)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor devuelto en next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campo de orden: id, fecha, total",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc o desc",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Ventas de un cliente",
+                        "name": "client_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Desde (YYYY-MM-DD o RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Hasta, inclusive si es YYYY-MM-DD",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e de la sesión del cajero (solo POST)",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Venta (solo POST)",
+                        "name": "sale",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/internal_transport_http_http_handlers.saleInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Page-ferreteria-inventario-ventas_internal_domain_Sale"
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Sale"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "GET lista ventas, POST crea venta.\nSi se envía el header Idempotency-Key, un reintento con la misma llave devuelve la venta original\n(header Idempotent-Replayed: true) y una llave reutilizada con otro cuerpo responde 422.\nDescuentos manuales: descuento_manual en cada item o en la venta, con porcentaje o monto y motivo; se aplican después de las promociones.\nEl cajero es el usuario de la sesión (header Authorization): los descuentos manuales requieren sesión (401 sin ella) y el cajero puede dar hasta el\ndescuento máximo de su rol; por encima hace falta autorizacion con usuario y password de un supervisor o gerente o un token\nde POST /api/discount-approvals (403 si no alcanza o el usuario no es supervisor, 401 si las credenciales no son válidas).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sales"
+                ],
+                "summary": "Listar o crear ventas",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Llave única por intento de venta (solo POST)",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Filas por página (por defecto 50, máximo 500)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor devuelto en next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campo de orden: id, fecha, total",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc o desc",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Ventas de un cliente",
+                        "name": "client_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Desde (YYYY-MM-DD o RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Hasta, inclusive si es YYYY-MM-DD",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e de la sesión del cajero (solo POST)",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Venta (solo POST)",
+                        "name": "sale",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/internal_transport_http_http_handlers.saleInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Page-ferreteria-inventario-ventas_internal_domain_Sale"
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Sale"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/sales/{id}": {
+            "get": {
+                "description": "Devuelve una venta con sus items",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Sales"
+                ],
+                "summary": "Obtener detalle de venta",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID de la venta",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Sale"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/sessions": {
+            "post": {
+                "description": "POST con usuario y password inicia una sesión de 12 horas y devuelve el token, que se envía en el header\nAuthorization: Bearer \u003ctoken\u003e. Con la sesión se identifica al cajero de las ventas y al actor de la auditoría.\nDELETE cierra la sesión del header Authorization. 401 si las credenciales o la sesión no son válidas.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Iniciar o cerrar sesión",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e (DELETE)",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Credenciales (POST)",
+                        "name": "session",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/internal_transport_http_http_handlers.sessionInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Session"
+                        }
+                    },
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            },
+            "delete": {
+                "description": "POST con usuario y password inicia una sesión de 12 horas y devuelve el token, que se envía en el header\nAuthorization: Bearer \u003ctoken\u003e. Con la sesión se identifica al cajero de las ventas y al actor de la auditoría.\nDELETE cierra la sesión del header Authorization. 401 si las credenciales o la sesión no son válidas.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Iniciar o cerrar sesión",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e (DELETE)",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Credenciales (POST)",
+                        "name": "session",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/internal_transport_http_http_handlers.sessionInput"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Session"
+                        }
+                    },
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/api/users": {
+            "get": {
+                "description": "GET lista los usuarios, GET /api/users/{id} devuelve uno, POST crea (password de 8 caracteres o más),\nPUT /api/users/{id} cambia nombre, rol, activo y la contraseña si viene (el usuario no cambia). La contraseña nunca se devuelve.\nRequiere una sesión de supervisor o gerente (401 sin sesión, 403 con otro rol); mientras no hay usuarios, el primero\n(supervisor o gerente) se crea sin sesión. El usuario que vende se identifica con su sesión; su rol define el descuento manual máximo.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Usuarios",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e de POST /api/sessions",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Usuario (POST/PUT)",
+                        "name": "user",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.User"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.User"
+                            }
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.User"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "GET lista los usuarios, GET /api/users/{id} devuelve uno, POST crea (password de 8 caracteres o más),\nPUT /api/users/{id} cambia nombre, rol, activo y la contraseña si viene (el usuario no cambia). La contraseña nunca se devuelve.\nRequiere una sesión de supervisor o gerente (401 sin sesión, 403 con otro rol); mientras no hay usuarios, el primero\n(supervisor o gerente) se crea sin sesión. El usuario que vende se identifica con su sesión; su rol define el descuento manual máximo.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Usuarios",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e de POST /api/sessions",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Usuario (POST/PUT)",
+                        "name": "user",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.User"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.User"
+                            }
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.User"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/users/{id}": {
+            "get": {
+                "description": "GET lista los usuarios, GET /api/users/{id} devuelve uno, POST crea (password de 8 caracteres o más),\nPUT /api/users/{id} cambia nombre, rol, activo y la contraseña si viene (el usuario no cambia). La contraseña nunca se devuelve.\nRequiere una sesión de supervisor o gerente (401 sin sesión, 403 con otro rol); mientras no hay usuarios, el primero\n(supervisor o gerente) se crea sin sesión. El usuario que vende se identifica con su sesión; su rol define el descuento manual máximo.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Usuarios",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e de POST /api/sessions",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Usuario (POST/PUT)",
+                        "name": "user",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.User"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.User"
+                            }
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.User"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "GET lista los usuarios, GET /api/users/{id} devuelve uno, POST crea (password de 8 caracteres o más),\nPUT /api/users/{id} cambia nombre, rol, activo y la contraseña si viene (el usuario no cambia). La contraseña nunca se devuelve.\nRequiere una sesión de supervisor o gerente (401 sin sesión, 403 con otro rol); mientras no hay usuarios, el primero\n(supervisor o gerente) se crea sin sesión. El usuario que vende se identifica con su sesión; su rol define el descuento manual máximo.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Usuarios",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer \u003ctoken\u003e de POST /api/sessions",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Usuario (POST/PUT)",
+                        "name": "user",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.User"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.User"
+                            }
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.User"
+                        }
+                    }
+                }
+            }
+        },
+        "/clients": {
+            "get": {
+                "description": "GET lista clientes paginados con filtros, POST crea cliente (price_list_id opcional asigna su lista de precios)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Clients"
+                ],
+                "summary": "Listar o crear clientes",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Filas por página (por defecto 50, máximo 500)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor devuelto en next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campo de orden: id, nombre, cedula",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc o desc",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Nombre contiene",
+                        "name": "nombre",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cédula empieza con",
+                        "name": "cedula",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Email contiene",
+                        "name": "email",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Cliente (solo POST)",
+                        "name": "client",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Client"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Page-ferreteria-inventario-ventas_internal_domain_Client"
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Client"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "GET lista clientes paginados con filtros, POST crea cliente (price_list_id opcional asigna su lista de precios)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Clients"
+                ],
+                "summary": "Listar o crear clientes",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Filas por página (por defecto 50, máximo 500)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor devuelto en next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campo de orden: id, nombre, cedula",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc o desc",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Nombre contiene",
+                        "name": "nombre",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cédula empieza con",
+                        "name": "cedula",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Email contiene",
+                        "name": "email",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Cliente (solo POST)",
+                        "name": "client",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Client"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Page-ferreteria-inventario-ventas_internal_domain_Client"
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Client"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "ferreteria-inventario-ventas_internal_domain.ABCClass": {
+            "type": "object",
+            "properties": {
+                "clase": {
+                    "type": "string"
+                },
+                "participacion_pct": {
+                    "description": "Del valor total",
+                    "type": "number"
+                },
+                "productos": {
+                    "type": "integer"
+                },
+                "productos_pct": {
+                    "description": "Del total de productos",
+                    "type": "number"
+                },
+                "valor": {
+                    "type": "number"
+                }
+            }
+        },
+        "ferreteria-inventario-ventas_internal_domain.ABCItem": {
+            "type": "object",
+            "properties": {
+                "acumulado_pct": {
+                    "description": "Participación acumulada hasta este producto",
+                    "type": "number"
+                },
+                "cantidad": {
+                    "description": "Unidades vendidas",
+                    "type": "integer"
+                },
+                "clase": {
+                    "type": "string"
+                },
+                "costo": {
+                    "description": "Costo de lo vendido",
+                    "type": "number"
+                },
+                "participacion_pct": {
+                    "description": "Valor / total * 100",
+                    "type": "number"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "producto": {
+                    "type": "string"
+                },
+                "sku": {
+                    "type": "string"
+                },
+                "total": {
+                    "description": "Monto vendido",
+                    "type": "number"
+                },
+                "utilidad": {
+                    "description": "Neto de las líneas - Costo",
+                    "type": "number"
+                },
+                "valor": {
+                    "type": "number"
+                },
+                "ventas": {
+                    "description": "Ventas en las que aparece",
+                    "type": "integer"
+                }
+            }
+        },
+        "ferreteria-inventario-ventas_internal_domain.ABCReport": {
+            "type": "object",
+            "properties": {
+                "clases": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.ABCClass"
+                    }
+                },
+                "desde": {
+                    "type": "string"
+                },
+                "hasta": {
+                    "type": "string"
+                },
+                "metric": {
+                    "type": "string"
+                },
+                "productos": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.ABCItem"
+                    }
+                },
+                "total": {
+                    "type": "number"
+                }
+            }
+        },
+        "ferreteria-inventario-ventas_internal_domain.AppliedPromotion": {
+            "type": "object",
+            "properties": {
+                "monto": {
+                    "type": "number"
+                },
+                "nombre": {
+                    "type": "string"
+                },
+                "promotion_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "ferreteria-inventario-ventas_internal_domain.AuditEntry": {
+            "type": "object",
+            "properties": {
+                "accion": {
+                    "type": "string"
+                },
+                "actor": {
+                    "type": "string"
+                },
+                "cambios": {
+                    "type": "object"
+                },
+                "entidad": {
+                    "type": "string"
+                },
+                "entidad_id": {
+                    "description": "null en acciones sobre varios registros",
+                    "type": "integer"
+                },
+                "fecha": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "request_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "ferreteria-inventario-ventas_internal_domain.BarcodeAssignment": {
+            "type": "object",
+            "properties": {
+                "barcode": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "sku": {
+                    "type": "string"
+                }
+            }
+        },
+        "ferreteria-inventario-ventas_internal_domain.Brand": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "nombre": {
+                    "type": "string"
+                }
+            }
+        },
+        "ferreteria-inventario-ventas_internal_domain.BulkPriceResult": {
+            "type": "object",
+            "properties": {
+                "aplicados": {
+                    "description": "Aplicados en el momento (desde vacío o pasado)",
+                    "type": "integer"
+                },
+                "cambios": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.PriceChange"
+                    }
+                },
+                "productos": {
+                    "description": "Productos alcanzados",
+                    "type": "integer"
+                }
+            }
+        },
+        "ferreteria-inventario-ventas_internal_domain.Category": {
+            "type": "object",
+            "properties": {
+                "hijos": {
+                    "description": "Subcategorías (solo en el árbol)",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Category"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "nombre": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "description": "nil = categoría raíz",
+                    "type": "integer"
+                },
+                "ruta": {
+                    "description": "Ej: \"Plomería \u003e Tuberías \u003e PVC\"",
+                    "type": "string"
+                }
+            }
+        },
+        "ferreteria-inventario-ventas_internal_domain.Client": {
+            "type": "object",
+            "properties": {
+                "cedula": {
+                    "description": "Número de cédula (único)",
+                    "type": "string"
+                },
+                "email": {
+                    "description": "Correo electrónico",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Identificador único en la base de datos",
+                    "type": "integer"
+                },
+                "lista_precios": {
+                    "description": "Nombre de la lista (solo lectura)",
+                    "type": "string"
+                },
+                "nombre": {
+                    "description": "Nombre completo del cliente",
+                    "type": "string"
+                },
+                "price_list_id": {
+                    "description": "Lista de precios (sin lista paga el precio del producto)",
+                    "type": "integer"
+                }
+            }
+        },
+        "ferreteria-inventario-ventas_internal_domain.ClientHistory": {
+            "type": "object",
+            "properties": {
+                "cliente": {
+                    "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Client"
+                },
+                "compras": {
+                    "description": "Cabeceras paginadas, más recientes primero",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Page-ferreteria-inventario-ventas_internal_domain_Sale"
+                        }
+                    ]
+                },
+                "primera_compra": {
+                    "type": "string"
+                },
+                "productos": {
+                    "description": "Más comprados primero (por monto)",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.ClientProduct"
+                    }
+                },
+                "ticket_promedio": {
+                    "type": "number"
+                },
+                "total": {
+                    "type": "number"
+                },
+                "ultima_compra": {
+                    "type": "string"
+                },
+                "unidades": {
+                    "type": "integer"
+                },
+                "ventas": {
+                    "type": "integer"
+                }
+            }
+        },
+        "ferreteria-inventario-ventas_internal_domain.ClientProduct": {
+            "type": "object",
+            "properties": {
+                "cantidad": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "producto": {
+                    "type": "string"
+                },
+                "sku": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "number"
+                },
+                "ultima_compra": {
+                    "type": "string"
+                },
+                "ventas": {
+                    "description": "Compras en las que aparece",
+                    "type": "integer"
+                }
+            }
+        },
+        "ferreteria-inventario-ventas_internal_domain.Dashboard": {
+            "type": "object",
+            "properties": {
+                "errores": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "generado": {
+                    "type": "string"
+                },
+                "hoy": {
+                    "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.DashboardPeriod"
+                },
+                "mes": {
+                    "description": "Desde el día 1",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.DashboardPeriod"
+                        }
+                    ]
+                },
+                "semana": {
+                    "description": "Desde el lunes",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.DashboardPeriod"
+                        }
+                    ]
+                },
+                "stock_bajo": {
+                    "description": "Productos con stock \u003c UmbralStock",
+                    "type": "integer"
+                },
+                "top_productos": {
+                    "description": "Más vendidos del mes por monto",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.TopProduct"
+                    }
+                },
+                "umbral_stock": {
+                    "type": "integer"
+                }
+            }
+        },
+        "ferreteria-inventario-ventas_internal_domain.DashboardPeriod": {
+            "type": "object",
+            "properties": {
+                "actual": {
+                    "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.SalesTotals"
+                },
+                "anterior": {
+                    "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.SalesTotals"
+                },
+                "anterior_desde": {
+                    "type": "string"
+                },
+                "anterior_hasta": {
+                    "type": "string"
+                },
+                "desde": {
+                    "type": "string"
+                },
+                "hasta": {
+                    "type": "string"
+                },
+                "variacion_pct": {
+                    "description": "Del total cobrado; null si el anterior es cero",
+                    "type": "number"
+                }
+            }
+        },
+        "ferreteria-inventario-ventas_internal_domain.DeadStockReport": {
+            "type": "object",
+            "properties": {
+                "capital": {
+                    "type": "number"
+                },
+                "desde": {
+                    "type": "string"
+                },
+                "productos": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.DeadStockRow"
+                    }
+                },
+                "unidades": {
+                    "type": "integer"
+                }
+            }
+        },
+        "ferreteria-inventario-ventas_internal_domain.DeadStockRow": {
+            "type": "object",
+            "properties": {
+                "capital": {
+                    "description": "Stock * Costo",
+                    "type": "number"
+                },
+                "categoria": {
+                    "type": "string"
+                },
+                "costo": {
+                    "description": "Costo promedio actual",
+                    "type": "number"
+                },
+                "dias_sin_venta": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "producto": {
+                    "type": "string"
+                },
+                "sku": {
+                    "type": "string"
+                },
+                "stock": {
+                    "type": "integer"
+                },
+                "ultima_venta": {
+                    "description": "null = nunca se vendió",
+                    "type": "string"
+                }
+            }
+        },
+        "ferreteria-inventario-ventas_internal_domain.DiscountApproval": {
+            "type": "object",
+            "properties": {
+                "creado": {
+                    "type": "string"
+                },
+                "descuento_max": {
+                    "type": "number"
+                },
+                "expira": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "supervisor": {
+                    "type": "string"
+                },
+                "token": {
+                    "description": "Solo al crearlo (se guarda su hash)",
+                    "type": "string"
+                }
+            }
+        },
+        "ferreteria-inventario-ventas_internal_domain.DiscountCredentials": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "usuario": {
+                    "type": "string"
+                }
+            }
+        },
+        "ferreteria-inventario-ventas_internal_domain.DiscountReport": {
+            "type": "object",
+            "properties": {
+                "cajeros": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.DiscountReportRow"
+                    }
+                },
+                "desde": {
+                    "type": "string"
+                },
+                "hasta": {
+                    "type": "string"
+                },
+                "totales": {
+                    "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.DiscountReportRow"
+                }
+            }
+        },
+        "ferreteria-inventario-ventas_internal_domain.DiscountReportRow": {
+            "type": "object",
+            "properties": {
+                "autorizadas": {
+                    "description": "Ventas que necesitaron un supervisor",
+                    "type": "integer"
+                },
+                "bruto": {
+                    "description": "Suma de las líneas de sus ventas",
+                    "type": "number"
+                },
+                "cajero": {
+                    "description": "Vacío = ventas sin sesión",
+                    "type": "string"
+                },
+                "descuentos": {
+                    "description": "Cantidad de descuentos manuales",
+                    "type": "integer"
+                },
+                "monto": {
+                    "type": "number"
+                },
+                "monto_autorizado": {
+                    "description": "Descuentos de esas ventas",
+                    "type": "number"
+                },
+                "porcentaje": {
+                    "description": "Monto / Bruto * 100",
+                    "type": "number"
+                },
+                "ventas": {
+                    "type": "integer"
+                },
+                "ventas_con_descuento": {
+                    "description": "Ventas con al menos un descuento manual",
+                    "type": "integer"
+                }
+            }
+        },
+        "ferreteria-inventario-ventas_internal_domain.GroupSales": {
+            "type": "object",
+            "properties": {
+                "cantidad": {
+                    "description": "Unidades vendidas",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "nombre": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "description": "Solo categorías",
+                    "type": "integer"
+                },
+                "total": {
+                    "description": "Monto vendido",
+                    "type": "number"
+                }
+            }
+        },
+        "ferreteria-inventario-ventas_internal_domain.HeatmapCell": {
+            "type": "object",
+            "properties": {
+                "dia": {
+                    "description": "0 = lunes ... 6 = domingo",
+                    "type": "integer"
+                },
+                "hora": {
+                    "description": "0 a 23",
+                    "type": "integer"
+                },
+                "mediana_total": {
+                    "type": "number"
+                },
+                "mediana_ventas": {
+                    "type": "number"
+                },
+                "ocurrencias": {
+                    "type": "integer"
+                },
+                "promedio_total": {
+                    "type": "number"
+                },
+                "promedio_ventas": {
+                    "type": "number"
+                },
+                "total": {
+                    "type": "number"
+                },
+                "ventas": {
+                    "type": "integer"
+                }
+            }
+        },
+        "ferreteria-inventario-ventas_internal_domain.ImportReport": {
+            "type": "object",
+            "properties": {
+                "actualizados": {
+                    "description": "Productos existentes modificados",
+                    "type": "integer"
+                },
+                "creados": {
+                    "description": "Productos nuevos",
+                    "type": "integer"
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "errores": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.RowError"
+                    }
+                },
+                "filas": {
+                    "description": "Filas de datos leídas",
+                    "type": "integer"
+                },
+                "guardado": {
+                    "description": "true si los cambios se confirmaron",
+                    "type": "boolean"
+                }
+            }
+        },
+        "ferreteria-inventario-ventas_internal_domain.InventoryValuation": {
+            "type": "object",
+            "properties": {
+                "categorias": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.ValuationCategory"
+                    }
+                },
+                "fecha": {
+                    "description": "Momento valorizado",
+                    "type": "string"
+                },
+                "totales": {
+                    "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.ValuationTotals"
+                }
+            }
+        },
+        "ferreteria-inventario-ventas_internal_domain.ManualDiscount": {
+            "type": "object",
+            "properties": {
+                "aplicado": {
+                    "description": "Monto descontado (solo lectura)",
+                    "type": "number"
+                },
+                "monto": {
+                    "type": "number"
+                },
+                "motivo": {
+                    "type": "string"
+                },
+                "porcentaje": {
+                    "type": "number"
+                }
+            }
+        },
+        "ferreteria-inventario-ventas_internal_domain.MarginRow": {
+            "type": "object",
+            "properties": {
+                "cantidad": {
+                    "description": "Unidades vendidas",
+                    "type": "integer"
+                },
+                "costo": {
+                    "type": "number"
+                },
+                "fecha": {
+                    "description": "Solo por venta",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "ingresos": {
+                    "type": "number"
+                },
+                "margen_pct": {
+                    "description": "Utilidad / Ingresos * 100",
+                    "type": "number"
+                },
+                "nombre": {
+                    "description": "Producto, categoría o cliente de la venta",
+                    "type": "string"
+                },
+                "utilidad": {
+                    "description": "Ingresos - Costo",
+                    "type": "number"
+                }
+            }
+        },
+        "ferreteria-inventario-ventas_internal_domain.Page-ferreteria-inventario-ventas_internal_domain_AuditEntry": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.AuditEntry"
+                    }
+                },
+                "next_cursor": {
+                    "description": "Vacío cuando no hay más páginas",
+                    "type": "string"
+                },
+                "total": {
+                    "description": "Total de filas que cumplen los filtros",
+                    "type": "integer"
+                }
+            }
+        },
+        "ferreteria-inventario-ventas_internal_domain.Page-ferreteria-inventario-ventas_internal_domain_Client": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Client"
+                    }
+                },
+                "next_cursor": {
+                    "description": "Vacío cuando no hay más páginas",
+                    "type": "string"
+                },
+                "total": {
+                    "description": "Total de filas que cumplen los filtros",
+                    "type": "integer"
+                }
+            }
+        },
+        "ferreteria-inventario-ventas_internal_domain.Page-ferreteria-inventario-ventas_internal_domain_PriceChange": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.PriceChange"
+                    }
+                },
+                "next_cursor": {
+                    "description": "Vacío cuando no hay más páginas",
+                    "type": "string"
+                },
+                "total": {
+                    "description": "Total de filas que cumplen los filtros",
+                    "type": "integer"
+                }
+            }
+        },
+        "ferreteria-inventario-ventas_internal_domain.Page-ferreteria-inventario-ventas_internal_domain_Product": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Product"
+                    }
+                },
+                "next_cursor": {
+                    "description": "Vacío cuando no hay más páginas",
+                    "type": "string"
+                },
+                "total": {
+                    "description": "Total de filas que cumplen los filtros",
+                    "type": "integer"
+                }
+            }
+        },
+        "ferreteria-inventario-ventas_internal_domain.Page-ferreteria-inventario-ventas_internal_domain_Receipt": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Receipt"
+                    }
+                },
+                "next_cursor": {
+                    "description": "Vacío cuando no hay más páginas",
+                    "type": "string"
+                },
+                "total": {
+                    "description": "Total de filas que cumplen los filtros",
+                    "type": "integer"
+                }
+            }
+        },
+        "ferreteria-inventario-ventas_internal_domain.Page-ferreteria-inventario-ventas_internal_domain_Sale": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.Sale"
+                    }
+                },
+                "next_cursor": {
+                    "description": "Vacío cuando no hay más páginas",
+                    "type": "string"
+                },
+                "total": {
+                    "description": "Total de filas que cumplen los filtros",
+                    "type": "integer"
+                }
+            }
+        },
+        "ferreteria-inventario-ventas_internal_domain.PriceAt": {
+            "type": "object",
+            "properties": {
+                "fecha": {
+                    "type": "string"
+                },
+                "precio": {
+                    "type": "number"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "vigente": {
+                    "description": "Entrada del historial que regía en esa fecha",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.PricePoint"
+                        }
+                    ]
+                }
+            }
+        },
+        "ferreteria-inventario-ventas_internal_domain.PriceChange": {
+            "type": "object",
+            "properties": {
+                "aplicado": {
+                    "type": "string"
+                },
+                "creado": {
+                    "type": "string"
+                },
+                "desde": {
+                    "description": "Vacío al crear: se aplica ya",
+                    "type": "string"
+                },
+                "estado": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "porcentaje": {
+                    "type": "number"
+                },
+                "precio": {
+                    "type": "number"
+                },
+                "precio_anterior": {
+                    "description": "Al aplicarse",
+                    "type": "number"
+                },
+                "precio_nuevo": {
+                    "description": "Al aplicarse",
+                    "type": "number"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "producto": {
+                    "description": "Solo lectura",
+                    "type": "string"
+                }
+            }
+        },
+        "ferreteria-inventario-ventas_internal_domain.PriceHistory": {
+            "type": "object",
+            "properties": {
+                "historial": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.PricePoint"
+                    }
+                },
+                "precio": {
+                    "description": "Precio actual",
+                    "type": "number"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "producto": {
+                    "type": "string"
+                },
+                "programados": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.PriceChange"
+                    }
+                }
+            }
+        },
+        "ferreteria-inventario-ventas_internal_domain.PriceList": {
+            "type": "object",
+            "properties": {
+                "clientes": {
+                    "description": "Clientes asignados (solo lectura)",
+                    "type": "integer"
+                },
+                "descripcion": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "nombre": {
+                    "type": "string"
+                },
+                "reglas": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.PriceListRule"
+                    }
+                }
+            }
+        },
+        "ferreteria-inventario-ventas_internal_domain.PriceListRule": {
+            "type": "object",
+            "properties": {
+                "cantidad_min": {
+                    "description": "1 si no se indica",
+                    "type": "integer"
+                },
+                "categoria": {
+                    "description": "Solo lectura",
+                    "type": "string"
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "porcentaje": {
+                    "type": "number"
+                },
+                "precio": {
+                    "type": "number"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "producto": {
+                    "description": "Solo lectura",
+                    "type": "string"
+                }
+            }
+        },
+        "ferreteria-inventario-ventas_internal_domain.PricePoint": {
+            "type": "object",
+            "properties": {
+                "change_id": {
+                    "description": "Cambio programado que lo aplicó",
+                    "type": "integer"
+                },
+                "desde": {
+                    "type": "string"
+                },
+                "origen": {
+                    "type": "string"
+                },
+                "precio": {
+                    "type": "number"
+                }
+            }
+        },
+        "ferreteria-inventario-ventas_internal_domain.Product": {
+            "type": "object",
+            "properties": {
+                "barcodes": {
+                    "description": "Códigos EAN-13 / UPC-A / EAN-8",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "brand_id": {
+                    "description": "Marca; al actualizar, nil la conserva y 0 la quita",
+                    "type": "integer"
+                },
+                "categoria": {
+                    "description": "Nombre de la categoría (solo lectura)",
+                    "type": "string"
+                },
+                "category_id": {
+                    "description": "Categoría (hoja o intermedia); al actualizar, nil la conserva y 0 la quita",
+                    "type": "integer"
+                },
+                "costo": {
+                    "description": "Costo promedio ponderado (se recalcula en cada recepción)",
+                    "type": "number"
+                },
+                "id": {
+                    "description": "Identificador único en la base de datos",
+                    "type": "integer"
+                },
+                "marca": {
+                    "description": "Nombre de la marca (solo lectura)",
+                    "type": "string"
+                },
+                "nombre": {
+                    "description": "Nombre del producto",
+                    "type": "string"
+                },
+                "precio": {
+                    "description": "Precio unitario del producto",
+                    "type": "number"
+                },
+                "sku": {
+                    "description": "Código interno único (se genera si viene vacío)",
+                    "type": "string"
+                },
+                "stock": {
+                    "description": "Cantidad disponible en inventario",
+                    "type": "integer"
+                }
+            }
+        },
+        "ferreteria-inventario-ventas_internal_domain.Promotion": {
+            "type": "object",
+            "properties": {
+                "activa": {
+                    "type": "boolean"
+                },
+                "acumulable": {
+                    "type": "boolean"
+                },
+                "cantidad_min": {
+                    "description": "porcentaje: unidades mínimas de la línea",
+                    "type": "integer"
+                },
+                "compra": {
+                    "description": "nxm: unidades que se pagan (compra 3...)",
+                    "type": "integer"
+                },
+                "desde": {
+                    "type": "string"
+                },
+                "gratis": {
+                    "description": "nxm: ...y gratis 1",
+                    "type": "integer"
+                },
+                "hasta": {
+                    "description": "Exclusivo; nil = sin fin",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "monto": {
+                    "description": "monto: descuento fijo",
+                    "type": "number"
+                },
+                "monto_min": {
+                    "description": "monto: compra mínima de los productos alcanzados",
+                    "type": "number"
+                },
+                "nombre": {
+                    "type": "string"
+                },
+                "objetivos": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.PromotionTarget"
+                    }
+                },
+                "porcentaje": {
+                    "description": "porcentaje: 0 a 100",
+                    "type": "number"
+                },
+                "prioridad": {
+                    "description": "Mayor primero",
+                    "type": "integer"
+                },
+                "tipo": {
+                    "description": "porcentaje, nxm o monto",
+                    "type": "string"
+                }
+            }
+        },
+        "ferreteria-inventario-ventas_internal_domain.PromotionTarget": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "nombre": {
+                    "description": "Solo lectura",
+                    "type": "string"
+                },
+                "tipo": {
+                    "description": "product, category o brand",
+                    "type": "string"
+                }
+            }
+        },
+        "ferreteria-inventario-ventas_internal_domain.RFMReport": {
+            "type": "object",
+            "properties": {
+                "clientes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.RFMRow"
+                    }
+                },
+                "desde": {
+                    "type": "string"
+                },
+                "hasta": {
+                    "type": "string"
+                },
+                "segmentos": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.RFMSegment"
+                    }
+                }
+            }
+        },
+        "ferreteria-inventario-ventas_internal_domain.RFMRow": {
+            "type": "object",
+            "properties": {
+                "cedula": {
+                    "type": "string"
+                },
+                "client_id": {
+                    "type": "integer"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "f": {
+                    "type": "integer"
+                },
+                "frecuencia": {
+                    "description": "Compras en el rango",
+                    "type": "integer"
+                },
+                "m": {
+                    "type": "integer"
+                },
+                "monto": {
+                    "description": "Total comprado en el rango",
+                    "type": "number"
+                },
+                "nombre": {
+                    "type": "string"
+                },
+                "r": {
+                    "type": "integer"
+                },
+                "recencia_dias": {
+                    "description": "Días desde la última compra",
+                    "type": "integer"
+                },
+                "segmento": {
+                    "type": "string"
+                },
+                "ultima_compra": {
+                    "type": "string"
+                }
+            }
+        },
+        "ferreteria-inventario-ventas_internal_domain.RFMSegment": {
+            "type": "object",
+            "properties": {
+                "clientes": {
+                    "type": "integer"
+                },
+                "monto": {
+                    "type": "number"
+                },
+                "segmento": {
+                    "type": "string"
+                }
+            }
+        },
+        "ferreteria-inventario-ventas_internal_domain.Receipt": {
+            "type": "object",
+            "properties": {
+                "fecha": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.ReceiptItem"
+                    }
+                },
+                "nota": {
+                    "type": "string"
+                },
+                "proveedor": {
+                    "type": "string"
+                },
+                "total": {
+                    "description": "Suma de cantidad * costo unitario",
+                    "type": "number"
+                }
+            }
+        },
+        "ferreteria-inventario-ventas_internal_domain.ReceiptItem": {
+            "type": "object",
+            "properties": {
+                "cantidad": {
+                    "type": "integer"
+                },
+                "costo_anterior": {
+                    "description": "Costo promedio antes de la recepción (solo al crear)",
+                    "type": "number"
+                },
+                "costo_nuevo": {
+                    "description": "Costo promedio después de la recepción (solo al crear)",
+                    "type": "number"
+                },
+                "costo_unitario": {
+                    "type": "number"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "producto": {
+                    "description": "Solo lectura",
+                    "type": "string"
+                }
+            }
+        },
+        "ferreteria-inventario-ventas_internal_domain.Recommendation": {
+            "type": "object",
+            "properties": {
+                "confianza": {
+                    "description": "Ventas juntos / ventas del producto consultado",
+                    "type": "number"
+                },
+                "lift": {
+                    "description": "\u003e 1: se compran juntos más de lo esperado por azar",
+                    "type": "number"
+                },
+                "precio": {
+                    "type": "number"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "producto": {
+                    "type": "string"
+                },
+                "sku": {
+                    "type": "string"
+                },
+                "soporte": {
+                    "description": "Ventas juntos / total de ventas",
+                    "type": "number"
+                },
+                "stock": {
+                    "type": "integer"
+                },
+                "ventas_juntos": {
+                    "description": "Ventas con los dos productos",
+                    "type": "integer"
+                }
+            }
+        },
+        "ferreteria-inventario-ventas_internal_domain.ReorderReport": {
+            "type": "object",
+            "properties": {
+                "alpha": {
+                    "type": "number"
+                },
+                "cobertura_dias": {
+                    "type": "integer"
+                },
+                "desde": {
+                    "type": "string"
+                },
+                "hasta": {
+                    "type": "string"
+                },
+                "metodo": {
+                    "type": "string"
+                },
+                "nivel_servicio": {
+                    "type": "number"
+                },
+                "plazo_dias": {
+                    "type": "integer"
+                },
+                "productos": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.ReorderRow"
+                    }
+                },
+                "temporada": {
+                    "type": "integer"
+                },
+                "ventana": {
+                    "type": "integer"
+                }
+            }
+        },
+        "ferreteria-inventario-ventas_internal_domain.ReorderRow": {
+            "type": "object",
+            "properties": {
+                "costo_sugerido": {
+                    "type": "number"
+                },
+                "demanda_plazo": {
+                    "description": "Demanda esperada durante el plazo de entrega",
+                    "type": "number"
+                },
+                "desvio_diario": {
+                    "type": "number"
+                },
+                "dias_cobertura": {
+                    "description": "Stock / demanda diaria (null sin demanda)",
+                    "type": "number"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "producto": {
+                    "type": "string"
+                },
+                "promedio_diario": {
+                    "description": "Demanda diaria estimada",
+                    "type": "number"
+                },
+                "punto_reorden": {
+                    "description": "DemandaPlazo + StockSeguridad",
+                    "type": "number"
+                },
+                "sku": {
+                    "type": "string"
+                },
+                "stock": {
+                    "type": "integer"
+                },
+                "stock_seguridad": {
+                    "type": "number"
+                },
+                "sugerido": {
+                    "description": "Unidades a pedir hoy",
+                    "type": "integer"
+                },
+                "unidades_periodo": {
+                    "description": "Vendidas en el historial",
+                    "type": "integer"
+                }
+            }
+        },
+        "ferreteria-inventario-ventas_internal_domain.Role": {
+            "type": "object",
+            "properties": {
+                "descuento_max": {
+                    "description": "% sobre lo que se cobra (0 a 100)",
+                    "type": "number"
+                },
+                "nombre": {
+                    "type": "string"
+                }
+            }
+        },
+        "ferreteria-inventario-ventas_internal_domain.RowError": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "fila": {
+                    "description": "Número de fila (desde 1)",
+                    "type": "integer"
+                }
+            }
+        },
+        "ferreteria-inventario-ventas_internal_domain.Sale": {
+            "type": "object",
+            "properties": {
+                "autorizado_por": {
+                    "description": "Supervisor que autorizó los descuentos manuales",
+                    "type": "string"
+                },
+                "cajero": {
+                    "description": "Usuario de la sesión que vendió",
+                    "type": "string"
+                },
+                "client_id": {
+                    "type": "integer"
+                },
+                "client_name": {
+                    "description": "👈 NUEVO",
+                    "type": "string"
+                },
+                "descuento": {
+                    "description": "Descuentos de las líneas + de la venta",
+                    "type": "number"
+                },
+                "descuento_manual": {
+                    "description": "Descuento del cajero sobre la venta",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.ManualDiscount"
+                        }
+                    ]
+                },
+                "fecha": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "impuesto": {
+                    "description": "Impuesto sobre el neto con la tasa de la tienda",
+                    "type": "number"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.SaleItem"
+                    }
+                },
+                "promociones": {
+                    "description": "Descuentos sobre la venta (no de una línea)",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.AppliedPromotion"
+                    }
+                },
+                "total": {
+                    "description": "Suma de subtotales - Descuento + Impuesto",
+                    "type": "number"
+                }
+            }
+        },
+        "ferreteria-inventario-ventas_internal_domain.SaleItem": {
+            "type": "object",
+            "properties": {
+                "cantidad": {
+                    "description": "Cantidad vendida",
+                    "type": "integer"
+                },
+                "costo_unitario": {
+                    "description": "Costo promedio del producto al momento de la venta",
+                    "type": "number"
+                },
+                "descuento": {
+                    "description": "Descuento de promociones en la línea",
+                    "type": "number"
+                },
+                "descuento_manual": {
+                    "description": "Descuento del cajero (incluido en Descuento)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.ManualDiscount"
+                        }
+                    ]
+                },
+                "lista_precios": {
+                    "description": "Nombre de la lista (solo lectura)",
+                    "type": "string"
+                },
+                "precio_unitario": {
+                    "description": "Precio al momento de la venta",
+                    "type": "number"
+                },
+                "price_list_id": {
+                    "description": "Lista de precios que definió el precio (nil = precio del producto)",
+                    "type": "integer"
+                },
+                "product_id": {
+                    "description": "ID del producto vendido",
+                    "type": "integer"
+                },
+                "promociones": {
+                    "description": "Promociones que lo produjeron",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.AppliedPromotion"
+                    }
+                },
+                "subtotal": {
+                    "description": "Cantidad * PrecioUnitario (antes de descuentos)",
+                    "type": "number"
+                }
+            }
         },
-        "ferreteria-inventario-ventas_internal_domain.Sale": {
+        "ferreteria-inventario-ventas_internal_domain.SalesHeatmap": {
             "type": "object",
             "properties": {
-                "client_id": {
-                    "description": "Cliente que realiza la compra",
-                    "type": "integer"
+                "desde": {
+                    "type": "string"
                 },
-                "fecha": {
-                    "description": "Fecha de la venta",
+                "dias": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "hasta": {
                     "type": "string"
                 },
-                "id": {
-                    "description": "ID de la venta",
+                "matriz": {
+                    "description": "Matriz[dia][hora]",
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.HeatmapCell"
+                        }
+                    }
+                },
+                "total": {
+                    "type": "number"
+                },
+                "ventas": {
                     "type": "integer"
+                }
+            }
+        },
+        "ferreteria-inventario-ventas_internal_domain.SalesSummary": {
+            "type": "object",
+            "properties": {
+                "desde": {
+                    "type": "string"
                 },
-                "items": {
-                    "description": "Lista de productos vendidos",
+                "group_by": {
+                    "type": "string"
+                },
+                "hasta": {
+                    "type": "string"
+                },
+                "periodos": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.SaleItem"
+                        "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.SalesSummaryBucket"
                     }
                 },
+                "totales": {
+                    "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.SalesTotals"
+                }
+            }
+        },
+        "ferreteria-inventario-ventas_internal_domain.SalesSummaryBucket": {
+            "type": "object",
+            "properties": {
+                "bruto": {
+                    "type": "number"
+                },
+                "costo": {
+                    "type": "number"
+                },
+                "descuentos": {
+                    "type": "number"
+                },
+                "desde": {
+                    "type": "string"
+                },
+                "hasta": {
+                    "description": "Exclusivo",
+                    "type": "string"
+                },
+                "impuestos": {
+                    "type": "number"
+                },
+                "margen_pct": {
+                    "description": "Utilidad / Neto * 100",
+                    "type": "number"
+                },
+                "neto": {
+                    "type": "number"
+                },
+                "periodo": {
+                    "description": "2026-10-19, 2026-W43 o 2026-10",
+                    "type": "string"
+                },
+                "ticket_promedio": {
+                    "description": "Total / Ventas",
+                    "type": "number"
+                },
+                "total": {
+                    "type": "number"
+                },
+                "utilidad": {
+                    "type": "number"
+                },
+                "ventas": {
+                    "type": "integer"
+                }
+            }
+        },
+        "ferreteria-inventario-ventas_internal_domain.SalesTotals": {
+            "type": "object",
+            "properties": {
+                "bruto": {
+                    "type": "number"
+                },
+                "costo": {
+                    "type": "number"
+                },
+                "descuentos": {
+                    "type": "number"
+                },
+                "impuestos": {
+                    "type": "number"
+                },
+                "margen_pct": {
+                    "description": "Utilidad / Neto * 100",
+                    "type": "number"
+                },
+                "neto": {
+                    "type": "number"
+                },
+                "ticket_promedio": {
+                    "description": "Total / Ventas",
+                    "type": "number"
+                },
                 "total": {
-                    "description": "Total de la venta",
                     "type": "number"
+                },
+                "utilidad": {
+                    "type": "number"
+                },
+                "ventas": {
+                    "type": "integer"
                 }
             }
         },
-        "ferreteria-inventario-ventas_internal_domain.SaleItem": {
+        "ferreteria-inventario-ventas_internal_domain.Session": {
+            "type": "object",
+            "properties": {
+                "creado": {
+                    "type": "string"
+                },
+                "expira": {
+                    "type": "string"
+                },
+                "token": {
+                    "description": "Solo al crearla (se guarda su hash)",
+                    "type": "string"
+                },
+                "usuario": {
+                    "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.User"
+                }
+            }
+        },
+        "ferreteria-inventario-ventas_internal_domain.TopProduct": {
             "type": "object",
             "properties": {
                 "cantidad": {
-                    "description": "Cantidad vendida",
+                    "description": "Unidades vendidas",
                     "type": "integer"
                 },
-                "precio_unitario": {
-                    "description": "Precio al momento de la venta",
+                "costo": {
+                    "description": "Costo de lo vendido",
                     "type": "number"
                 },
                 "product_id": {
-                    "description": "ID del producto vendido",
                     "type": "integer"
                 },
+                "producto": {
+                    "type": "string"
+                },
+                "sku": {
+                    "type": "string"
+                },
+                "total": {
+                    "description": "Monto vendido",
+                    "type": "number"
+                },
+                "utilidad": {
+                    "description": "Neto de las líneas - Costo",
+                    "type": "number"
+                },
+                "ventas": {
+                    "description": "Ventas en las que aparece",
+                    "type": "integer"
+                }
+            }
+        },
+        "ferreteria-inventario-ventas_internal_domain.User": {
+            "type": "object",
+            "properties": {
+                "activo": {
+                    "type": "boolean"
+                },
+                "creado": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "nombre": {
+                    "type": "string"
+                },
+                "password": {
+                    "description": "Solo al crear o cambiarla; nunca se devuelve",
+                    "type": "string"
+                },
+                "rol": {
+                    "type": "string"
+                },
+                "usuario": {
+                    "description": "Único, sin distinguir mayúsculas",
+                    "type": "string"
+                }
+            }
+        },
+        "ferreteria-inventario-ventas_internal_domain.ValuationCategory": {
+            "type": "object",
+            "properties": {
+                "categoria": {
+                    "type": "string"
+                },
+                "category_id": {
+                    "description": "null = sin categoría",
+                    "type": "integer"
+                },
+                "productos": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.ValuationRow"
+                    }
+                },
                 "subtotal": {
+                    "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.ValuationTotals"
+                }
+            }
+        },
+        "ferreteria-inventario-ventas_internal_domain.ValuationRow": {
+            "type": "object",
+            "properties": {
+                "cantidad": {
+                    "type": "integer"
+                },
+                "categoria": {
+                    "description": "Ruta completa (Plomería \u003e Tuberías)",
+                    "type": "string"
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "costo_unitario": {
+                    "description": "Costo promedio a la fecha",
+                    "type": "number"
+                },
+                "precio_unitario": {
+                    "description": "Precio de venta actual",
+                    "type": "number"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "producto": {
+                    "type": "string"
+                },
+                "sku": {
+                    "type": "string"
+                },
+                "valor_costo": {
+                    "description": "Cantidad * CostoUnitario",
+                    "type": "number"
+                },
+                "valor_venta": {
                     "description": "Cantidad * PrecioUnitario",
                     "type": "number"
                 }
             }
+        },
+        "ferreteria-inventario-ventas_internal_domain.ValuationTotals": {
+            "type": "object",
+            "properties": {
+                "cantidad": {
+                    "type": "integer"
+                },
+                "productos": {
+                    "type": "integer"
+                },
+                "valor_costo": {
+                    "type": "number"
+                },
+                "valor_venta": {
+                    "type": "number"
+                }
+            }
+        },
+        "internal_transport_http_http_handlers.bulkPriceInput": {
+            "type": "object",
+            "properties": {
+                "category_id": {
+                    "type": "integer"
+                },
+                "desde": {
+                    "description": "YYYY-MM-DD (inicio del día) o RFC3339; vacío = ya",
+                    "type": "string"
+                },
+                "porcentaje": {
+                    "type": "number"
+                },
+                "proveedor": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_transport_http_http_handlers.clientPriceListInput": {
+            "type": "object",
+            "properties": {
+                "price_list_id": {
+                    "description": "null quita la lista",
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_transport_http_http_handlers.discountApprovalInput": {
+            "type": "object",
+            "properties": {
+                "descuento_max": {
+                    "description": "0 = el máximo del rol",
+                    "type": "number"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "usuario": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_transport_http_http_handlers.priceChangeInput": {
+            "type": "object",
+            "properties": {
+                "desde": {
+                    "description": "YYYY-MM-DD (inicio del día) o RFC3339; vacío = ya",
+                    "type": "string"
+                },
+                "porcentaje": {
+                    "type": "number"
+                },
+                "precio": {
+                    "type": "number"
+                },
+                "product_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_transport_http_http_handlers.saleInput": {
+            "type": "object",
+            "properties": {
+                "autorizacion": {
+                    "description": "Supervisor, si el descuento supera el límite del cajero",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.DiscountCredentials"
+                        }
+                    ]
+                },
+                "client_id": {
+                    "type": "integer"
+                },
+                "descuento_manual": {
+                    "description": "Descuento sobre toda la venta",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.ManualDiscount"
+                        }
+                    ]
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ferreteria-inventario-ventas_internal_domain.SaleItem"
+                    }
+                }
+            }
+        },
+        "internal_transport_http_http_handlers.sessionInput": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "type": "string"
+                },
+                "usuario": {
+                    "type": "string"
+                }
+            }
         }
     }
 }`
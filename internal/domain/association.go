@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+// AssociationParams son los parámetros del cálculo de productos que se compran juntos.
+type AssociationParams struct {
+	Desde     time.Time // Ventas consideradas desde esta fecha
+	MinVentas int       // Mínimo de ventas con el par para guardarlo (descarta coincidencias)
+}
+
+// AssociationStats resume un cálculo de asociaciones.
+type AssociationStats struct {
+	Desde     time.Time `json:"desde"`
+	Ventas    int       `json:"ventas"` // Ventas (canastas) analizadas
+	Pares     int       `json:"pares"`  // Pares guardados (en los dos sentidos)
+	Calculado time.Time `json:"calculado"`
+}
+
+// Recommendation es un producto que suele comprarse junto con otro.
+type Recommendation struct {
+	ProductID int64   `json:"product_id"`
+	SKU       string  `json:"sku"`
+	Producto  string  `json:"producto"`
+	Precio    float64 `json:"precio"`
+	Stock     int     `json:"stock"`
+	Ventas    int     `json:"ventas_juntos"` // Ventas con los dos productos
+	Soporte   float64 `json:"soporte"`       // Ventas juntos / total de ventas
+	Confianza float64 `json:"confianza"`     // Ventas juntos / ventas del producto consultado
+	Lift      float64 `json:"lift"`          // > 1: se compran juntos más de lo esperado por azar
+}
@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// Acciones registradas en la auditoría.
+const (
+	AuditCreate   = "create"
+	AuditUpdate   = "update"
+	AuditDelete   = "delete"
+	AuditImport   = "import"   // Importación masiva de productos
+	AuditBarcodes = "barcodes" // Asignación de códigos de barras en bloque
+)
+
+// Entidades auditadas.
+const (
+	EntityProduct   = "product"
+	EntityClient    = "client"
+	EntitySale      = "sale"
+	EntityPrice     = "price_change" // Cambio de precio programado
+	EntityList      = "price_list"   // Lista de precios
+	EntityPromotion = "promotion"
+	EntityUser      = "user"
+	EntityRole      = "role"
+)
+
+// AuditEntry es un registro de la auditoría. Solo se agregan, nunca se modifican.
+// Cambios tiene solo los campos que cambiaron: {"precio": {"antes": 5, "despues": 6}}.
+// Al crear, antes es null; al eliminar, despues es null.
+type AuditEntry struct {
+	ID        int64           `json:"id"`
+	Fecha     time.Time       `json:"fecha"`
+	Actor     string          `json:"actor"`
+	RequestID string          `json:"request_id,omitempty"`
+	Accion    string          `json:"accion"`
+	Entidad   string          `json:"entidad"`
+	EntidadID *int64          `json:"entidad_id,omitempty"` // null en acciones sobre varios registros
+	Cambios   json.RawMessage `json:"cambios" swaggertype:"object"`
+}
+
+// AuditFunc arma el registro de auditoría de un cambio: id del registro (0 en acciones
+// sobre varios), cómo era (nil al crear) y cómo quedó (nil al eliminar).
+// Los repositorios la llaman dentro de la transacción del cambio y guardan el registro
+// en esa misma transacción: si la auditoría falla, el cambio se deshace.
+type AuditFunc func(id int64, before, after any) (*AuditEntry, error)
+
+// FieldChange es el valor de un campo antes y después del cambio.
+type FieldChange struct {
+	Antes   any `json:"antes"`
+	Despues any `json:"despues"`
+}
+
+// AuditFilter filtra el listado de la auditoría.
+type AuditFilter struct {
+	Actor     string
+	Accion    string
+	Entidad   string
+	EntidadID int64
+	RequestID string
+	Desde     time.Time // Inclusive (cero = sin límite)
+	Hasta     time.Time // Exclusivo (cero = sin límite)
+}
+
+// AuditActor identifica quién hizo un cambio y en qué petición.
+type AuditActor struct {
+	Actor     string
+	RequestID string
+}
+
+type auditActorKey struct{}
+
+// WithAuditActor devuelve un contexto que lleva el actor para la auditoría.
+func WithAuditActor(ctx context.Context, a AuditActor) context.Context {
+	return context.WithValue(ctx, auditActorKey{}, a)
+}
+
+// AuditActorFrom devuelve el actor del contexto (vacío si no tiene).
+func AuditActorFrom(ctx context.Context) AuditActor {
+	a, _ := ctx.Value(auditActorKey{}).(AuditActor)
+	return a
+}
@@ -0,0 +1,53 @@
+package domain
+
+import "strings"
+
+// BarcodeAssignment asigna un código de barras a un producto,
+// identificado por ID o por SKU.
+type BarcodeAssignment struct {
+	ProductID int64  `json:"product_id,omitempty"`
+	SKU       string `json:"sku,omitempty"`
+	Barcode   string `json:"barcode"`
+}
+
+// NormalizeBarcode valida un código EAN-13, UPC-A (12) o EAN-8 con su dígito verificador.
+// Devuelve el código normalizado: los UPC-A se convierten a EAN-13 agregando un 0,
+// así el mismo producto se encuentra escaneado como UPC o como EAN.
+func NormalizeBarcode(code string) (string, bool) {
+
+	code = strings.TrimSpace(code)
+
+	switch len(code) {
+	case 8, 13:
+	case 12:
+		code = "0" + code
+	default:
+		return "", false
+	}
+
+	for _, c := range code {
+		if c < '0' || c > '9' {
+			return "", false
+		}
+	}
+
+	if checkDigit(code[:len(code)-1]) != code[len(code)-1] {
+		return "", false
+	}
+
+	return code, true
+}
+
+// checkDigit calcula el dígito verificador GTIN (módulo 10):
+// desde la derecha, los dígitos en posición impar pesan 3 y los pares 1.
+func checkDigit(digits string) byte {
+	sum := 0
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if (len(digits)-1-i)%2 == 0 {
+			d *= 3
+		}
+		sum += d
+	}
+	return byte('0' + (10-sum%10)%10)
+}
@@ -0,0 +1,17 @@
+package domain
+
+// Category representa una categoría del catálogo.
+// Las categorías forman un árbol: Plomería > Tuberías > PVC.
+type Category struct {
+	ID       int64      `json:"id"`
+	Nombre   string     `json:"nombre"`
+	ParentID *int64     `json:"parent_id"`       // nil = categoría raíz
+	Ruta     string     `json:"ruta,omitempty"`  // Ej: "Plomería > Tuberías > PVC"
+	Hijos    []Category `json:"hijos,omitempty"` // Subcategorías (solo en el árbol)
+}
+
+// Brand representa una marca de productos (ej: Truper, Stanley).
+type Brand struct {
+	ID     int64  `json:"id"`
+	Nombre string `json:"nombre"`
+}
@@ -1,5 +1,7 @@
 package domain
 
+import "time"
+
 // Client representa un cliente de la ferretería.
 // Contiene los datos básicos necesarios para registrar ventas.
 type Client struct {
@@ -7,4 +9,31 @@ type Client struct {
 	Nombre string `json:"nombre"` // Nombre completo del cliente
 	Cedula string `json:"cedula"` // Número de cédula (único)
 	Email  string `json:"email"`  // Correo electrónico
+
+	PriceListID  *int64 `json:"price_list_id,omitempty"` // Lista de precios (sin lista paga el precio del producto)
+	ListaPrecios string `json:"lista_precios,omitempty"` // Nombre de la lista (solo lectura)
+}
+
+// ClientProduct es un producto comprado por un cliente.
+type ClientProduct struct {
+	ProductID    int64     `json:"product_id"`
+	SKU          string    `json:"sku"`
+	Producto     string    `json:"producto"`
+	Cantidad     int       `json:"cantidad"`
+	Total        float64   `json:"total"`
+	Ventas       int       `json:"ventas"` // Compras en las que aparece
+	UltimaCompra time.Time `json:"ultima_compra"`
+}
+
+// ClientHistory es el historial de compras de un cliente en un rango.
+type ClientHistory struct {
+	Cliente        Client          `json:"cliente"`
+	Ventas         int             `json:"ventas"`
+	Unidades       int             `json:"unidades"`
+	Total          float64         `json:"total"`
+	TicketPromedio float64         `json:"ticket_promedio"`
+	PrimeraCompra  *time.Time      `json:"primera_compra,omitempty"`
+	UltimaCompra   *time.Time      `json:"ultima_compra,omitempty"`
+	Productos      []ClientProduct `json:"productos"` // Más comprados primero (por monto)
+	Compras        Page[Sale]      `json:"compras"`   // Cabeceras paginadas, más recientes primero
 }
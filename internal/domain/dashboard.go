@@ -0,0 +1,39 @@
+package domain
+
+import "time"
+
+// Secciones del tablero. Se usan como claves de Dashboard.Errores.
+const (
+	DashboardToday    = "hoy"
+	DashboardWeek     = "semana"
+	DashboardMonth    = "mes"
+	DashboardLowStock = "stock_bajo"
+	DashboardTop      = "top_productos"
+)
+
+// DashboardPeriod son las ventas de un período en curso (desde su inicio hasta ahora)
+// comparadas con el mismo tramo del período anterior (ej: lunes a miércoles de la
+// semana pasada hasta la misma hora).
+type DashboardPeriod struct {
+	Desde         time.Time   `json:"desde"`
+	Hasta         time.Time   `json:"hasta"`
+	Actual        SalesTotals `json:"actual"`
+	AnteriorDesde time.Time   `json:"anterior_desde"`
+	AnteriorHasta time.Time   `json:"anterior_hasta"`
+	Anterior      SalesTotals `json:"anterior"`
+	VariacionPct  *float64    `json:"variacion_pct"` // Del total cobrado; null si el anterior es cero
+}
+
+// Dashboard reúne los indicadores de la pantalla principal.
+// Cada sección se consulta por separado: si una falla o tarda demasiado queda
+// en null y el motivo en Errores, y el resto se devuelve igual.
+type Dashboard struct {
+	Generado     time.Time         `json:"generado"`
+	Hoy          *DashboardPeriod  `json:"hoy"`
+	Semana       *DashboardPeriod  `json:"semana"` // Desde el lunes
+	Mes          *DashboardPeriod  `json:"mes"`    // Desde el día 1
+	UmbralStock  int               `json:"umbral_stock"`
+	StockBajo    *int              `json:"stock_bajo"`    // Productos con stock < UmbralStock
+	TopProductos []TopProduct      `json:"top_productos"` // Más vendidos del mes por monto
+	Errores      map[string]string `json:"errores,omitempty"`
+}
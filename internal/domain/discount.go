@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// ManualDiscount es un descuento que da el cajero en una línea o en toda la venta:
+// porcentaje o monto (no los dos), con el motivo. Se aplica después de las promociones,
+// sobre lo que queda por cobrar.
+type ManualDiscount struct {
+	Porcentaje float64 `json:"porcentaje,omitempty"`
+	Monto      float64 `json:"monto,omitempty"`
+	Motivo     string  `json:"motivo"`
+	Aplicado   float64 `json:"aplicado,omitempty"` // Monto descontado (solo lectura)
+}
+
+// NewSale son los datos para crear una venta. Los descuentos manuales de línea
+// van en cada item (DescuentoManual).
+type NewSale struct {
+	ClientID     int64
+	Items        []SaleItem
+	Descuento    *ManualDiscount      // Descuento manual sobre toda la venta
+	Autorizacion *DiscountCredentials // Supervisor, si los descuentos superan el límite del cajero
+	Auth         DiscountAuth         // Límites resueltos por el servicio con Autorizacion
+	TasaImpuesto float64              // Porcentaje de impuesto sobre el neto (lo pone el servicio)
+}
+
+// DiscountReportRow resume los descuentos manuales de un cajero en un rango de fechas.
+type DiscountReportRow struct {
+	Cajero          string  `json:"cajero"` // Vacío = ventas sin sesión
+	Ventas          int     `json:"ventas"`
+	Bruto           float64 `json:"bruto"`                // Suma de las líneas de sus ventas
+	VentasDescuento int     `json:"ventas_con_descuento"` // Ventas con al menos un descuento manual
+	Descuentos      int     `json:"descuentos"`           // Cantidad de descuentos manuales
+	Monto           float64 `json:"monto"`
+	Porcentaje      float64 `json:"porcentaje"`       // Monto / Bruto * 100
+	Autorizadas     int     `json:"autorizadas"`      // Ventas que necesitaron un supervisor
+	MontoAutorizado float64 `json:"monto_autorizado"` // Descuentos de esas ventas
+}
+
+// DiscountReport es el reporte de descuentos manuales por cajero.
+type DiscountReport struct {
+	Desde   time.Time           `json:"desde"`
+	Hasta   time.Time           `json:"hasta"`
+	Cajeros []DiscountReportRow `json:"cajeros"`
+	Totales DiscountReportRow   `json:"totales"`
+}
+
+// ApplyManualDiscounts calcula cuánto descuenta cada descuento manual (Aplicado):
+// los de línea sobre lo que queda por cobrar de la línea (remaining, mismo orden que
+// lines) y el de la venta sobre total (lo que se cobra después de las promociones)
+// menos los de línea. Devuelve el porcentaje que hay que autorizar: el mayor entre el
+// de cada línea y el de todos juntos sobre total.
+// ErrInvalidInput si los descuentos superan lo que queda por cobrar.
+func ApplyManualDiscounts(lines []*ManualDiscount, remaining []float64, sale *ManualDiscount, total float64) (float64, error) {
+
+	var required, sum float64
+
+	for i, d := range lines {
+		if d == nil {
+			continue
+		}
+		amount := d.amount(remaining[i])
+		if amount <= 0 || amount > remaining[i] {
+			return 0, ErrInvalidInput
+		}
+		d.Aplicado = amount
+		sum = cents(sum + amount)
+		required = math.Max(required, amount/remaining[i]*100)
+	}
+
+	if sale != nil {
+		amount := sale.amount(total - sum)
+		if amount <= 0 {
+			return 0, ErrInvalidInput
+		}
+		sale.Aplicado = amount
+		sum = cents(sum + amount)
+	}
+
+	if sum == 0 {
+		return 0, nil
+	}
+	if sum > total {
+		return 0, ErrInvalidInput
+	}
+
+	required = math.Max(required, sum/total*100)
+	return cents(required), nil
+}
+
+// amount es el monto del descuento sobre base, redondeado a centavos.
+func (d *ManualDiscount) amount(base float64) float64 {
+	if d.Porcentaje > 0 {
+		return cents(base * d.Porcentaje / 100)
+	}
+	return cents(d.Monto)
+}
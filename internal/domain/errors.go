@@ -9,4 +9,17 @@ var (
 	ErrInvalidInput      = errors.New("invalid input")      // Datos incorrectos
 	ErrConflict          = errors.New("conflict")           // Conflicto (ej: cédula repetida)
 	ErrInsufficientStock = errors.New("insufficient stock") // Stock insuficiente
+
+	ErrIdempotencyMismatch = errors.New("idempotency key reused with different request") // Llave repetida con otro cuerpo
+
+	ErrBadCredentials  = errors.New("invalid credentials")                  // Usuario, contraseña o token (de sesión o aprobación) inválidos
+	ErrDiscountLimit   = errors.New("discount exceeds the allowed maximum") // Descuento manual mayor al que se puede autorizar
+	ErrUnauthenticated = errors.New("authentication required")              // Hace falta iniciar sesión
+	ErrForbidden       = errors.New("forbidden")                            // El rol del usuario no lo permite
 )
+
+// RowError describe el error de una fila en una operación masiva.
+type RowError struct {
+	Row   int    `json:"fila"` // Número de fila (desde 1)
+	Error string `json:"error"`
+}
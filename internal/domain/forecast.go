@@ -0,0 +1,66 @@
+package domain
+
+import "time"
+
+// Métodos para estimar la demanda diaria.
+const (
+	ForecastSMA = "sma" // Promedio móvil simple de los últimos Window días
+	ForecastSES = "ses" // Suavizado exponencial simple con factor Alpha
+)
+
+// ReorderQuery son los parámetros del pronóstico de reposición.
+// Los campos en cero toman los valores por defecto del servicio.
+type ReorderQuery struct {
+	Days         int     // Días de historial (terminan ayer)
+	Method       string  // sma o ses
+	Window       int     // Días del promedio móvil
+	Alpha        float64 // Factor de suavizado (0 < Alpha <= 1)
+	Season       int     // Largo de la temporada en días (7 = semana); 0 = sin estacionalidad
+	LeadTime     int     // Días que tarda el proveedor en entregar
+	CoverDays    int     // Días de demanda que debe cubrir el pedido además del plazo
+	ServiceLevel float64 // Probabilidad de no quedarse sin stock durante el plazo (0.5 a 0.999)
+	CategoryID   int64   // Categoría y subcategorías (0 = todas)
+	OnlyReorder  bool    // Solo productos con cantidad sugerida > 0
+}
+
+// DemandSeries es el historial de unidades vendidas por día de un producto.
+type DemandSeries struct {
+	ProductID int64
+	SKU       string
+	Producto  string
+	Stock     int
+	Costo     float64
+	Alta      *time.Time // Creación del producto (nil si es anterior al registro de movimientos)
+	Unidades  []int      // Una posición por día del historial
+}
+
+// ReorderRow es el pronóstico de un producto.
+type ReorderRow struct {
+	ProductID       int64    `json:"product_id"`
+	SKU             string   `json:"sku"`
+	Producto        string   `json:"producto"`
+	Stock           int      `json:"stock"`
+	UnidadesPeriodo int      `json:"unidades_periodo"` // Vendidas en el historial
+	PromedioDiario  float64  `json:"promedio_diario"`  // Demanda diaria estimada
+	DesvioDiario    float64  `json:"desvio_diario"`
+	DiasCobertura   *float64 `json:"dias_cobertura"` // Stock / demanda diaria (null sin demanda)
+	DemandaPlazo    float64  `json:"demanda_plazo"`  // Demanda esperada durante el plazo de entrega
+	StockSeguridad  float64  `json:"stock_seguridad"`
+	PuntoReorden    float64  `json:"punto_reorden"` // DemandaPlazo + StockSeguridad
+	Sugerido        int      `json:"sugerido"`      // Unidades a pedir hoy
+	CostoSugerido   float64  `json:"costo_sugerido"`
+}
+
+// ReorderReport es el pronóstico de reposición con los parámetros usados.
+type ReorderReport struct {
+	Desde         time.Time    `json:"desde"`
+	Hasta         time.Time    `json:"hasta"`
+	Metodo        string       `json:"metodo"`
+	Ventana       int          `json:"ventana,omitempty"`
+	Alpha         float64      `json:"alpha,omitempty"`
+	Temporada     int          `json:"temporada,omitempty"`
+	PlazoDias     int          `json:"plazo_dias"`
+	CoberturaDias int          `json:"cobertura_dias"`
+	NivelServicio float64      `json:"nivel_servicio"`
+	Productos     []ReorderRow `json:"productos"`
+}
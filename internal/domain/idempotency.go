@@ -0,0 +1,12 @@
+package domain
+
+import "time"
+
+// IdempotencyKey relaciona la llave enviada por el cliente (header Idempotency-Key)
+// con la huella de la petición y la venta que se creó con ella.
+type IdempotencyKey struct {
+	Key         string    `json:"key"`
+	Fingerprint string    `json:"fingerprint"` // SHA-256 del cuerpo normalizado
+	SaleID      int64     `json:"sale_id"`
+	CreatedAt   time.Time `json:"created_at"`
+}
@@ -0,0 +1,26 @@
+package domain
+
+// ProductImportRow es una fila de un archivo de importación de productos.
+// Los campos nil (o vacíos en Categoria/Marca) no venían en el archivo:
+// al actualizar un producto existente se conserva su valor actual.
+type ProductImportRow struct {
+	Row       int      // Número de fila en el archivo (la cabecera es la fila 1)
+	Nombre    string   // Requerido para crear; también identifica al producto si no hay SKU
+	SKU       string   // Identifica al producto a actualizar
+	Stock     *int     //
+	Precio    *float64 //
+	Costo     *float64 // Costo unitario (reemplaza el promedio actual)
+	Barcodes  []string // nil = conservar; vacío = quitar todos
+	Categoria *string  // Ruta ("Herramientas > Manuales") o nombre de la categoría
+	Marca     *string  // Nombre de la marca
+}
+
+// ImportReport es el resultado de una importación.
+type ImportReport struct {
+	DryRun       bool       `json:"dry_run"`
+	Filas        int        `json:"filas"`        // Filas de datos leídas
+	Creados      int        `json:"creados"`      // Productos nuevos
+	Actualizados int        `json:"actualizados"` // Productos existentes modificados
+	Guardado     bool       `json:"guardado"`     // true si los cambios se confirmaron
+	Errores      []RowError `json:"errores,omitempty"`
+}
@@ -0,0 +1,43 @@
+package domain
+
+import "time"
+
+// PageParams contiene los parámetros comunes de paginación y orden de los listados.
+type PageParams struct {
+	Limit  int    // Máximo de filas por página
+	Cursor string // Cursor opaco devuelto en la página anterior (vacío = primera página)
+	Sort   string // Campo de orden (depende de cada listado)
+	Desc   bool   // true = descendente
+}
+
+// Page es una página de resultados.
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	Total      int    `json:"total"`                 // Total de filas que cumplen los filtros
+	NextCursor string `json:"next_cursor,omitempty"` // Vacío cuando no hay más páginas
+}
+
+// ProductFilter filtra el listado de productos.
+type ProductFilter struct {
+	Nombre    string   // Nombre contiene
+	StockLT   *int     // Stock menor a N
+	PrecioMin *float64 // Precio mayor o igual
+	PrecioMax *float64 // Precio menor o igual
+
+	CategoryID int64 // Categoría y todas sus subcategorías
+	BrandID    int64 // Marca
+}
+
+// ClientFilter filtra el listado de clientes.
+type ClientFilter struct {
+	Nombre string // Nombre contiene
+	Cedula string // Cédula empieza con
+	Email  string // Email contiene
+}
+
+// SaleFilter filtra el listado de ventas.
+type SaleFilter struct {
+	ClientID int64
+	Desde    time.Time // Inclusive (cero = sin límite)
+	Hasta    time.Time // Exclusivo (cero = sin límite)
+}
@@ -0,0 +1,100 @@
+package domain
+
+import "time"
+
+// Orígenes de un precio en el historial.
+const (
+	PriceOriginInitial   = "inicial"    // Precio con el que se creó el producto
+	PriceOriginManual    = "manual"     // Edición del producto o importación
+	PriceOriginScheduled = "programado" // Cambio programado o aumento masivo
+	PriceOriginSales     = "ventas"     // Reconstruido de las ventas al crear el historial
+)
+
+// Estados de un cambio de precio programado.
+const (
+	PriceChangePending   = "pendiente"
+	PriceChangeApplied   = "aplicado"
+	PriceChangeCancelled = "cancelado"
+)
+
+// PricePoint es un precio del historial: rige desde Desde hasta el siguiente.
+type PricePoint struct {
+	Precio   float64   `json:"precio"`
+	Desde    time.Time `json:"desde"`
+	Origen   string    `json:"origen"`
+	ChangeID *int64    `json:"change_id,omitempty"` // Cambio programado que lo aplicó
+}
+
+// PriceHistory es el historial de precios de un producto, del más reciente al más antiguo,
+// con los cambios programados que todavía no se aplicaron.
+type PriceHistory struct {
+	ProductID   int64         `json:"product_id"`
+	Producto    string        `json:"producto"`
+	Precio      float64       `json:"precio"` // Precio actual
+	Historial   []PricePoint  `json:"historial"`
+	Programados []PriceChange `json:"programados"`
+}
+
+// PriceAt es el precio que tenía un producto en una fecha.
+type PriceAt struct {
+	ProductID int64      `json:"product_id"`
+	Fecha     time.Time  `json:"fecha"`
+	Precio    float64    `json:"precio"`
+	Vigente   PricePoint `json:"vigente"` // Entrada del historial que regía en esa fecha
+}
+
+// PriceChange es un cambio de precio programado para una fecha.
+// Lleva precio (nuevo precio fijo) o porcentaje (aumento sobre el precio que tenga
+// el producto al aplicarse; negativo para bajar), no los dos.
+type PriceChange struct {
+	ID             int64      `json:"id"`
+	ProductID      int64      `json:"product_id"`
+	Producto       string     `json:"producto,omitempty"` // Solo lectura
+	Precio         *float64   `json:"precio,omitempty"`
+	Porcentaje     *float64   `json:"porcentaje,omitempty"`
+	Desde          time.Time  `json:"desde"` // Vacío al crear: se aplica ya
+	Estado         string     `json:"estado"`
+	Creado         time.Time  `json:"creado"`
+	Aplicado       *time.Time `json:"aplicado,omitempty"`
+	PrecioAnterior *float64   `json:"precio_anterior,omitempty"` // Al aplicarse
+	PrecioNuevo    *float64   `json:"precio_nuevo,omitempty"`    // Al aplicarse
+}
+
+// NewPrice calcula el precio que deja el cambio: el precio fijo, o el actual más el
+// porcentaje redondeado a centavos (nunca menos de un centavo).
+func (c PriceChange) NewPrice(actual float64) float64 {
+	if c.Precio != nil {
+		return *c.Precio
+	}
+	return max(cents(actual*(1+*c.Porcentaje/100)), 0.01)
+}
+
+// BulkPriceChange es un aumento porcentual para todos los productos de una categoría
+// (con sus subcategorías) o de un proveedor (productos recibidos de él alguna vez).
+type BulkPriceChange struct {
+	CategoryID int64     `json:"category_id,omitempty"`
+	Proveedor  string    `json:"proveedor,omitempty"`
+	Porcentaje float64   `json:"porcentaje"`
+	Desde      time.Time `json:"desde"` // Vacío: se aplica ya
+}
+
+// BulkPriceResult es el resultado de un aumento masivo.
+type BulkPriceResult struct {
+	Productos int           `json:"productos"` // Productos alcanzados
+	Aplicados int           `json:"aplicados"` // Aplicados en el momento (desde vacío o pasado)
+	Cambios   []PriceChange `json:"cambios"`
+}
+
+// PriceChangeFilter filtra el listado de cambios programados.
+type PriceChangeFilter struct {
+	ProductID int64
+	Estado    string
+}
+
+// AppliedPrice es un cambio programado que se acaba de aplicar.
+type AppliedPrice struct {
+	ChangeID  int64   `json:"change_id"`
+	ProductID int64   `json:"product_id"`
+	Antes     float64 `json:"antes"`
+	Despues   float64 `json:"despues"`
+}
@@ -0,0 +1,30 @@
+package domain
+
+// PriceList es una lista de precios (ej: minorista, mayorista, contratista) que se asigna
+// a clientes. Sus reglas definen el precio de venta según el producto y la cantidad.
+type PriceList struct {
+	ID          int64           `json:"id"`
+	Nombre      string          `json:"nombre"`
+	Descripcion string          `json:"descripcion,omitempty"`
+	Reglas      []PriceListRule `json:"reglas"`
+	Clientes    int             `json:"clientes"` // Clientes asignados (solo lectura)
+}
+
+// PriceListRule es una regla de una lista de precios. Aplica a un producto, a una
+// categoría (con sus subcategorías) o, sin ninguno de los dos, a todos los productos;
+// desde CantidadMin unidades de la línea (escalas por cantidad).
+// Lleva precio fijo (solo por producto) o porcentaje sobre el precio del producto
+// (negativo para descuento), no los dos.
+//
+// Al vender gana la regla más específica (producto, luego la categoría más cercana,
+// luego toda la lista) y, dentro de ella, la escala más alta que alcance la cantidad.
+type PriceListRule struct {
+	ID          int64    `json:"id"`
+	ProductID   *int64   `json:"product_id,omitempty"`
+	Producto    string   `json:"producto,omitempty"` // Solo lectura
+	CategoryID  *int64   `json:"category_id,omitempty"`
+	Categoria   string   `json:"categoria,omitempty"` // Solo lectura
+	CantidadMin int      `json:"cantidad_min"`        // 1 si no se indica
+	Precio      *float64 `json:"precio,omitempty"`
+	Porcentaje  *float64 `json:"porcentaje,omitempty"`
+}
@@ -7,4 +7,13 @@ type Product struct {
 	Nombre string  `json:"nombre"` // Nombre del producto
 	Stock  int     `json:"stock"`  // Cantidad disponible en inventario
 	Precio float64 `json:"precio"` // Precio unitario del producto
+	Costo  float64 `json:"costo"`  // Costo promedio ponderado (se recalcula en cada recepción)
+
+	SKU      string   `json:"sku"`                // Código interno único (se genera si viene vacío)
+	Barcodes []string `json:"barcodes,omitempty"` // Códigos EAN-13 / UPC-A / EAN-8
+
+	CategoryID *int64 `json:"category_id,omitempty"` // Categoría (hoja o intermedia); al actualizar, nil la conserva y 0 la quita
+	Categoria  string `json:"categoria,omitempty"`   // Nombre de la categoría (solo lectura)
+	BrandID    *int64 `json:"brand_id,omitempty"`    // Marca; al actualizar, nil la conserva y 0 la quita
+	Marca      string `json:"marca,omitempty"`       // Nombre de la marca (solo lectura)
 }
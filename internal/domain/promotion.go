@@ -0,0 +1,211 @@
+package domain
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Tipos de promoción.
+const (
+	PromoPercent = "porcentaje" // % de descuento en cada línea alcanzada
+	PromoBuyGet  = "nxm"        // Lleva compra + gratis unidades y paga compra (por línea)
+	PromoAmount  = "monto"      // Monto fijo sobre la venta si los productos alcanzados suman monto_min
+)
+
+// Tipos de objetivo de una promoción.
+const (
+	TargetProduct  = "product"
+	TargetCategory = "category" // Incluye subcategorías
+	TargetBrand    = "brand"
+)
+
+// Promotion es una regla de descuento que se evalúa al crear cada venta.
+//
+// Objetivos: sin objetivos alcanza a todos los productos. Con objetivos, un producto
+// debe cumplir cada tipo indicado (producto, categoría, marca) y dentro de un tipo
+// basta con uno: marca Truper + categoría Herramientas = herramientas Truper.
+//
+// Acumulación: se evalúan de mayor a menor prioridad. Una promoción no acumulable no
+// se aplica a una línea que ya tiene descuento y, si se aplica, la línea no recibe más.
+// Las de monto (cabecera) se evalúan después de las de línea; una no acumulable no se
+// aplica si los productos alcanzados ya tienen descuento y corta las siguientes.
+type Promotion struct {
+	ID          int64             `json:"id"`
+	Nombre      string            `json:"nombre"`
+	Tipo        string            `json:"tipo"`                   // porcentaje, nxm o monto
+	Porcentaje  float64           `json:"porcentaje,omitempty"`   // porcentaje: 0 a 100
+	Compra      int               `json:"compra,omitempty"`       // nxm: unidades que se pagan (compra 3...)
+	Gratis      int               `json:"gratis,omitempty"`       // nxm: ...y gratis 1
+	Monto       float64           `json:"monto,omitempty"`        // monto: descuento fijo
+	CantidadMin int               `json:"cantidad_min,omitempty"` // porcentaje: unidades mínimas de la línea
+	MontoMin    float64           `json:"monto_min,omitempty"`    // monto: compra mínima de los productos alcanzados
+	Desde       time.Time         `json:"desde"`
+	Hasta       *time.Time        `json:"hasta,omitempty"` // Exclusivo; nil = sin fin
+	Activa      bool              `json:"activa"`
+	Acumulable  bool              `json:"acumulable"`
+	Prioridad   int               `json:"prioridad"` // Mayor primero
+	Objetivos   []PromotionTarget `json:"objetivos"`
+}
+
+// PromotionTarget es un producto, categoría o marca alcanzado por una promoción.
+type PromotionTarget struct {
+	Tipo   string `json:"tipo"` // product, category o brand
+	ID     int64  `json:"id"`
+	Nombre string `json:"nombre,omitempty"` // Solo lectura
+}
+
+// PromotionFilter filtra el listado de promociones.
+type PromotionFilter struct {
+	Vigentes bool // Solo activas y dentro de su rango de fechas ahora
+}
+
+// AppliedPromotion es el descuento que una promoción hizo en una línea o en la venta.
+type AppliedPromotion struct {
+	PromotionID int64   `json:"promotion_id"`
+	Nombre      string  `json:"nombre"`
+	Monto       float64 `json:"monto"`
+}
+
+// PromoLine es una línea de venta ya con su precio, lista para evaluar promociones.
+type PromoLine struct {
+	ProductID      int64
+	BrandID        int64   // 0 = sin marca
+	Categories     []int64 // Categoría del producto y sus ancestros
+	Cantidad       int
+	PrecioUnitario float64
+}
+
+// ApplyPromotions evalúa las promociones sobre las líneas y devuelve los descuentos
+// de cada línea (mismo orden que lines) y los de la cabecera. Los montos se redondean
+// a centavos y nunca superan lo que queda por cobrar.
+func ApplyPromotions(promos []Promotion, lines []PromoLine) (lineDiscounts [][]AppliedPromotion, header []AppliedPromotion) {
+
+	sorted := append([]Promotion(nil), promos...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Prioridad != sorted[j].Prioridad {
+			return sorted[i].Prioridad > sorted[j].Prioridad
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	lineDiscounts = make([][]AppliedPromotion, len(lines))
+	remaining := make([]float64, len(lines))
+	locked := make([]bool, len(lines))
+	for i, l := range lines {
+		remaining[i] = float64(l.Cantidad) * l.PrecioUnitario
+	}
+
+	// 1) Descuentos por línea
+	for _, p := range sorted {
+		if p.Tipo == PromoAmount {
+			continue
+		}
+		for i, l := range lines {
+			if locked[i] || !p.matches(l) {
+				continue
+			}
+			if !p.Acumulable && len(lineDiscounts[i]) > 0 {
+				continue
+			}
+
+			amount := math.Min(p.lineDiscount(l, remaining[i]), remaining[i])
+			if amount <= 0 {
+				continue
+			}
+
+			lineDiscounts[i] = append(lineDiscounts[i], AppliedPromotion{PromotionID: p.ID, Nombre: p.Nombre, Monto: amount})
+			remaining[i] = cents(remaining[i] - amount)
+			locked[i] = !p.Acumulable
+		}
+	}
+
+	// 2) Descuentos sobre la venta
+	var headerTotal float64
+	for _, p := range sorted {
+		if p.Tipo != PromoAmount {
+			continue
+		}
+
+		var base float64
+		discounted := headerTotal > 0
+		for i, l := range lines {
+			if p.matches(l) {
+				base += remaining[i]
+				discounted = discounted || len(lineDiscounts[i]) > 0
+			}
+		}
+		if base <= 0 || base < p.MontoMin || (!p.Acumulable && discounted) {
+			continue
+		}
+
+		amount := cents(math.Min(p.Monto, base-headerTotal))
+		if amount <= 0 {
+			continue
+		}
+
+		header = append(header, AppliedPromotion{PromotionID: p.ID, Nombre: p.Nombre, Monto: amount})
+		headerTotal = cents(headerTotal + amount)
+		if !p.Acumulable {
+			break
+		}
+	}
+
+	return lineDiscounts, header
+}
+
+// lineDiscount calcula el descuento de una promoción de línea sobre lo que queda por cobrar.
+func (p Promotion) lineDiscount(l PromoLine, remaining float64) float64 {
+	switch p.Tipo {
+	case PromoPercent:
+		if l.Cantidad < max(p.CantidadMin, 1) {
+			return 0
+		}
+		return cents(remaining * p.Porcentaje / 100)
+	case PromoBuyGet:
+		group := p.Compra + p.Gratis
+		if group <= 0 {
+			return 0
+		}
+		free := l.Cantidad / group * p.Gratis
+		return cents(float64(free) * l.PrecioUnitario)
+	}
+	return 0
+}
+
+// matches indica si la línea cumple los objetivos de la promoción.
+func (p Promotion) matches(l PromoLine) bool {
+
+	var hasKind, okKind [3]bool
+	for _, t := range p.Objetivos {
+		var k int
+		var ok bool
+		switch t.Tipo {
+		case TargetProduct:
+			k, ok = 0, l.ProductID == t.ID
+		case TargetCategory:
+			k = 1
+			for _, c := range l.Categories {
+				ok = ok || c == t.ID
+			}
+		case TargetBrand:
+			k, ok = 2, l.BrandID == t.ID
+		default:
+			continue
+		}
+		hasKind[k] = true
+		okKind[k] = okKind[k] || ok
+	}
+
+	for k := range hasKind {
+		if hasKind[k] && !okKind[k] {
+			return false
+		}
+	}
+	return true
+}
+
+// cents redondea a centavos.
+func cents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,299 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestApplyPromotions(t *testing.T) {
+
+	product := func(id int64) []PromotionTarget {
+		return []PromotionTarget{{Tipo: TargetProduct, ID: id}}
+	}
+
+	tests := []struct {
+		name   string
+		promos []Promotion
+		lines  []PromoLine
+		want   [][]AppliedPromotion
+		header []AppliedPromotion
+	}{
+		{
+			name: "no acumulable corta las siguientes en la línea",
+			promos: []Promotion{
+				{ID: 1, Nombre: "A", Tipo: PromoPercent, Porcentaje: 10, Prioridad: 10},
+				{ID: 2, Nombre: "B", Tipo: PromoPercent, Porcentaje: 5, Prioridad: 5, Acumulable: true},
+			},
+			lines: []PromoLine{{ProductID: 1, Cantidad: 2, PrecioUnitario: 10}},
+			want:  [][]AppliedPromotion{{{PromotionID: 1, Nombre: "A", Monto: 2}}},
+		},
+		{
+			name: "no acumulable no entra en una línea con descuento",
+			promos: []Promotion{
+				{ID: 1, Nombre: "A", Tipo: PromoPercent, Porcentaje: 10, Prioridad: 10, Acumulable: true},
+				{ID: 2, Nombre: "B", Tipo: PromoPercent, Porcentaje: 50, Prioridad: 5},
+			},
+			lines: []PromoLine{{ProductID: 1, Cantidad: 2, PrecioUnitario: 10}},
+			want:  [][]AppliedPromotion{{{PromotionID: 1, Nombre: "A", Monto: 2}}},
+		},
+		{
+			name: "acumulables se aplican sobre lo que queda",
+			promos: []Promotion{
+				{ID: 1, Nombre: "A", Tipo: PromoPercent, Porcentaje: 10, Prioridad: 10, Acumulable: true},
+				{ID: 2, Nombre: "B", Tipo: PromoPercent, Porcentaje: 10, Prioridad: 5, Acumulable: true},
+			},
+			lines: []PromoLine{{ProductID: 1, Cantidad: 2, PrecioUnitario: 10}},
+			want: [][]AppliedPromotion{{
+				{PromotionID: 1, Nombre: "A", Monto: 2},
+				{PromotionID: 2, Nombre: "B", Monto: 1.8},
+			}},
+		},
+		{
+			name: "mayor prioridad primero",
+			promos: []Promotion{
+				{ID: 1, Nombre: "Baja", Tipo: PromoPercent, Porcentaje: 50, Prioridad: 1},
+				{ID: 2, Nombre: "Alta", Tipo: PromoPercent, Porcentaje: 10, Prioridad: 9},
+			},
+			lines: []PromoLine{{ProductID: 1, Cantidad: 2, PrecioUnitario: 10}},
+			want:  [][]AppliedPromotion{{{PromotionID: 2, Nombre: "Alta", Monto: 2}}},
+		},
+		{
+			name: "misma prioridad por ID",
+			promos: []Promotion{
+				{ID: 5, Nombre: "B", Tipo: PromoPercent, Porcentaje: 20, Acumulable: true},
+				{ID: 4, Nombre: "A", Tipo: PromoPercent, Porcentaje: 10, Acumulable: true},
+			},
+			lines: []PromoLine{{ProductID: 1, Cantidad: 1, PrecioUnitario: 100}},
+			want: [][]AppliedPromotion{{
+				{PromotionID: 4, Nombre: "A", Monto: 10},
+				{PromotionID: 5, Nombre: "B", Monto: 18},
+			}},
+		},
+		{
+			name: "nxm con unidades sobrantes",
+			promos: []Promotion{
+				{ID: 1, Nombre: "3x2", Tipo: PromoBuyGet, Compra: 2, Gratis: 1},
+			},
+			lines: []PromoLine{
+				{ProductID: 1, Cantidad: 7, PrecioUnitario: 5},
+				{ProductID: 2, Cantidad: 2, PrecioUnitario: 5},
+			},
+			want: [][]AppliedPromotion{{{PromotionID: 1, Nombre: "3x2", Monto: 10}}, nil},
+		},
+		{
+			name: "monto no alcanza la compra mínima",
+			promos: []Promotion{
+				{ID: 1, Nombre: "Monto", Tipo: PromoAmount, Monto: 5, MontoMin: 50, Objetivos: product(1)},
+			},
+			lines: []PromoLine{
+				{ProductID: 1, Cantidad: 4, PrecioUnitario: 10},
+				{ProductID: 2, Cantidad: 3, PrecioUnitario: 10},
+			},
+			want: [][]AppliedPromotion{nil, nil},
+		},
+		{
+			name: "monto con la compra mínima justa",
+			promos: []Promotion{
+				{ID: 1, Nombre: "Monto", Tipo: PromoAmount, Monto: 5, MontoMin: 50, Objetivos: product(1)},
+			},
+			lines:  []PromoLine{{ProductID: 1, Cantidad: 5, PrecioUnitario: 10}},
+			want:   [][]AppliedPromotion{nil},
+			header: []AppliedPromotion{{PromotionID: 1, Nombre: "Monto", Monto: 5}},
+		},
+		{
+			name: "monto mínimo sobre lo que queda después de las de línea",
+			promos: []Promotion{
+				{ID: 1, Nombre: "Línea", Tipo: PromoPercent, Porcentaje: 10, Acumulable: true, Prioridad: 10},
+				{ID: 2, Nombre: "Monto", Tipo: PromoAmount, Monto: 5, MontoMin: 50, Acumulable: true},
+			},
+			lines: []PromoLine{{ProductID: 1, Cantidad: 5, PrecioUnitario: 10}},
+			want:  [][]AppliedPromotion{{{PromotionID: 1, Nombre: "Línea", Monto: 5}}},
+		},
+		{
+			name: "monto no acumulable no entra si hay descuentos de línea",
+			promos: []Promotion{
+				{ID: 1, Nombre: "Línea", Tipo: PromoPercent, Porcentaje: 10, Acumulable: true},
+				{ID: 2, Nombre: "Exclusivo", Tipo: PromoAmount, Monto: 5, Prioridad: 10},
+				{ID: 3, Nombre: "Acumulable", Tipo: PromoAmount, Monto: 3, Acumulable: true, Prioridad: 5},
+			},
+			lines:  []PromoLine{{ProductID: 1, Cantidad: 5, PrecioUnitario: 10}},
+			want:   [][]AppliedPromotion{{{PromotionID: 1, Nombre: "Línea", Monto: 5}}},
+			header: []AppliedPromotion{{PromotionID: 3, Nombre: "Acumulable", Monto: 3}},
+		},
+		{
+			name: "monto no acumulable corta las siguientes",
+			promos: []Promotion{
+				{ID: 1, Nombre: "Exclusivo", Tipo: PromoAmount, Monto: 5, Prioridad: 10},
+				{ID: 2, Nombre: "Acumulable", Tipo: PromoAmount, Monto: 3, Acumulable: true, Prioridad: 5},
+			},
+			lines:  []PromoLine{{ProductID: 1, Cantidad: 5, PrecioUnitario: 10}},
+			want:   [][]AppliedPromotion{nil},
+			header: []AppliedPromotion{{PromotionID: 1, Nombre: "Exclusivo", Monto: 5}},
+		},
+		{
+			name: "línea sin saldo no recibe más descuentos",
+			promos: []Promotion{
+				{ID: 1, Nombre: "Gratis", Tipo: PromoPercent, Porcentaje: 100, Acumulable: true, Prioridad: 10},
+				{ID: 2, Nombre: "Diez", Tipo: PromoPercent, Porcentaje: 10, Acumulable: true, Prioridad: 5},
+			},
+			lines: []PromoLine{{ProductID: 1, Cantidad: 2, PrecioUnitario: 10}},
+			want:  [][]AppliedPromotion{{{PromotionID: 1, Nombre: "Gratis", Monto: 20}}},
+		},
+		{
+			name: "nxm limitado a lo que queda de la línea",
+			promos: []Promotion{
+				{ID: 1, Nombre: "Ochenta", Tipo: PromoPercent, Porcentaje: 80, Acumulable: true, Prioridad: 10},
+				{ID: 2, Nombre: "2x1", Tipo: PromoBuyGet, Compra: 1, Gratis: 1, Acumulable: true, Prioridad: 5},
+			},
+			lines: []PromoLine{{ProductID: 1, Cantidad: 4, PrecioUnitario: 10}},
+			want: [][]AppliedPromotion{{
+				{PromotionID: 1, Nombre: "Ochenta", Monto: 32},
+				{PromotionID: 2, Nombre: "2x1", Monto: 8},
+			}},
+		},
+		{
+			name: "montos de cabecera limitados al total",
+			promos: []Promotion{
+				{ID: 1, Nombre: "Quince", Tipo: PromoAmount, Monto: 15, Acumulable: true, Prioridad: 10},
+				{ID: 2, Nombre: "Otros quince", Tipo: PromoAmount, Monto: 15, Acumulable: true, Prioridad: 5},
+				{ID: 3, Nombre: "Cinco", Tipo: PromoAmount, Monto: 5, Acumulable: true},
+			},
+			lines: []PromoLine{{ProductID: 1, Cantidad: 2, PrecioUnitario: 10}},
+			want:  [][]AppliedPromotion{nil},
+			header: []AppliedPromotion{
+				{PromotionID: 1, Nombre: "Quince", Monto: 15},
+				{PromotionID: 2, Nombre: "Otros quince", Monto: 5},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines, header := ApplyPromotions(tt.promos, tt.lines)
+			if !reflect.DeepEqual(lines, tt.want) {
+				t.Errorf("líneas = %v, se esperaba %v", lines, tt.want)
+			}
+			if !reflect.DeepEqual(header, tt.header) {
+				t.Errorf("cabecera = %v, se esperaba %v", header, tt.header)
+			}
+		})
+	}
+}
+
+func TestPromotionLineDiscount(t *testing.T) {
+
+	tests := []struct {
+		name      string
+		promo     Promotion
+		line      PromoLine
+		remaining float64
+		want      float64
+	}{
+		{
+			name:      "porcentaje sobre lo que queda",
+			promo:     Promotion{Tipo: PromoPercent, Porcentaje: 10},
+			line:      PromoLine{Cantidad: 2, PrecioUnitario: 10},
+			remaining: 15,
+			want:      1.5,
+		},
+		{
+			name:      "porcentaje redondeado a centavos",
+			promo:     Promotion{Tipo: PromoPercent, Porcentaje: 15},
+			line:      PromoLine{Cantidad: 1, PrecioUnitario: 3.33},
+			remaining: 3.33,
+			want:      0.5,
+		},
+		{
+			name:      "porcentaje bajo la cantidad mínima",
+			promo:     Promotion{Tipo: PromoPercent, Porcentaje: 10, CantidadMin: 3},
+			line:      PromoLine{Cantidad: 2, PrecioUnitario: 10},
+			remaining: 20,
+			want:      0,
+		},
+		{
+			name:      "porcentaje con la cantidad mínima",
+			promo:     Promotion{Tipo: PromoPercent, Porcentaje: 10, CantidadMin: 3},
+			line:      PromoLine{Cantidad: 3, PrecioUnitario: 10},
+			remaining: 30,
+			want:      3,
+		},
+		{
+			name:      "nxm con sobrantes",
+			promo:     Promotion{Tipo: PromoBuyGet, Compra: 2, Gratis: 1},
+			line:      PromoLine{Cantidad: 7, PrecioUnitario: 5},
+			remaining: 35,
+			want:      10,
+		},
+		{
+			name:      "nxm sin completar un grupo",
+			promo:     Promotion{Tipo: PromoBuyGet, Compra: 2, Gratis: 1},
+			line:      PromoLine{Cantidad: 2, PrecioUnitario: 5},
+			remaining: 10,
+			want:      0,
+		},
+		{
+			name:      "nxm con varias gratis por grupo",
+			promo:     Promotion{Tipo: PromoBuyGet, Compra: 3, Gratis: 2},
+			line:      PromoLine{Cantidad: 11, PrecioUnitario: 4},
+			remaining: 44,
+			want:      16,
+		},
+		{
+			name:      "nxm sin grupo",
+			promo:     Promotion{Tipo: PromoBuyGet},
+			line:      PromoLine{Cantidad: 5, PrecioUnitario: 5},
+			remaining: 25,
+			want:      0,
+		},
+		{
+			name:      "monto no descuenta por línea",
+			promo:     Promotion{Tipo: PromoAmount, Monto: 5},
+			line:      PromoLine{Cantidad: 5, PrecioUnitario: 5},
+			remaining: 25,
+			want:      0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.promo.lineDiscount(tt.line, tt.remaining); got != tt.want {
+				t.Errorf("lineDiscount = %v, se esperaba %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPromotionMatches(t *testing.T) {
+
+	line := PromoLine{ProductID: 7, BrandID: 3, Categories: []int64{12, 4, 1}}
+
+	tests := []struct {
+		name      string
+		objetivos []PromotionTarget
+		want      bool
+	}{
+		{"sin objetivos alcanza a todos", nil, true},
+		{"producto", []PromotionTarget{{Tipo: TargetProduct, ID: 7}}, true},
+		{"otro producto", []PromotionTarget{{Tipo: TargetProduct, ID: 8}}, false},
+		{"uno de varios productos", []PromotionTarget{{Tipo: TargetProduct, ID: 8}, {Tipo: TargetProduct, ID: 7}}, true},
+		{"categoría padre", []PromotionTarget{{Tipo: TargetCategory, ID: 4}}, true},
+		{"otra categoría", []PromotionTarget{{Tipo: TargetCategory, ID: 5}}, false},
+		{"marca y categoría", []PromotionTarget{{Tipo: TargetBrand, ID: 3}, {Tipo: TargetCategory, ID: 1}}, true},
+		{"marca sin la categoría", []PromotionTarget{{Tipo: TargetBrand, ID: 3}, {Tipo: TargetCategory, ID: 5}}, false},
+		{"categoría sin la marca", []PromotionTarget{{Tipo: TargetBrand, ID: 9}, {Tipo: TargetCategory, ID: 12}}, false},
+		{"tipo desconocido se ignora", []PromotionTarget{{Tipo: "proveedor", ID: 99}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := Promotion{Objetivos: tt.objetivos}
+			if got := p.matches(line); got != tt.want {
+				t.Errorf("matches = %v, se esperaba %v", got, tt.want)
+			}
+		})
+	}
+
+	if (Promotion{Objetivos: []PromotionTarget{{Tipo: TargetBrand, ID: 3}}}).matches(PromoLine{ProductID: 7}) {
+		t.Error("una línea sin marca no cumple un objetivo de marca")
+	}
+}
@@ -0,0 +1,48 @@
+package domain
+
+import "time"
+
+// Receipt es una recepción de mercadería de un proveedor.
+// Suma stock y actualiza el costo promedio ponderado de cada producto.
+type Receipt struct {
+	ID        int64         `json:"id"`
+	Fecha     time.Time     `json:"fecha"`
+	Proveedor string        `json:"proveedor"`
+	Nota      string        `json:"nota,omitempty"`
+	Total     float64       `json:"total"` // Suma de cantidad * costo unitario
+	Items     []ReceiptItem `json:"items,omitempty"`
+}
+
+// ReceiptItem es un producto recibido.
+type ReceiptItem struct {
+	ProductID     int64   `json:"product_id"`
+	Producto      string  `json:"producto,omitempty"` // Solo lectura
+	Cantidad      int     `json:"cantidad"`
+	CostoUnitario float64 `json:"costo_unitario"`
+	CostoAnterior float64 `json:"costo_anterior,omitempty"` // Costo promedio antes de la recepción (solo al crear)
+	CostoNuevo    float64 `json:"costo_nuevo,omitempty"`    // Costo promedio después de la recepción (solo al crear)
+}
+
+// ReceiptFilter filtra el listado de recepciones.
+type ReceiptFilter struct {
+	Proveedor string    // Proveedor contiene
+	Desde     time.Time // Inclusive (cero = sin límite)
+	Hasta     time.Time // Exclusivo (cero = sin límite)
+}
+
+// Tipos de movimiento de stock (las ventas se registran en sale_items).
+const (
+	MovementInitial    = "inicial"   // Stock con el que se creó el producto
+	MovementReceipt    = "recepcion" // Recepción de mercadería
+	MovementAdjustment = "ajuste"    // Corrección manual o importación
+)
+
+// WeightedAverageCost calcula el nuevo costo promedio al recibir cantidad unidades
+// a costoUnitario teniendo stock unidades a costoActual. Sin stock previo
+// (o negativo) el costo pasa a ser el de la recepción.
+func WeightedAverageCost(stock int, costoActual float64, cantidad int, costoUnitario float64) float64 {
+	if stock <= 0 {
+		return costoUnitario
+	}
+	return (float64(stock)*costoActual + float64(cantidad)*costoUnitario) / float64(stock+cantidad)
+}
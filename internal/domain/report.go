@@ -0,0 +1,247 @@
+package domain
+
+import "time"
+
+// Agrupaciones disponibles para los reportes de ventas.
+const (
+	GroupByProduct  = "product"
+	GroupByCategory = "category"
+	GroupByBrand    = "brand"
+	GroupBySale     = "sale"
+)
+
+// GroupSales es una fila de ventas agregadas por categoría o marca.
+// En categorías, las ventas de las subcategorías se suman a sus padres.
+type GroupSales struct {
+	ID       int64   `json:"id"`
+	Nombre   string  `json:"nombre"`
+	ParentID *int64  `json:"parent_id,omitempty"` // Solo categorías
+	Cantidad int     `json:"cantidad"`            // Unidades vendidas
+	Total    float64 `json:"total"`               // Monto vendido
+}
+
+// Períodos del resumen de ventas.
+const (
+	PeriodDay   = "day"
+	PeriodWeek  = "week" // Semanas ISO, de lunes a domingo
+	PeriodMonth = "month"
+)
+
+// TimeRange es un intervalo [Desde, Hasta).
+type TimeRange struct {
+	Desde time.Time
+	Hasta time.Time
+}
+
+// SalesTotals son los totales de un conjunto de ventas.
+// Bruto es la suma de las líneas; Neto = Bruto - Descuentos; Total = Neto + Impuestos.
+// Utilidad = Neto - Costo (costo de lo vendido al momento de cada venta).
+type SalesTotals struct {
+	Ventas         int     `json:"ventas"`
+	Bruto          float64 `json:"bruto"`
+	Descuentos     float64 `json:"descuentos"`
+	Impuestos      float64 `json:"impuestos"`
+	Neto           float64 `json:"neto"`
+	Total          float64 `json:"total"`
+	TicketPromedio float64 `json:"ticket_promedio"` // Total / Ventas
+	Costo          float64 `json:"costo"`
+	Utilidad       float64 `json:"utilidad"`
+	MargenPct      float64 `json:"margen_pct"` // Utilidad / Neto * 100
+}
+
+// SalesSummaryBucket son los totales de un período (día, semana o mes).
+type SalesSummaryBucket struct {
+	Periodo string    `json:"periodo"` // 2026-10-19, 2026-W43 o 2026-10
+	Desde   time.Time `json:"desde"`
+	Hasta   time.Time `json:"hasta"` // Exclusivo
+	SalesTotals
+}
+
+// SalesSummary es el resumen de ventas de un rango agrupado por período.
+// Incluye los períodos sin ventas (en cero).
+type SalesSummary struct {
+	GroupBy  string               `json:"group_by"`
+	Desde    time.Time            `json:"desde"`
+	Hasta    time.Time            `json:"hasta"`
+	Periodos []SalesSummaryBucket `json:"periodos"`
+	Totales  SalesTotals          `json:"totales"`
+}
+
+// Métricas para ordenar el ranking de productos.
+const (
+	MetricUnits   = "units"   // Unidades vendidas
+	MetricRevenue = "revenue" // Monto vendido
+	MetricMargin  = "margin"  // Utilidad bruta
+)
+
+// TopProductsQuery son los parámetros del ranking de productos.
+type TopProductsQuery struct {
+	Limit      int
+	Desde      time.Time // Inclusive (cero = sin límite)
+	Hasta      time.Time // Exclusivo (cero = sin límite)
+	Metric     string    // units, revenue o margin
+	CategoryID int64     // Categoría y subcategorías (0 = todas)
+	Asc        bool      // true = menos vendidos primero (incluye productos sin ventas)
+	All        bool      // Todos los productos (incluye los sin ventas) y sin límite; lo usan otros reportes
+}
+
+// TopProduct es una fila del ranking de productos, agrupada por ID de producto.
+type TopProduct struct {
+	ProductID int64   `json:"product_id"`
+	SKU       string  `json:"sku"`
+	Producto  string  `json:"producto"`
+	Cantidad  int     `json:"cantidad"` // Unidades vendidas
+	Total     float64 `json:"total"`    // Monto vendido
+	Ventas    int     `json:"ventas"`   // Ventas en las que aparece
+	Costo     float64 `json:"costo"`    // Costo de lo vendido
+	Utilidad  float64 `json:"utilidad"` // Neto de las líneas - Costo
+}
+
+// MarginRow es la utilidad bruta de una venta, producto o categoría.
+// Ingresos es el neto de las líneas (descuentos de cabecera repartidos, sin impuestos).
+type MarginRow struct {
+	ID        int64      `json:"id"`
+	Nombre    string     `json:"nombre"`          // Producto, categoría o cliente de la venta
+	Fecha     *time.Time `json:"fecha,omitempty"` // Solo por venta
+	Cantidad  int        `json:"cantidad"`        // Unidades vendidas
+	Ingresos  float64    `json:"ingresos"`
+	Costo     float64    `json:"costo"`
+	Utilidad  float64    `json:"utilidad"`   // Ingresos - Costo
+	MargenPct float64    `json:"margen_pct"` // Utilidad / Ingresos * 100
+}
+
+// ValuationRow es el valor del stock de un producto a una fecha.
+type ValuationRow struct {
+	ProductID      int64   `json:"product_id"`
+	SKU            string  `json:"sku"`
+	Producto       string  `json:"producto"`
+	CategoryID     *int64  `json:"category_id,omitempty"`
+	Categoria      string  `json:"categoria,omitempty"` // Ruta completa (Plomería > Tuberías)
+	Cantidad       int     `json:"cantidad"`
+	CostoUnitario  float64 `json:"costo_unitario"`  // Costo promedio a la fecha
+	PrecioUnitario float64 `json:"precio_unitario"` // Precio de venta actual
+	ValorCosto     float64 `json:"valor_costo"`     // Cantidad * CostoUnitario
+	ValorVenta     float64 `json:"valor_venta"`     // Cantidad * PrecioUnitario
+}
+
+// ValuationTotals son los totales de un grupo de productos valorizados.
+type ValuationTotals struct {
+	Productos  int     `json:"productos"`
+	Cantidad   int     `json:"cantidad"`
+	ValorCosto float64 `json:"valor_costo"`
+	ValorVenta float64 `json:"valor_venta"`
+}
+
+// ValuationCategory agrupa los productos de una categoría (sin incluir subcategorías).
+type ValuationCategory struct {
+	CategoryID *int64          `json:"category_id"` // null = sin categoría
+	Categoria  string          `json:"categoria"`
+	Productos  []ValuationRow  `json:"productos"`
+	Subtotal   ValuationTotals `json:"subtotal"`
+}
+
+// InventoryValuation es el valor del inventario a una fecha, al costo y a precio de venta.
+type InventoryValuation struct {
+	Fecha      time.Time           `json:"fecha"` // Momento valorizado
+	Categorias []ValuationCategory `json:"categorias"`
+	Totales    ValuationTotals     `json:"totales"`
+}
+
+// Clases del análisis ABC.
+const (
+	ClassA = "A"
+	ClassB = "B"
+	ClassC = "C"
+)
+
+// ABCQuery son los parámetros del análisis ABC.
+type ABCQuery struct {
+	Desde      time.Time
+	Hasta      time.Time // Exclusivo
+	Metric     string    // revenue (por defecto), units o margin
+	CategoryID int64     // Categoría y subcategorías (0 = todas)
+	LimiteA    float64   // % acumulado que cierra la clase A (por defecto 80)
+	LimiteB    float64   // % acumulado que cierra la clase B (por defecto 95)
+}
+
+// ABCItem es un producto clasificado. Valor es la métrica usada.
+type ABCItem struct {
+	TopProduct
+	Valor         float64 `json:"valor"`
+	Participacion float64 `json:"participacion_pct"` // Valor / total * 100
+	Acumulado     float64 `json:"acumulado_pct"`     // Participación acumulada hasta este producto
+	Clase         string  `json:"clase"`
+}
+
+// ABCClass resume una clase del análisis.
+type ABCClass struct {
+	Clase         string  `json:"clase"`
+	Productos     int     `json:"productos"`
+	PctProductos  float64 `json:"productos_pct"` // Del total de productos
+	Valor         float64 `json:"valor"`
+	Participacion float64 `json:"participacion_pct"` // Del valor total
+}
+
+// ABCReport clasifica los productos por su aporte a la métrica en el rango:
+// A hasta LimiteA % acumulado, B hasta LimiteB % y C el resto (incluye los sin ventas).
+type ABCReport struct {
+	Desde     time.Time  `json:"desde"`
+	Hasta     time.Time  `json:"hasta"`
+	Metric    string     `json:"metric"`
+	Total     float64    `json:"total"`
+	Clases    []ABCClass `json:"clases"`
+	Productos []ABCItem  `json:"productos"`
+}
+
+// DeadStockRow es un producto con stock que no se vende desde la fecha de corte.
+type DeadStockRow struct {
+	ProductID    int64      `json:"product_id"`
+	SKU          string     `json:"sku"`
+	Producto     string     `json:"producto"`
+	Categoria    string     `json:"categoria,omitempty"`
+	Stock        int        `json:"stock"`
+	Costo        float64    `json:"costo"`                  // Costo promedio actual
+	Capital      float64    `json:"capital"`                // Stock * Costo
+	UltimaVenta  *time.Time `json:"ultima_venta,omitempty"` // null = nunca se vendió
+	DiasSinVenta *int       `json:"dias_sin_venta,omitempty"`
+}
+
+// DeadStockReport lista el stock sin movimiento desde Desde, ordenado por capital inmovilizado.
+type DeadStockReport struct {
+	Desde     time.Time      `json:"desde"`
+	Productos []DeadStockRow `json:"productos"`
+	Unidades  int            `json:"unidades"`
+	Capital   float64        `json:"capital"`
+}
+
+// HeatmapCell son las ventas de una hora de un día de la semana (hora local de la tienda).
+// Ocurrencias es cuántas veces esa hora aparece en el rango (ej: 12 lunes a las 10);
+// promedios y medianas son por ocurrencia, contando las que no tuvieron ventas.
+type HeatmapCell struct {
+	Dia            int     `json:"dia"`  // 0 = lunes ... 6 = domingo
+	Hora           int     `json:"hora"` // 0 a 23
+	Ocurrencias    int     `json:"ocurrencias"`
+	Ventas         int     `json:"ventas"`
+	Total          float64 `json:"total"`
+	PromedioVentas float64 `json:"promedio_ventas"`
+	MedianaVentas  float64 `json:"mediana_ventas"`
+	PromedioTotal  float64 `json:"promedio_total"`
+	MedianaTotal   float64 `json:"mediana_total"`
+}
+
+// SalesHeatmap es la matriz de 7 días (lunes a domingo) × 24 horas de [Desde, Hasta).
+type SalesHeatmap struct {
+	Desde  time.Time       `json:"desde"`
+	Hasta  time.Time       `json:"hasta"`
+	Dias   []string        `json:"dias"`
+	Ventas int             `json:"ventas"`
+	Total  float64         `json:"total"`
+	Matriz [][]HeatmapCell `json:"matriz"` // Matriz[dia][hora]
+}
+
+// DailySalesRebuild es el resultado de reconstruir el resumen diario de ventas.
+type DailySalesRebuild struct {
+	Zona   string `json:"zona"`   // Zona horaria con la que se armaron los días
+	Ventas int    `json:"ventas"` // Ventas leídas
+	Filas  int    `json:"filas"`  // Filas (día, producto) generadas
+}
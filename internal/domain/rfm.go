@@ -0,0 +1,48 @@
+package domain
+
+import "time"
+
+// Segmentos de clientes del análisis RFM, según los puntajes de recencia (R) y frecuencia (F).
+const (
+	SegmentChampion          = "champion"           // Compran seguido y hace poco
+	SegmentLoyal             = "loyal"              // Compran seguido
+	SegmentPotentialLoyalist = "potential_loyalist" // Recientes con algunas compras
+	SegmentNew               = "new"                // Recientes con una sola compra
+	SegmentNeedAttention     = "need_attention"     // Término medio
+	SegmentAtRisk            = "at_risk"            // Compraban seguido pero hace tiempo que no
+	SegmentCantLose          = "cant_lose"          // Los más frecuentes, hace tiempo que no compran
+	SegmentHibernating       = "hibernating"        // Pocas compras y hace tiempo
+	SegmentLost              = "lost"               // Pocas compras y hace mucho
+)
+
+// RFMRow es un cliente con su recencia, frecuencia y monto, los puntajes de 1 a 5
+// (quintiles entre los clientes del reporte, 5 = mejor) y el segmento.
+type RFMRow struct {
+	ClientID     int64     `json:"client_id"`
+	Nombre       string    `json:"nombre"`
+	Cedula       string    `json:"cedula"`
+	Email        string    `json:"email"`
+	UltimaCompra time.Time `json:"ultima_compra"`
+	Recencia     int       `json:"recencia_dias"` // Días desde la última compra
+	Frecuencia   int       `json:"frecuencia"`    // Compras en el rango
+	Monto        float64   `json:"monto"`         // Total comprado en el rango
+	R            int       `json:"r"`
+	F            int       `json:"f"`
+	M            int       `json:"m"`
+	Segmento     string    `json:"segmento"`
+}
+
+// RFMSegment resume un segmento.
+type RFMSegment struct {
+	Segmento string  `json:"segmento"`
+	Clientes int     `json:"clientes"`
+	Monto    float64 `json:"monto"`
+}
+
+// RFMReport es el análisis RFM de los clientes que compraron alguna vez hasta Hasta.
+type RFMReport struct {
+	Desde     time.Time    `json:"desde"`
+	Hasta     time.Time    `json:"hasta"`
+	Segmentos []RFMSegment `json:"segmentos"`
+	Clientes  []RFMRow     `json:"clientes"`
+}
@@ -5,18 +5,47 @@ import "time"
 // SaleItem representa un producto dentro de una venta.
 // Cada venta puede tener varios productos.
 type SaleItem struct {
-	ProductID      int64   `json:"product_id"`      // ID del producto vendido
-	Cantidad       int     `json:"cantidad"`        // Cantidad vendida
-	PrecioUnitario float64 `json:"precio_unitario"` // Precio al momento de la venta
-	Subtotal       float64 `json:"subtotal"`        // Cantidad * PrecioUnitario
+	ProductID       int64              `json:"product_id"`                 // ID del producto vendido
+	Cantidad        int                `json:"cantidad"`                   // Cantidad vendida
+	PrecioUnitario  float64            `json:"precio_unitario"`            // Precio al momento de la venta
+	Subtotal        float64            `json:"subtotal"`                   // Cantidad * PrecioUnitario (antes de descuentos)
+	CostoUnitario   float64            `json:"costo_unitario"`             // Costo promedio del producto al momento de la venta
+	PriceListID     *int64             `json:"price_list_id,omitempty"`    // Lista de precios que definió el precio (nil = precio del producto)
+	ListaPrecios    string             `json:"lista_precios,omitempty"`    // Nombre de la lista (solo lectura)
+	Descuento       float64            `json:"descuento,omitempty"`        // Descuento de promociones en la línea
+	Promociones     []AppliedPromotion `json:"promociones,omitempty"`      // Promociones que lo produjeron
+	DescuentoManual *ManualDiscount    `json:"descuento_manual,omitempty"` // Descuento del cajero (incluido en Descuento)
 }
 
 // Sale representa la cabecera de una venta.
 type Sale struct {
-	ID         int64      `json:"id"`
-	ClientID   int64      `json:"client_id"`
-	ClientName string     `json:"client_name"` // 👈 NUEVO
-	Fecha      time.Time  `json:"fecha"`
-	Total      float64    `json:"total"`
-	Items      []SaleItem `json:"items"`
+	ID              int64              `json:"id"`
+	ClientID        int64              `json:"client_id"`
+	ClientName      string             `json:"client_name"` // 👈 NUEVO
+	Fecha           time.Time          `json:"fecha"`
+	Total           float64            `json:"total"`     // Suma de subtotales - Descuento + Impuesto
+	Descuento       float64            `json:"descuento"` // Descuentos de las líneas + de la venta
+	Impuesto        float64            `json:"impuesto"`  // Impuesto sobre el neto con la tasa de la tienda
+	Items           []SaleItem         `json:"items"`
+	Promociones     []AppliedPromotion `json:"promociones,omitempty"`      // Descuentos sobre la venta (no de una línea)
+	DescuentoManual *ManualDiscount    `json:"descuento_manual,omitempty"` // Descuento del cajero sobre la venta
+	Cajero          string             `json:"cajero,omitempty"`           // Usuario de la sesión que vendió
+	AutorizadoPor   string             `json:"autorizado_por,omitempty"`   // Supervisor que autorizó los descuentos manuales
+}
+
+// SaleLine es una línea de venta con los datos de la cabecera y del producto
+// (una fila por producto vendido, usada en las exportaciones).
+type SaleLine struct {
+	ID             int64     `json:"id"`
+	SaleID         int64     `json:"sale_id"`
+	Fecha          time.Time `json:"fecha"`
+	ClientID       int64     `json:"client_id"`
+	ClientName     string    `json:"client_name"`
+	ProductID      int64     `json:"product_id"`
+	Producto       string    `json:"producto"`
+	SKU            string    `json:"sku"`
+	Cantidad       int       `json:"cantidad"`
+	PrecioUnitario float64   `json:"precio_unitario"`
+	Subtotal       float64   `json:"subtotal"`
+	Descuento      float64   `json:"descuento"` // Descuento de promociones en la línea
 }
@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// Roles que vienen creados. Se pueden agregar otros con su descuento máximo.
+const (
+	RoleCashier    = "cajero" // Rol de los usuarios que no están registrados
+	RoleSupervisor = "supervisor"
+	RoleManager    = "gerente"
+)
+
+// Role define hasta qué porcentaje de descuento manual puede dar un usuario sin autorización.
+type Role struct {
+	Nombre       string  `json:"nombre"`
+	DescuentoMax float64 `json:"descuento_max"` // % sobre lo que se cobra (0 a 100)
+}
+
+// User es un usuario de la tienda. Se identifica con una sesión (POST /api/sessions).
+type User struct {
+	ID       int64     `json:"id"`
+	Usuario  string    `json:"usuario"` // Único, sin distinguir mayúsculas
+	Nombre   string    `json:"nombre"`
+	Rol      string    `json:"rol"`
+	Activo   bool      `json:"activo"`
+	Password string    `json:"password,omitempty"` // Solo al crear o cambiarla; nunca se devuelve
+	Creado   time.Time `json:"creado"`
+}
+
+// CanManage indica si el rol del usuario administra usuarios, roles y aprobaciones
+// de descuentos (supervisor o gerente).
+func (u *User) CanManage() bool {
+	return u != nil && (strings.EqualFold(u.Rol, RoleSupervisor) || strings.EqualFold(u.Rol, RoleManager))
+}
+
+// Session es una sesión iniciada con usuario y contraseña. El token se envía en el
+// header Authorization: Bearer <token>.
+type Session struct {
+	Token   string    `json:"token"` // Solo al crearla (se guarda su hash)
+	Usuario User      `json:"usuario"`
+	Creado  time.Time `json:"creado"`
+	Expira  time.Time `json:"expira"`
+}
+
+type userKey struct{}
+
+// WithUser devuelve un contexto que lleva el usuario de la sesión.
+func WithUser(ctx context.Context, u *User) context.Context {
+	return context.WithValue(ctx, userKey{}, u)
+}
+
+// UserFrom devuelve el usuario de la sesión del contexto (nil si no inició sesión).
+func UserFrom(ctx context.Context) *User {
+	u, _ := ctx.Value(userKey{}).(*User)
+	return u
+}
+
+// DiscountCredentials autorizan un descuento por encima del límite del cajero:
+// usuario y contraseña de un supervisor, o un token de aprobación que él generó.
+type DiscountCredentials struct {
+	Usuario  string `json:"usuario,omitempty"`
+	Password string `json:"password,omitempty"`
+	Token    string `json:"token,omitempty"`
+}
+
+// DiscountApproval es un token de un solo uso con el que un supervisor autoriza
+// de antemano un descuento manual hasta DescuentoMax.
+type DiscountApproval struct {
+	ID           int64     `json:"id"`
+	Supervisor   string    `json:"supervisor"`
+	DescuentoMax float64   `json:"descuento_max"`
+	Token        string    `json:"token,omitempty"` // Solo al crearlo (se guarda su hash)
+	Creado       time.Time `json:"creado"`
+	Expira       time.Time `json:"expira"`
+}
+
+// DiscountAuth son los límites con que se autorizan los descuentos manuales de una venta.
+type DiscountAuth struct {
+	Cajero           string  // Usuario de la sesión que vende (vacío si no inició sesión)
+	Limite           float64 // % máximo del rol del cajero
+	Supervisor       string  // Quien autoriza por encima del límite (vacío = nadie)
+	LimiteSupervisor float64 // % máximo que autoriza el supervisor
+	ApprovalID       int64   // Token de aprobación que se consume si hace falta (0 = credenciales)
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"math"
+	"time"
+
+	"ferreteria-inventario-ventas/internal/domain"
+)
+
+// Interfaz que debe cumplir el repositorio de asociaciones.
+type AssociationRepository interface {
+	Rebuild(p domain.AssociationParams) (ventas, pares int, err error)
+	Recommendations(productID int64, limit int, minLift float64, inStock bool) ([]domain.Recommendation, error)
+}
+
+// Parámetros del cálculo y de las recomendaciones.
+const (
+	AssociationWindowDays      = 365 // Ventas del último año
+	AssociationMinSales        = 2   // Un par que aparece en una sola venta es coincidencia
+	DefaultRecommendationLimit = 5
+	MaxRecommendationLimit     = 20
+)
+
+// AssociationService calcula y consulta los productos que se compran juntos.
+type AssociationService struct {
+	repo AssociationRepository
+}
+
+// Constructor del servicio.
+func NewAssociationService(r AssociationRepository) *AssociationService {
+	return &AssociationService{repo: r}
+}
+
+// Rebuild recalcula las asociaciones con las ventas del último año.
+// Lo ejecuta periódicamente un proceso en segundo plano (ver cmd/api).
+func (s *AssociationService) Rebuild() (domain.AssociationStats, error) {
+
+	now := time.Now().Truncate(time.Second)
+	p := domain.AssociationParams{
+		Desde:     now.AddDate(0, 0, -AssociationWindowDays),
+		MinVentas: AssociationMinSales,
+	}
+
+	ventas, pares, err := s.repo.Rebuild(p)
+	if err != nil {
+		return domain.AssociationStats{}, err
+	}
+
+	return domain.AssociationStats{Desde: p.Desde, Ventas: ventas, Pares: pares, Calculado: now}, nil
+}
+
+// Recommendations devuelve hasta limit productos (5 por defecto) que suelen venderse
+// con productID y que se compran juntos más de lo esperado por azar (lift > 1).
+// Con inStock solo sugiere productos con stock.
+func (s *AssociationService) Recommendations(productID int64, limit int, inStock bool) ([]domain.Recommendation, error) {
+
+	if limit == 0 {
+		limit = DefaultRecommendationLimit
+	}
+	if productID <= 0 || limit < 0 || limit > MaxRecommendationLimit {
+		return nil, domain.ErrInvalidInput
+	}
+
+	list, err := s.repo.Recommendations(productID, limit, 1, inStock)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range list {
+		list[i].Soporte = roundRatio(list[i].Soporte)
+		list[i].Confianza = roundRatio(list[i].Confianza)
+		list[i].Lift = roundCents(list[i].Lift)
+	}
+
+	return list, nil
+}
+
+// roundRatio redondea proporciones (0 a 1) a cuatro decimales.
+func roundRatio(v float64) float64 {
+	return math.Round(v*10000) / 10000
+}
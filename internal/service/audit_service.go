@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"ferreteria-inventario-ventas/internal/domain"
+)
+
+// Interfaz que debe cumplir el repositorio de auditoría (los registros los guardan
+// los repositorios de cada entidad con la función que arma Entry).
+type AuditRepository interface {
+	List(f domain.AuditFilter, p domain.PageParams) (domain.Page[domain.AuditEntry], error)
+}
+
+// Auditor arma los registros de los cambios hechos por los servicios.
+type Auditor interface {
+	Entry(ctx context.Context, accion, entidad string) domain.AuditFunc
+}
+
+// DefaultActor es el actor cuando la petición no lo indica.
+const DefaultActor = "desconocido"
+
+// AuditService guarda y consulta la auditoría.
+type AuditService struct {
+	repo AuditRepository
+}
+
+// Constructor del servicio.
+func NewAuditService(r AuditRepository) *AuditService {
+	return &AuditService{repo: r}
+}
+
+// Entry devuelve la función que arma los registros de una acción con los campos que
+// cambiaron entre before y after. El actor y el ID de la petición salen de ctx.
+// El repositorio la llama dentro de la transacción del cambio, así que si la
+// auditoría no se puede guardar el cambio tampoco se hace.
+func (s *AuditService) Entry(ctx context.Context, accion, entidad string) domain.AuditFunc {
+
+	actor := domain.AuditActorFrom(ctx)
+	if actor.Actor == "" {
+		actor.Actor = DefaultActor
+	}
+
+	return func(id int64, before, after any) (*domain.AuditEntry, error) {
+
+		e := &domain.AuditEntry{
+			Fecha:     time.Now().Truncate(time.Second),
+			Actor:     actor.Actor,
+			RequestID: actor.RequestID,
+			Accion:    accion,
+			Entidad:   entidad,
+		}
+		if id > 0 {
+			e.EntidadID = &id
+		}
+
+		changes, err := auditDiff(before, after)
+		if err != nil {
+			return nil, err
+		}
+		if e.Cambios, err = json.Marshal(changes); err != nil {
+			return nil, err
+		}
+		return e, nil
+	}
+}
+
+// List devuelve una página de la auditoría, por defecto los más recientes primero.
+func (s *AuditService) List(f domain.AuditFilter, p domain.PageParams) (domain.Page[domain.AuditEntry], error) {
+	if err := normalizePage(&p); err != nil {
+		return domain.Page[domain.AuditEntry]{}, err
+	}
+	if !f.Desde.IsZero() && !f.Hasta.IsZero() && !f.Desde.Before(f.Hasta) {
+		return domain.Page[domain.AuditEntry]{}, domain.ErrInvalidInput
+	}
+	return s.repo.List(f, p)
+}
+
+// auditDiff compara los campos JSON de before y after y devuelve los que cambiaron.
+func auditDiff(before, after any) (map[string]domain.FieldChange, error) {
+
+	b, err := jsonFields(before)
+	if err != nil {
+		return nil, err
+	}
+	a, err := jsonFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string]domain.FieldChange)
+
+	for k, v := range b {
+		if !reflect.DeepEqual(v, a[k]) {
+			changes[k] = domain.FieldChange{Antes: v, Despues: a[k]}
+		}
+	}
+	for k, v := range a {
+		if _, ok := b[k]; !ok {
+			changes[k] = domain.FieldChange{Despues: v}
+		}
+	}
+
+	return changes, nil
+}
+
+// jsonFields convierte v en sus campos JSON (vacío si v es nil).
+func jsonFields(v any) (map[string]any, error) {
+
+	fields := map[string]any{}
+	if v == nil {
+		return fields, nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	if fields == nil {
+		// Puntero nil
+		fields = map[string]any{}
+	}
+
+	return fields, nil
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"strings"
+
+	"ferreteria-inventario-ventas/internal/domain"
+)
+
+// Interfaz que debe cumplir el repositorio de marcas.
+type BrandRepository interface {
+	Create(*domain.Brand) error
+	List() ([]domain.Brand, error)
+	Update(id int64, b *domain.Brand) error
+	Delete(id int64) error
+}
+
+// BrandService contiene la lógica de negocio para marcas.
+type BrandService struct {
+	repo BrandRepository
+}
+
+// Constructor del servicio.
+func NewBrandService(r BrandRepository) *BrandService {
+	return &BrandService{repo: r}
+}
+
+// Create valida el nombre antes de guardar.
+func (s *BrandService) Create(b *domain.Brand) error {
+	b.Nombre = strings.TrimSpace(b.Nombre)
+	if b.Nombre == "" {
+		return domain.ErrInvalidInput
+	}
+	return s.repo.Create(b)
+}
+
+// List devuelve todas las marcas.
+func (s *BrandService) List() ([]domain.Brand, error) {
+	return s.repo.List()
+}
+
+func (s *BrandService) Update(id int64, b *domain.Brand) error {
+	b.Nombre = strings.TrimSpace(b.Nombre)
+	if id <= 0 || b.Nombre == "" {
+		return domain.ErrInvalidInput
+	}
+	b.ID = id
+	return s.repo.Update(id, b)
+}
+
+// Delete elimina una marca sin productos.
+func (s *BrandService) Delete(id int64) error {
+	if id <= 0 {
+		return domain.ErrInvalidInput
+	}
+	return s.repo.Delete(id)
+}
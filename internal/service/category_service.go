@@ -0,0 +1,116 @@
+package service
+
+import (
+	"strings"
+
+	"ferreteria-inventario-ventas/internal/domain"
+)
+
+// Interfaz que debe cumplir el repositorio de categorías.
+type CategoryRepository interface {
+	Create(*domain.Category) error
+	List() ([]domain.Category, error)
+	Get(id int64) (*domain.Category, error)
+	IsDescendant(id, candidate int64) (bool, error)
+	Update(id int64, c *domain.Category) error
+	Delete(id int64) error
+}
+
+// CategoryService contiene la lógica de negocio para el árbol de categorías.
+type CategoryService struct {
+	repo CategoryRepository
+}
+
+// Constructor del servicio.
+func NewCategoryService(r CategoryRepository) *CategoryService {
+	return &CategoryService{repo: r}
+}
+
+// Create valida el nombre y que la categoría padre exista.
+func (s *CategoryService) Create(c *domain.Category) error {
+
+	c.Nombre = strings.TrimSpace(c.Nombre)
+	if c.Nombre == "" {
+		return domain.ErrInvalidInput
+	}
+
+	if c.ParentID != nil {
+		if _, err := s.repo.Get(*c.ParentID); err != nil {
+			return err
+		}
+	}
+
+	return s.repo.Create(c)
+}
+
+// List devuelve todas las categorías en forma plana, con su ruta.
+func (s *CategoryService) List() ([]domain.Category, error) {
+	return s.repo.List()
+}
+
+// Tree devuelve las categorías raíz con sus subcategorías anidadas.
+func (s *CategoryService) Tree() ([]domain.Category, error) {
+
+	list, err := s.repo.List()
+	if err != nil {
+		return nil, err
+	}
+
+	children := make(map[int64][]domain.Category)
+	var roots []domain.Category
+
+	for _, c := range list {
+		if c.ParentID == nil {
+			roots = append(roots, c)
+		} else {
+			children[*c.ParentID] = append(children[*c.ParentID], c)
+		}
+	}
+
+	var attach func(nodes []domain.Category) []domain.Category
+	attach = func(nodes []domain.Category) []domain.Category {
+		for i := range nodes {
+			nodes[i].Hijos = attach(children[nodes[i].ID])
+		}
+		return nodes
+	}
+
+	if roots == nil {
+		return []domain.Category{}, nil
+	}
+
+	return attach(roots), nil
+}
+
+// Update valida que la categoría no quede colgada de sí misma ni de una subcategoría suya.
+func (s *CategoryService) Update(id int64, c *domain.Category) error {
+
+	c.Nombre = strings.TrimSpace(c.Nombre)
+	if id <= 0 || c.Nombre == "" {
+		return domain.ErrInvalidInput
+	}
+
+	if c.ParentID != nil {
+		if _, err := s.repo.Get(*c.ParentID); err != nil {
+			return err
+		}
+		cycle, err := s.repo.IsDescendant(id, *c.ParentID)
+		if err != nil {
+			return err
+		}
+		if cycle {
+			return domain.ErrInvalidInput
+		}
+	}
+
+	c.ID = id
+	return s.repo.Update(id, c)
+}
+
+// Delete elimina una categoría vacía (sin subcategorías ni productos).
+func (s *CategoryService) Delete(id int64) error {
+	if id <= 0 {
+		return domain.ErrInvalidInput
+	}
+	return s.repo.Delete(id)
+}
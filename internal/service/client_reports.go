@@ -0,0 +1,201 @@
+package service
+
+import (
+	"sort"
+	"time"
+
+	"ferreteria-inventario-ventas/internal/domain"
+)
+
+// ClientHistory devuelve el resumen de compras del cliente en [desde, hasta)
+// (fechas en cero = todo el historial), los productos que compró y una página
+// de sus compras.
+func (s *SaleService) ClientHistory(clientID int64, desde, hasta time.Time, p domain.PageParams) (*domain.ClientHistory, error) {
+
+	f := domain.SaleFilter{ClientID: clientID, Desde: desde, Hasta: hasta}
+
+	if clientID <= 0 || !validSaleRange(f) {
+		return nil, domain.ErrInvalidInput
+	}
+	if err := normalizePage(&p); err != nil {
+		return nil, err
+	}
+
+	h, err := s.repo.ClientPurchases(clientID, desde, hasta)
+	if err != nil {
+		return nil, err
+	}
+
+	h.Compras, err = s.repo.ListSales(f, p)
+	if err != nil {
+		return nil, err
+	}
+
+	h.Total = roundCents(h.Total)
+	if h.Ventas > 0 {
+		h.TicketPromedio = roundCents(h.Total / float64(h.Ventas))
+	}
+	for i := range h.Productos {
+		h.Productos[i].Total = roundCents(h.Productos[i].Total)
+	}
+
+	return h, nil
+}
+
+// RFM puntúa a los clientes que compraron alguna vez hasta hasta (por defecto el final
+// de hoy): recencia desde su última compra, y frecuencia y monto de sus compras en
+// [desde, hasta) (por defecto los últimos 12 meses). Cada dimensión se puntúa de 1 a 5
+// por quintiles entre los clientes del reporte y el segmento sale de R y F.
+// Con segment solo devuelve los clientes de ese segmento (los totales por segmento
+// siempre son de todos). Ordenado por monto.
+func (s *SaleService) RFM(desde, hasta time.Time, segment string) (domain.RFMReport, error) {
+
+	if hasta.IsZero() {
+		now := time.Now()
+		hasta = time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, now.Location())
+	}
+	if desde.IsZero() {
+		desde = hasta.AddDate(-1, 0, 0)
+	}
+
+	report := domain.RFMReport{Desde: desde, Hasta: hasta}
+
+	if !desde.Before(hasta) || (segment != "" && !validSegment(segment)) {
+		return report, domain.ErrInvalidInput
+	}
+
+	rows, err := s.repo.ClientActivity(desde, hasta)
+	if err != nil {
+		return report, err
+	}
+
+	// La recencia se mide hasta el momento del reporte (ahora si hasta es futuro)
+	ref := hasta
+	if now := time.Now(); ref.After(now) {
+		ref = now
+	}
+
+	for i := range rows {
+		rows[i].Recencia = max(0, int(ref.Sub(rows[i].UltimaCompra).Hours()/24))
+		rows[i].Monto = roundCents(rows[i].Monto)
+	}
+
+	// Menos días es mejor: se puntúa la recencia con signo negativo
+	quintileScores(rows, func(r *domain.RFMRow) float64 { return -float64(r.Recencia) }, func(r *domain.RFMRow, v int) { r.R = v })
+	quintileScores(rows, func(r *domain.RFMRow) float64 { return float64(r.Frecuencia) }, func(r *domain.RFMRow, v int) { r.F = v })
+	quintileScores(rows, func(r *domain.RFMRow) float64 { return r.Monto }, func(r *domain.RFMRow, v int) { r.M = v })
+
+	segments := make(map[string]*domain.RFMSegment)
+	report.Clientes = []domain.RFMRow{}
+
+	for i := range rows {
+		row := &rows[i]
+		row.Segmento = rfmSegment(row.R, row.F)
+
+		sg := segments[row.Segmento]
+		if sg == nil {
+			sg = &domain.RFMSegment{Segmento: row.Segmento}
+			segments[row.Segmento] = sg
+		}
+		sg.Clientes++
+		sg.Monto += row.Monto
+
+		if segment == "" || row.Segmento == segment {
+			report.Clientes = append(report.Clientes, *row)
+		}
+	}
+
+	sort.SliceStable(report.Clientes, func(i, j int) bool {
+		return report.Clientes[i].Monto > report.Clientes[j].Monto
+	})
+
+	report.Segmentos = []domain.RFMSegment{}
+	for _, name := range rfmSegments {
+		if sg := segments[name]; sg != nil {
+			sg.Monto = roundCents(sg.Monto)
+			report.Segmentos = append(report.Segmentos, *sg)
+		}
+	}
+
+	return report, nil
+}
+
+// rfmSegments es el orden de los segmentos en el reporte, de mejor a peor.
+var rfmSegments = []string{
+	domain.SegmentChampion,
+	domain.SegmentLoyal,
+	domain.SegmentPotentialLoyalist,
+	domain.SegmentNew,
+	domain.SegmentNeedAttention,
+	domain.SegmentCantLose,
+	domain.SegmentAtRisk,
+	domain.SegmentHibernating,
+	domain.SegmentLost,
+}
+
+func validSegment(name string) bool {
+	for _, s := range rfmSegments {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
+
+// rfmSegment asigna el segmento según los puntajes de recencia y frecuencia.
+func rfmSegment(r, f int) string {
+	switch {
+	case r >= 4 && f >= 4:
+		return domain.SegmentChampion
+	case r >= 3 && f >= 4:
+		return domain.SegmentLoyal
+	case r >= 4 && f >= 2:
+		return domain.SegmentPotentialLoyalist
+	case r >= 4:
+		return domain.SegmentNew
+	case r == 3:
+		return domain.SegmentNeedAttention
+	case f == 5:
+		return domain.SegmentCantLose
+	case f >= 3:
+		return domain.SegmentAtRisk
+	case r == 2:
+		return domain.SegmentHibernating
+	default:
+		return domain.SegmentLost
+	}
+}
+
+// quintileScores asigna a cada fila un puntaje de 1 a 5 según la posición de su valor
+// entre todas (5 = el 20 % más alto). Valores iguales reciben el puntaje de la posición
+// promedio del empate, así un grupo que compró el mismo día no queda abajo.
+// Con menos de 5 filas no hay quintiles: el valor más alto recibe 5 y cada fila por
+// debajo un punto menos (nunca baja de 2).
+func quintileScores(rows []domain.RFMRow, value func(*domain.RFMRow) float64, set func(*domain.RFMRow, int)) {
+
+	n := len(rows)
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return value(&rows[order[a]]) < value(&rows[order[b]])
+	})
+
+	for start := 0; start < n; {
+		// end es la última posición del empate
+		end := start
+		for end+1 < n && value(&rows[order[end+1]]) == value(&rows[order[start]]) {
+			end++
+		}
+
+		score := 1 + (start+end)*5/(2*n)
+		if n < 5 {
+			score = 5 - (n - 1 - end)
+		}
+		for _, i := range order[start : end+1] {
+			set(&rows[i], score)
+		}
+		start = end + 1
+	}
+}
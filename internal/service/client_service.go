@@ -1,50 +1,89 @@
 package service
 
-import "ferreteria-inventario-ventas/internal/domain"
+import (
+	"context"
+
+	"ferreteria-inventario-ventas/internal/domain"
+)
 
 // Interfaz que define lo que el repositorio debe implementar.
 type ClientRepository interface {
-	Create(*domain.Client) error
-	List() ([]domain.Client, error)
-	Update(id int64, c *domain.Client) error
-	Delete(id int64) error
+	Create(c *domain.Client, audit domain.AuditFunc) error
+	Get(id int64) (*domain.Client, error)
+	List(f domain.ClientFilter, p domain.PageParams) (domain.Page[domain.Client], error)
+	Each(f domain.ClientFilter, fn func(domain.Client) error) error
+	Update(id int64, c *domain.Client, audit domain.AuditFunc) error
+	Delete(id int64, audit domain.AuditFunc) error
+	SetPriceList(id int64, listID *int64, audit domain.AuditFunc) error
 }
 
 // ClientService contiene la lógica de negocio para clientes.
 type ClientService struct {
-	repo ClientRepository
+	repo  ClientRepository
+	audit Auditor
 }
 
 // Constructor del servicio.
-func NewClientService(r ClientRepository) *ClientService {
-	return &ClientService{repo: r}
+func NewClientService(r ClientRepository, audit Auditor) *ClientService {
+	return &ClientService{repo: r, audit: audit}
 }
 
-// Create valida los datos antes de guardar.
-func (s *ClientService) Create(c *domain.Client) error {
+// Create valida los datos antes de guardar y registra el alta en la auditoría.
+func (s *ClientService) Create(ctx context.Context, c *domain.Client) error {
 
-	if c.Nombre == "" || c.Cedula == "" || c.Email == "" {
+	if c.Nombre == "" || c.Cedula == "" || c.Email == "" || (c.PriceListID != nil && *c.PriceListID <= 0) {
 		return domain.ErrInvalidInput
 	}
 
-	return s.repo.Create(c)
+	return s.repo.Create(c, s.audit.Entry(ctx, domain.AuditCreate, domain.EntityClient))
+}
+
+// List devuelve una página de clientes filtrados.
+func (s *ClientService) List(f domain.ClientFilter, p domain.PageParams) (domain.Page[domain.Client], error) {
+	if err := normalizePage(&p); err != nil {
+		return domain.Page[domain.Client]{}, err
+	}
+	return s.repo.List(f, p)
 }
 
-// List devuelve todos los clientes.
-func (s *ClientService) List() ([]domain.Client, error) {
-	return s.repo.List()
+// Export recorre todos los clientes filtrados sin paginar.
+func (s *ClientService) Export(f domain.ClientFilter, fn func(domain.Client) error) error {
+	return s.repo.Each(f, fn)
 }
 
-func (s *ClientService) Update(id int64, c *domain.Client) error {
+// Update guarda los cambios y registra en la auditoría los campos que cambiaron.
+func (s *ClientService) Update(ctx context.Context, id int64, c *domain.Client) error {
 	if id <= 0 || c.Nombre == "" || c.Cedula == "" || c.Email == "" {
 		return domain.ErrInvalidInput
 	}
-	return s.repo.Update(id, c)
+
+	if err := s.repo.Update(id, c, s.audit.Entry(ctx, domain.AuditUpdate, domain.EntityClient)); err != nil {
+		return err
+	}
+
+	c.ID = id
+	return nil
+}
+
+// AssignPriceList asigna (o con nil quita) la lista de precios del cliente,
+// la registra en la auditoría y devuelve el cliente actualizado.
+func (s *ClientService) AssignPriceList(ctx context.Context, id int64, listID *int64) (*domain.Client, error) {
+	if id <= 0 || (listID != nil && *listID <= 0) {
+		return nil, domain.ErrInvalidInput
+	}
+
+	if err := s.repo.SetPriceList(id, listID, s.audit.Entry(ctx, domain.AuditUpdate, domain.EntityClient)); err != nil {
+		return nil, err
+	}
+
+	return s.repo.Get(id)
 }
 
-func (s *ClientService) Delete(id int64) error {
+// Delete elimina el cliente y registra en la auditoría cómo era.
+func (s *ClientService) Delete(ctx context.Context, id int64) error {
 	if id <= 0 {
 		return domain.ErrInvalidInput
 	}
-	return s.repo.Delete(id)
+
+	return s.repo.Delete(id, s.audit.Entry(ctx, domain.AuditDelete, domain.EntityClient))
 }
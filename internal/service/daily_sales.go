@@ -0,0 +1,38 @@
+package service
+
+import (
+	"time"
+
+	"ferreteria-inventario-ventas/internal/domain"
+)
+
+// RebuildDailySales recalcula el resumen diario por producto con todas las ventas,
+// en la zona horaria actual de la tienda.
+func (s *SaleService) RebuildDailySales() (domain.DailySalesRebuild, error) {
+
+	ventas, filas, err := s.repo.RebuildDailySales()
+	if err != nil {
+		return domain.DailySalesRebuild{}, err
+	}
+
+	return domain.DailySalesRebuild{Zona: time.Local.String(), Ventas: ventas, Filas: filas}, nil
+}
+
+// EnsureDailySales reconstruye el resumen diario si nunca se armó o se armó con
+// otra zona horaria (los días no coincidirían). Devuelve nil si ya estaba al día.
+func (s *SaleService) EnsureDailySales() (*domain.DailySalesRebuild, error) {
+
+	zona, err := s.repo.DailySalesZone()
+	if err != nil {
+		return nil, err
+	}
+	if zona == time.Local.String() {
+		return nil, nil
+	}
+
+	result, err := s.RebuildDailySales()
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
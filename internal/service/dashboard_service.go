@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"ferreteria-inventario-ventas/internal/domain"
+)
+
+// Consultas de ventas que usa el tablero.
+type DashboardSalesRepository interface {
+	SalesTotalsContext(ctx context.Context, ranges []domain.TimeRange) ([]domain.SalesTotals, error)
+	TopProductosContext(ctx context.Context, q domain.TopProductsQuery) ([]domain.TopProduct, error)
+}
+
+// Consultas de productos que usa el tablero.
+type DashboardProductRepository interface {
+	CountLowStock(ctx context.Context, threshold int) (int, error)
+}
+
+// Valores por defecto del tablero.
+const (
+	DefaultLowStock       = 5
+	DashboardTopLimit     = 5
+	DashboardQueryTimeout = 3 * time.Second // Tiempo máximo de cada sección
+)
+
+// DashboardService arma el tablero de la pantalla principal.
+type DashboardService struct {
+	sales    DashboardSalesRepository
+	products DashboardProductRepository
+	timeout  time.Duration
+}
+
+// Constructor del servicio.
+func NewDashboardService(sales DashboardSalesRepository, products DashboardProductRepository) *DashboardService {
+	return &DashboardService{sales: sales, products: products, timeout: DashboardQueryTimeout}
+}
+
+// Dashboard consulta en paralelo las ventas de hoy, de la semana y del mes (cada una
+// contra el mismo tramo del período anterior), la cantidad de productos con stock menor
+// que lowStock (0 = 5) y los productos más vendidos del mes.
+//
+// Cada sección tiene su propio tiempo máximo; las que fallan quedan en null con el motivo
+// en Errores. Si ctx se cancela (el cliente cerró la conexión) se cancelan todas.
+func (s *DashboardService) Dashboard(ctx context.Context, lowStock int) (domain.Dashboard, error) {
+
+	if lowStock == 0 {
+		lowStock = DefaultLowStock
+	}
+	if lowStock < 0 {
+		return domain.Dashboard{}, domain.ErrInvalidInput
+	}
+
+	// Las fechas se guardan al segundo: se redondea hacia arriba para incluir
+	// las ventas del segundo actual
+	now := time.Now().Truncate(time.Second).Add(time.Second)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	week := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+
+	d := domain.Dashboard{Generado: now, UmbralStock: lowStock}
+
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+
+	// run ejecuta una sección en su goroutine con su propio tiempo máximo
+	run := func(name string, fn func(ctx context.Context) error) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			qctx, cancel := context.WithTimeout(ctx, s.timeout)
+			defer cancel()
+
+			if err := fn(qctx); err != nil {
+				msg := err.Error()
+				if errors.Is(err, context.DeadlineExceeded) || errors.Is(qctx.Err(), context.DeadlineExceeded) {
+					msg = "tiempo de consulta agotado"
+				}
+				mu.Lock()
+				if d.Errores == nil {
+					d.Errores = make(map[string]string)
+				}
+				d.Errores[name] = msg
+				mu.Unlock()
+			}
+		}()
+	}
+
+	// Cada goroutine escribe solo su campo de d
+	run(domain.DashboardToday, func(ctx context.Context) (err error) {
+		d.Hoy, err = s.period(ctx, today, now, 0, 1)
+		return err
+	})
+	run(domain.DashboardWeek, func(ctx context.Context) (err error) {
+		d.Semana, err = s.period(ctx, week, now, 0, 7)
+		return err
+	})
+	run(domain.DashboardMonth, func(ctx context.Context) (err error) {
+		d.Mes, err = s.period(ctx, month, now, 1, 0)
+		return err
+	})
+	run(domain.DashboardLowStock, func(ctx context.Context) error {
+		n, err := s.products.CountLowStock(ctx, lowStock)
+		if err != nil {
+			return err
+		}
+		d.StockBajo = &n
+		return nil
+	})
+	run(domain.DashboardTop, func(ctx context.Context) error {
+		list, err := s.sales.TopProductosContext(ctx, domain.TopProductsQuery{
+			Desde:  month,
+			Hasta:  now,
+			Metric: domain.MetricRevenue,
+			Limit:  DashboardTopLimit,
+		})
+		if err != nil {
+			return err
+		}
+		for i := range list {
+			list[i].Total = roundCents(list[i].Total)
+			list[i].Costo = roundCents(list[i].Costo)
+			list[i].Utilidad = roundCents(list[i].Utilidad)
+		}
+		d.TopProductos = list
+		return nil
+	})
+
+	wg.Wait()
+
+	return d, nil
+}
+
+// period devuelve las ventas de [start, now) y las del tramo equivalente del período
+// anterior: ambos límites retroceden months/days (el final no pasa de start,
+// ej: del 1 al 31 de marzo se compara con todo febrero).
+func (s *DashboardService) period(ctx context.Context, start, now time.Time, months, days int) (*domain.DashboardPeriod, error) {
+
+	prevStart := start.AddDate(0, -months, -days)
+	prevEnd := now.AddDate(0, -months, -days)
+	if prevEnd.After(start) {
+		prevEnd = start
+	}
+
+	totals, err := s.sales.SalesTotalsContext(ctx, []domain.TimeRange{
+		{Desde: prevStart, Hasta: prevEnd},
+		{Desde: start, Hasta: now},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	p := &domain.DashboardPeriod{
+		Desde:         start,
+		Hasta:         now,
+		Actual:        finishTotals(totals[1]),
+		AnteriorDesde: prevStart,
+		AnteriorHasta: prevEnd,
+		Anterior:      finishTotals(totals[0]),
+	}
+	if p.Anterior.Total != 0 {
+		v := roundCents((p.Actual.Total - p.Anterior.Total) / p.Anterior.Total * 100)
+		p.VariacionPct = &v
+	}
+
+	return p, nil
+}
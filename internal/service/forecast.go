@@ -0,0 +1,292 @@
+package service
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"ferreteria-inventario-ventas/internal/domain"
+)
+
+// Valores por defecto y límites del pronóstico de reposición.
+const (
+	DefaultForecastDays = 90
+	MaxForecastDays     = 730
+	DefaultSMAWindow    = 28
+	DefaultSESAlpha     = 0.3
+	DefaultLeadTime     = 7
+	DefaultCoverDays    = 30
+	DefaultServiceLevel = 0.95
+	MaxPlanningDays     = 365 // Máximo para plazo de entrega y días de cobertura
+)
+
+// Reorder estima la demanda diaria de cada producto con el historial de ventas
+// (días completos que terminan ayer) y calcula días de cobertura, punto de reorden
+// y cantidad sugerida:
+//
+//	demanda del plazo = demanda diaria estimada durante LeadTime días
+//	stock de seguridad = z(ServiceLevel) * desvío diario * raíz(LeadTime)
+//	punto de reorden = demanda del plazo + stock de seguridad
+//
+// Si el stock llegó al punto de reorden se sugiere pedir hasta cubrir el plazo,
+// CoverDays días más y el stock de seguridad. No hay pedidos en tránsito registrados,
+// así que solo se cuenta el stock actual.
+//
+// Con Season (ej: 7) la demanda se desestacionaliza con índices por posición dentro
+// de la temporada y se vuelve a aplicar al proyectar los días siguientes.
+// Los días anteriores al alta de un producto no cuentan en su historial.
+func (s *SaleService) Reorder(q domain.ReorderQuery) (domain.ReorderReport, error) {
+
+	if q.Days == 0 {
+		q.Days = DefaultForecastDays
+	}
+	if q.Method == "" {
+		q.Method = domain.ForecastSMA
+	}
+	// Cada método usa solo su parámetro
+	switch q.Method {
+	case domain.ForecastSMA:
+		q.Alpha = 0
+		if q.Window == 0 {
+			q.Window = min(DefaultSMAWindow, q.Days)
+		}
+	case domain.ForecastSES:
+		q.Window = 0
+		if q.Alpha == 0 {
+			q.Alpha = DefaultSESAlpha
+		}
+	}
+	if q.LeadTime == 0 {
+		q.LeadTime = DefaultLeadTime
+	}
+	if q.CoverDays == 0 {
+		q.CoverDays = DefaultCoverDays
+	}
+	if q.ServiceLevel == 0 {
+		q.ServiceLevel = DefaultServiceLevel
+	}
+
+	report := domain.ReorderReport{
+		Metodo:        q.Method,
+		Ventana:       q.Window,
+		Alpha:         q.Alpha,
+		Temporada:     q.Season,
+		PlazoDias:     q.LeadTime,
+		CoberturaDias: q.CoverDays,
+		NivelServicio: q.ServiceLevel,
+	}
+
+	if !validReorderQuery(q) {
+		return report, domain.ErrInvalidInput
+	}
+
+	now := time.Now()
+	report.Hasta = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	report.Desde = report.Hasta.AddDate(0, 0, -q.Days)
+
+	days, err := summaryBuckets(report.Desde, report.Hasta, domain.PeriodDay)
+	if err != nil {
+		return report, err
+	}
+	ranges := make([]domain.TimeRange, len(days))
+	for i, d := range days {
+		ranges[i] = domain.TimeRange{Desde: d.Desde, Hasta: d.Hasta}
+	}
+
+	series, err := s.repo.DemandHistory(ranges, q.CategoryID)
+	if err != nil {
+		return report, err
+	}
+
+	z := math.Sqrt2 * math.Erfinv(2*q.ServiceLevel-1)
+
+	report.Productos = []domain.ReorderRow{}
+
+	for _, d := range series {
+
+		// Los días antes del alta no son demanda cero: se descartan
+		start := 0
+		if d.Alta != nil {
+			for start < len(ranges) && !ranges[start].Hasta.After(*d.Alta) {
+				start++
+			}
+		}
+
+		row := forecastProduct(d, start, len(ranges), q, z)
+		if q.OnlyReorder && row.Sugerido == 0 {
+			continue
+		}
+		report.Productos = append(report.Productos, row)
+	}
+
+	// Primero los que se quedan antes sin stock; sin demanda al final
+	sort.SliceStable(report.Productos, func(i, j int) bool {
+		a, b := report.Productos[i].DiasCobertura, report.Productos[j].DiasCobertura
+		if a == nil || b == nil {
+			return a != nil && b == nil
+		}
+		return *a < *b
+	})
+
+	return report, nil
+}
+
+// validReorderQuery verifica los parámetros ya completados con los valores por defecto.
+func validReorderQuery(q domain.ReorderQuery) bool {
+
+	switch q.Method {
+	case domain.ForecastSMA:
+		if q.Window < 1 || q.Window > q.Days {
+			return false
+		}
+	case domain.ForecastSES:
+		if q.Alpha <= 0 || q.Alpha > 1 {
+			return false
+		}
+	default:
+		return false
+	}
+
+	return q.Days >= 1 && q.Days <= MaxForecastDays &&
+		q.Season >= 0 && q.Season != 1 && q.Season*2 <= q.Days &&
+		q.LeadTime >= 1 && q.LeadTime <= MaxPlanningDays &&
+		q.CoverDays >= 0 && q.CoverDays <= MaxPlanningDays &&
+		q.ServiceLevel >= 0.5 && q.ServiceLevel <= 0.999 &&
+		q.CategoryID >= 0
+}
+
+// forecastProduct arma la fila de un producto con las ventas de los días [start, total).
+func forecastProduct(d domain.DemandSeries, start, total int, q domain.ReorderQuery, z float64) domain.ReorderRow {
+
+	row := domain.ReorderRow{
+		ProductID: d.ProductID,
+		SKU:       d.SKU,
+		Producto:  d.Producto,
+		Stock:     d.Stock,
+	}
+
+	units := d.Unidades[start:]
+	for _, u := range units {
+		row.UnidadesPeriodo += u
+	}
+
+	if len(units) == 0 {
+		return row
+	}
+
+	// Índices estacionales por posición absoluta dentro de la temporada
+	var indices []float64
+	if q.Season > 0 && len(units) >= 2*q.Season {
+		indices = seasonalIndices(units, start, q.Season)
+	}
+	seasonal := func(day int) float64 {
+		if indices == nil {
+			return 1
+		}
+		return indices[day%q.Season]
+	}
+
+	// Serie desestacionalizada
+	xs := make([]float64, len(units))
+	for i, u := range units {
+		if idx := seasonal(start + i); idx > 0 {
+			xs[i] = float64(u) / idx
+		}
+	}
+
+	var level float64
+	switch q.Method {
+	case domain.ForecastSMA:
+		level = mean(xs[max(0, len(xs)-q.Window):])
+	case domain.ForecastSES:
+		level = xs[0]
+		for _, x := range xs[1:] {
+			level = q.Alpha*x + (1-q.Alpha)*level
+		}
+	}
+	sigma := stdDev(xs)
+
+	// Proyección de los días siguientes (el día total es hoy)
+	var demandaPlazo, demandaCobertura float64
+	for h := 0; h < q.LeadTime+q.CoverDays; h++ {
+		f := level * seasonal(total+h)
+		if h < q.LeadTime {
+			demandaPlazo += f
+		} else {
+			demandaCobertura += f
+		}
+	}
+
+	safety := z * sigma * math.Sqrt(float64(q.LeadTime))
+	reorderPoint := demandaPlazo + safety
+
+	if float64(d.Stock) <= reorderPoint && level > 0 {
+		target := reorderPoint + demandaCobertura
+		row.Sugerido = int(math.Ceil(target - float64(d.Stock)))
+	}
+
+	if level > 0 {
+		cover := roundCents(float64(d.Stock) / level)
+		row.DiasCobertura = &cover
+	}
+
+	row.PromedioDiario = roundCents(level)
+	row.DesvioDiario = roundCents(sigma)
+	row.DemandaPlazo = roundCents(demandaPlazo)
+	row.StockSeguridad = roundCents(safety)
+	row.PuntoReorden = roundCents(reorderPoint)
+	row.CostoSugerido = roundCents(float64(row.Sugerido) * d.Costo)
+
+	return row
+}
+
+// seasonalIndices devuelve, para cada posición de la temporada, el promedio de
+// ventas de esa posición dividido por el promedio general (1 = día normal).
+// units[0] corresponde al día absoluto offset del historial.
+func seasonalIndices(units []int, offset, season int) []float64 {
+
+	sums := make([]float64, season)
+	counts := make([]float64, season)
+	var total float64
+
+	for i, u := range units {
+		p := (offset + i) % season
+		sums[p] += float64(u)
+		counts[p]++
+		total += float64(u)
+	}
+
+	avg := total / float64(len(units))
+	if avg == 0 {
+		return nil
+	}
+
+	indices := make([]float64, season)
+	for p := range indices {
+		indices[p] = sums[p] / counts[p] / avg
+	}
+	return indices
+}
+
+func mean(xs []float64) float64 {
+	if len(xs) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, x := range xs {
+		sum += x
+	}
+	return sum / float64(len(xs))
+}
+
+func stdDev(xs []float64) float64 {
+	if len(xs) < 2 {
+		return 0
+	}
+	m := mean(xs)
+	var sum float64
+	for _, x := range xs {
+		sum += (x - m) * (x - m)
+	}
+	return math.Sqrt(sum / float64(len(xs)-1))
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"sort"
+	"time"
+
+	"ferreteria-inventario-ventas/internal/domain"
+)
+
+// DefaultHeatmapDays son 12 semanas completas: cada día de la semana aparece 12 veces.
+const DefaultHeatmapDays = 84
+
+var heatmapDays = []string{"lunes", "martes", "miércoles", "jueves", "viernes", "sábado", "domingo"}
+
+// Heatmap arma la matriz día de la semana × hora de las ventas de [desde, hasta)
+// en la hora local de la tienda. Por defecto los últimos 84 días completos (hasta ayer).
+//
+// Además de las sumas, cada celda tiene el promedio y la mediana por ocurrencia
+// (cada lunes de 10 a 11 del rango es una ocurrencia, con o sin ventas). La mediana
+// no se mueve por una semana con ventas fuera de lo normal, el promedio sí.
+// Una hora solo cuenta como ocurrencia si su inicio cae dentro del rango.
+func (s *SaleService) Heatmap(desde, hasta time.Time) (domain.SalesHeatmap, error) {
+
+	if hasta.IsZero() {
+		now := time.Now()
+		hasta = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	}
+	if desde.IsZero() {
+		desde = hasta.AddDate(0, 0, -DefaultHeatmapDays)
+	}
+
+	report := domain.SalesHeatmap{Desde: desde, Hasta: hasta, Dias: heatmapDays}
+
+	if !desde.Before(hasta) {
+		return report, domain.ErrInvalidInput
+	}
+
+	days, err := summaryBuckets(desde, hasta, domain.PeriodDay)
+	if err != nil {
+		return report, err
+	}
+
+	index := make(map[string]int, len(days))
+	for i, d := range days {
+		index[d.Periodo] = i
+	}
+
+	// Ventas y monto de cada hora de cada día del rango
+	counts := make([][24]int, len(days))
+	totals := make([][24]float64, len(days))
+
+	err = s.repo.EachSale(domain.SaleFilter{Desde: desde, Hasta: hasta}, func(sale domain.Sale) error {
+		t := sale.Fecha.In(time.Local)
+		i, ok := index[t.Format(time.DateOnly)]
+		if !ok {
+			return nil
+		}
+		counts[i][t.Hour()]++
+		totals[i][t.Hour()] += sale.Total
+		return nil
+	})
+	if err != nil {
+		return report, err
+	}
+
+	// Valores por ocurrencia de cada celda
+	type sample struct {
+		ventas []float64
+		total  []float64
+	}
+	var samples [7][24]sample
+
+	for i, d := range days {
+		local := d.Desde.In(time.Local)
+		wd := (int(local.Weekday()) + 6) % 7
+
+		for h := 0; h < 24; h++ {
+			start := time.Date(local.Year(), local.Month(), local.Day(), h, 0, 0, 0, time.Local)
+			// Horas que no existen por el cambio de horario o fuera del rango
+			if start.Hour() != h || start.Before(desde) || !start.Before(hasta) {
+				continue
+			}
+			samples[wd][h].ventas = append(samples[wd][h].ventas, float64(counts[i][h]))
+			samples[wd][h].total = append(samples[wd][h].total, totals[i][h])
+		}
+	}
+
+	report.Matriz = make([][]domain.HeatmapCell, 7)
+
+	for wd := range report.Matriz {
+		report.Matriz[wd] = make([]domain.HeatmapCell, 24)
+
+		for h := range report.Matriz[wd] {
+			sm := samples[wd][h]
+			c := domain.HeatmapCell{Dia: wd, Hora: h, Ocurrencias: len(sm.ventas)}
+
+			for k := range sm.ventas {
+				c.Ventas += int(sm.ventas[k])
+				c.Total += sm.total[k]
+			}
+
+			c.Total = roundCents(c.Total)
+			c.PromedioVentas = roundCents(mean(sm.ventas))
+			c.MedianaVentas = roundCents(median(sm.ventas))
+			c.PromedioTotal = roundCents(mean(sm.total))
+			c.MedianaTotal = roundCents(median(sm.total))
+
+			report.Ventas += c.Ventas
+			report.Total += c.Total
+			report.Matriz[wd][h] = c
+		}
+	}
+	report.Total = roundCents(report.Total)
+
+	return report, nil
+}
+
+func median(xs []float64) float64 {
+	n := len(xs)
+	if n == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), xs...)
+	sort.Float64s(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
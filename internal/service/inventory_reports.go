@@ -0,0 +1,143 @@
+package service
+
+import (
+	"time"
+
+	"ferreteria-inventario-ventas/internal/domain"
+)
+
+// Valuation valoriza el inventario al costo promedio y a precio de venta,
+// con subtotales por categoría (la propia del producto) y total general.
+// asOf en cero valoriza el stock actual; con fecha, el stock a ese momento
+// reconstruido desde las ventas y los movimientos de stock.
+func (s *ProductService) Valuation(asOf time.Time, categoryID int64) (domain.InventoryValuation, error) {
+
+	now := time.Now()
+
+	// El cierre de hoy (medianoche siguiente) es el stock actual; más adelante no se conoce
+	if categoryID < 0 || asOf.After(now.AddDate(0, 0, 1)) {
+		return domain.InventoryValuation{}, domain.ErrInvalidInput
+	}
+	if asOf.After(now) {
+		asOf = time.Time{}
+	}
+
+	rows, err := s.repo.Valuation(asOf, categoryID)
+	if err != nil {
+		return domain.InventoryValuation{}, err
+	}
+
+	v := domain.InventoryValuation{Fecha: asOf, Categorias: []domain.ValuationCategory{}}
+	if asOf.IsZero() {
+		v.Fecha = now.Truncate(time.Second)
+	}
+
+	// Las filas vienen ordenadas por categoría: se corta el grupo cuando cambia
+	for _, row := range rows {
+		row.ValorCosto = roundCents(float64(row.Cantidad) * row.CostoUnitario)
+		row.ValorVenta = roundCents(float64(row.Cantidad) * row.PrecioUnitario)
+		row.CostoUnitario = roundCents(row.CostoUnitario)
+
+		n := len(v.Categorias)
+		if n == 0 || !sameCategory(v.Categorias[n-1].CategoryID, row.CategoryID) {
+			nombre := row.Categoria
+			if row.CategoryID == nil {
+				nombre = "Sin categoría"
+			}
+			v.Categorias = append(v.Categorias, domain.ValuationCategory{CategoryID: row.CategoryID, Categoria: nombre})
+			n++
+		}
+
+		c := &v.Categorias[n-1]
+		c.Productos = append(c.Productos, row)
+		addValuation(&c.Subtotal, row)
+		addValuation(&v.Totales, row)
+	}
+
+	for i := range v.Categorias {
+		roundValuation(&v.Categorias[i].Subtotal)
+	}
+	roundValuation(&v.Totales)
+
+	return v, nil
+}
+
+// ExportValuation recorre las filas de Valuation sin agrupar (para exportar).
+func (s *ProductService) ExportValuation(asOf time.Time, categoryID int64, fn func(domain.ValuationRow) error) error {
+
+	v, err := s.Valuation(asOf, categoryID)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range v.Categorias {
+		for _, row := range c.Productos {
+			if err := fn(row); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func sameCategory(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func addValuation(t *domain.ValuationTotals, row domain.ValuationRow) {
+	t.Productos++
+	t.Cantidad += row.Cantidad
+	t.ValorCosto += row.ValorCosto
+	t.ValorVenta += row.ValorVenta
+}
+
+func roundValuation(t *domain.ValuationTotals) {
+	t.ValorCosto = roundCents(t.ValorCosto)
+	t.ValorVenta = roundCents(t.ValorVenta)
+}
+
+// DefaultDeadStockDays es la antigüedad por defecto del stock sin movimiento.
+const DefaultDeadStockDays = 180
+
+// DeadStock lista los productos con stock sin ventas desde since y el capital
+// inmovilizado en ellos (al costo promedio actual). since en cero = hace 180 días.
+func (s *ProductService) DeadStock(since time.Time, categoryID int64) (domain.DeadStockReport, error) {
+
+	now := time.Now()
+	if since.IsZero() {
+		since = now.AddDate(0, 0, -DefaultDeadStockDays)
+	}
+
+	report := domain.DeadStockReport{Desde: since}
+
+	if categoryID < 0 || since.After(now) {
+		return report, domain.ErrInvalidInput
+	}
+
+	rows, err := s.repo.DeadStock(since, categoryID)
+	if err != nil {
+		return report, err
+	}
+
+	for i := range rows {
+		d := &rows[i]
+		d.Costo = roundCents(d.Costo)
+		d.Capital = roundCents(float64(d.Stock) * d.Costo)
+		if d.UltimaVenta != nil {
+			days := int(now.Sub(*d.UltimaVenta).Hours() / 24)
+			d.DiasSinVenta = &days
+		}
+
+		report.Unidades += d.Stock
+		report.Capital += d.Capital
+	}
+
+	report.Productos = rows
+	report.Capital = roundCents(report.Capital)
+
+	return report, nil
+}
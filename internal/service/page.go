@@ -0,0 +1,20 @@
+package service
+
+import "ferreteria-inventario-ventas/internal/domain"
+
+// Límites de paginación para todos los listados.
+const (
+	DefaultPageLimit = 50
+	MaxPageLimit     = 500
+)
+
+// normalizePage aplica el límite por defecto y valida el máximo.
+func normalizePage(p *domain.PageParams) error {
+	if p.Limit < 0 || p.Limit > MaxPageLimit {
+		return domain.ErrInvalidInput
+	}
+	if p.Limit == 0 {
+		p.Limit = DefaultPageLimit
+	}
+	return nil
+}
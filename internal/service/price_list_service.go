@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"ferreteria-inventario-ventas/internal/domain"
+)
+
+// Interfaz que debe cumplir el repositorio de listas de precios.
+type PriceListRepository interface {
+	Create(l *domain.PriceList, audit domain.AuditFunc) error
+	Get(id int64) (*domain.PriceList, error)
+	List() ([]domain.PriceList, error)
+	Update(id int64, l *domain.PriceList, audit domain.AuditFunc) error
+	Delete(id int64, audit domain.AuditFunc) error
+}
+
+// PriceListService contiene la lógica de negocio para listas de precios.
+type PriceListService struct {
+	repo  PriceListRepository
+	audit Auditor
+}
+
+// Constructor del servicio.
+func NewPriceListService(r PriceListRepository, audit Auditor) *PriceListService {
+	return &PriceListService{repo: r, audit: audit}
+}
+
+// Create valida la lista y sus reglas antes de guardar y registra el alta en la auditoría.
+func (s *PriceListService) Create(ctx context.Context, l *domain.PriceList) error {
+
+	if err := validatePriceList(l); err != nil {
+		return err
+	}
+
+	// El repositorio deja en l los nombres de productos y categorías de las reglas
+	return s.repo.Create(l, s.audit.Entry(ctx, domain.AuditCreate, domain.EntityList))
+}
+
+// validatePriceList exige nombre y reglas válidas: a lo sumo producto o categoría,
+// cantidad mínima desde 1 (0 se toma como 1), y precio fijo (solo por producto)
+// o porcentaje, no los dos.
+func validatePriceList(l *domain.PriceList) error {
+
+	l.Nombre = strings.TrimSpace(l.Nombre)
+	l.Descripcion = strings.TrimSpace(l.Descripcion)
+	if l.Nombre == "" {
+		return domain.ErrInvalidInput
+	}
+	if l.Reglas == nil {
+		l.Reglas = []domain.PriceListRule{}
+	}
+
+	for i := range l.Reglas {
+		rule := &l.Reglas[i]
+		if rule.CantidadMin == 0 {
+			rule.CantidadMin = 1
+		}
+
+		switch {
+		case rule.ProductID != nil && rule.CategoryID != nil:
+			return domain.ErrInvalidInput
+		case rule.ProductID != nil && *rule.ProductID <= 0, rule.CategoryID != nil && *rule.CategoryID <= 0:
+			return domain.ErrInvalidInput
+		case rule.CantidadMin < 1:
+			return domain.ErrInvalidInput
+		case (rule.Precio == nil) == (rule.Porcentaje == nil):
+			return domain.ErrInvalidInput
+		case rule.Precio != nil && (*rule.Precio <= 0 || rule.ProductID == nil):
+			return domain.ErrInvalidInput
+		case rule.Porcentaje != nil && !validPercent(*rule.Porcentaje):
+			return domain.ErrInvalidInput
+		}
+	}
+
+	return nil
+}
+
+// Get devuelve una lista con sus reglas.
+func (s *PriceListService) Get(id int64) (*domain.PriceList, error) {
+	if id <= 0 {
+		return nil, domain.ErrInvalidInput
+	}
+	return s.repo.Get(id)
+}
+
+// List devuelve todas las listas con sus reglas.
+func (s *PriceListService) List() ([]domain.PriceList, error) {
+	return s.repo.List()
+}
+
+// Update reemplaza nombre, descripción y reglas y registra el cambio en la auditoría.
+// Las ventas ya hechas conservan su precio.
+func (s *PriceListService) Update(ctx context.Context, id int64, l *domain.PriceList) error {
+
+	if id <= 0 {
+		return domain.ErrInvalidInput
+	}
+	if err := validatePriceList(l); err != nil {
+		return err
+	}
+
+	return s.repo.Update(id, l, s.audit.Entry(ctx, domain.AuditUpdate, domain.EntityList))
+}
+
+// Delete elimina una lista sin clientes ni ventas y registra en la auditoría cómo era.
+func (s *PriceListService) Delete(ctx context.Context, id int64) error {
+	if id <= 0 {
+		return domain.ErrInvalidInput
+	}
+
+	return s.repo.Delete(id, s.audit.Entry(ctx, domain.AuditDelete, domain.EntityList))
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"ferreteria-inventario-ventas/internal/domain"
+)
+
+// Interfaz que debe cumplir el repositorio de precios.
+type PriceRepository interface {
+	History(productID int64) (*domain.PriceHistory, error)
+	PriceAt(productID int64, at time.Time) (*domain.PriceAt, error)
+	CreateChange(c *domain.PriceChange, audit domain.AuditFunc) error
+	CreateBulk(b domain.BulkPriceChange, creado time.Time, audit domain.AuditFunc) ([]domain.PriceChange, error)
+	GetChange(id int64) (*domain.PriceChange, error)
+	ListChanges(f domain.PriceChangeFilter, p domain.PageParams) (domain.Page[domain.PriceChange], error)
+	CancelChange(id int64, audit domain.AuditFunc) error
+	ApplyDue(now time.Time, audit domain.AuditFunc) ([]domain.AppliedPrice, error)
+}
+
+// PriceService contiene la lógica del historial de precios y los cambios programados.
+type PriceService struct {
+	repo  PriceRepository
+	audit Auditor
+}
+
+// Constructor del servicio.
+func NewPriceService(r PriceRepository, audit Auditor) *PriceService {
+	return &PriceService{repo: r, audit: audit}
+}
+
+// History devuelve el historial de precios de un producto y sus cambios pendientes.
+func (s *PriceService) History(productID int64) (*domain.PriceHistory, error) {
+	if productID <= 0 {
+		return nil, domain.ErrInvalidInput
+	}
+	return s.repo.History(productID)
+}
+
+// PriceAt devuelve el precio que tenía el producto en la fecha at.
+func (s *PriceService) PriceAt(productID int64, at time.Time) (*domain.PriceAt, error) {
+	if productID <= 0 || at.IsZero() {
+		return nil, domain.ErrInvalidInput
+	}
+	return s.repo.PriceAt(productID, at)
+}
+
+// Schedule programa un cambio de precio (precio fijo o porcentaje, no los dos).
+// Sin fecha, o con una fecha que ya pasó, se aplica en el momento.
+func (s *PriceService) Schedule(ctx context.Context, c *domain.PriceChange) error {
+
+	switch {
+	case c.ProductID <= 0:
+		return domain.ErrInvalidInput
+	case (c.Precio == nil) == (c.Porcentaje == nil):
+		return domain.ErrInvalidInput
+	case c.Precio != nil && *c.Precio <= 0:
+		return domain.ErrInvalidInput
+	case c.Porcentaje != nil && !validPercent(*c.Porcentaje):
+		return domain.ErrInvalidInput
+	}
+
+	now := time.Now().Truncate(time.Second)
+	c.Creado = now
+	if c.Desde.IsZero() {
+		c.Desde = now
+	}
+
+	if err := s.repo.CreateChange(c, s.audit.Entry(ctx, domain.AuditCreate, domain.EntityPrice)); err != nil {
+		return err
+	}
+
+	if c.Desde.After(now) {
+		return nil
+	}
+
+	if _, err := s.ApplyDue(ctx); err != nil {
+		return err
+	}
+	applied, err := s.repo.GetChange(c.ID)
+	if err != nil {
+		return err
+	}
+	*c = *applied
+	return nil
+}
+
+// Bulk programa un aumento porcentual para todos los productos de una categoría
+// (con subcategorías) o de un proveedor. Sin fecha, o con una que ya pasó, se aplica
+// en el momento. ErrNotFound si ningún producto cumple el filtro.
+func (s *PriceService) Bulk(ctx context.Context, b domain.BulkPriceChange) (*domain.BulkPriceResult, error) {
+
+	b.Proveedor = strings.TrimSpace(b.Proveedor)
+	if (b.CategoryID > 0) == (b.Proveedor != "") || b.CategoryID < 0 || !validPercent(b.Porcentaje) {
+		return nil, domain.ErrInvalidInput
+	}
+
+	now := time.Now().Truncate(time.Second)
+	if b.Desde.IsZero() {
+		b.Desde = now
+	}
+
+	list, err := s.repo.CreateBulk(b, now, s.audit.Entry(ctx, domain.AuditCreate, domain.EntityPrice))
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, domain.ErrNotFound
+	}
+
+	result := &domain.BulkPriceResult{Productos: len(list), Cambios: list}
+
+	if b.Desde.After(now) {
+		return result, nil
+	}
+
+	applied, err := s.ApplyDue(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byChange := make(map[int64]domain.AppliedPrice, len(applied))
+	for _, a := range applied {
+		byChange[a.ChangeID] = a
+	}
+	for i := range result.Cambios {
+		c := &result.Cambios[i]
+		a, ok := byChange[c.ID]
+		if !ok {
+			continue
+		}
+		c.Estado = domain.PriceChangeApplied
+		c.Aplicado = &now
+		c.PrecioAnterior = &a.Antes
+		c.PrecioNuevo = &a.Despues
+		result.Aplicados++
+	}
+
+	return result, nil
+}
+
+// validPercent acepta aumentos y rebajas, pero no una rebaja del 100% o más.
+func validPercent(pct float64) bool {
+	return pct != 0 && pct > -100 && pct <= 1000
+}
+
+// List devuelve una página de cambios programados, por defecto los más recientes primero.
+func (s *PriceService) List(f domain.PriceChangeFilter, p domain.PageParams) (domain.Page[domain.PriceChange], error) {
+	if err := normalizePage(&p); err != nil {
+		return domain.Page[domain.PriceChange]{}, err
+	}
+	switch f.Estado {
+	case "", domain.PriceChangePending, domain.PriceChangeApplied, domain.PriceChangeCancelled:
+	default:
+		return domain.Page[domain.PriceChange]{}, domain.ErrInvalidInput
+	}
+	return s.repo.ListChanges(f, p)
+}
+
+// Get devuelve un cambio programado.
+func (s *PriceService) Get(id int64) (*domain.PriceChange, error) {
+	if id <= 0 {
+		return nil, domain.ErrInvalidInput
+	}
+	return s.repo.GetChange(id)
+}
+
+// Cancel cancela un cambio pendiente y lo registra en la auditoría.
+// ErrConflict si ya se aplicó o se canceló.
+func (s *PriceService) Cancel(ctx context.Context, id int64) error {
+	if id <= 0 {
+		return domain.ErrInvalidInput
+	}
+
+	return s.repo.CancelChange(id, s.audit.Entry(ctx, domain.AuditUpdate, domain.EntityPrice))
+}
+
+// ApplyDue aplica los cambios programados cuya fecha ya llegó. Cada precio que cambia
+// queda en la auditoría como una modificación del producto.
+func (s *PriceService) ApplyDue(ctx context.Context) ([]domain.AppliedPrice, error) {
+	return s.repo.ApplyDue(time.Now().Truncate(time.Second), s.audit.Entry(ctx, domain.AuditUpdate, domain.EntityProduct))
+}
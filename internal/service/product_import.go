@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	"ferreteria-inventario-ventas/internal/domain"
+)
+
+// MaxImportRows es el máximo de filas de datos por importación.
+const MaxImportRows = 20000
+
+// importColumns asocia los nombres de cabecera aceptados (ya normalizados) con cada campo.
+var importColumns = map[string]string{
+	"nombre": "nombre", "name": "nombre", "producto": "nombre", "descripcion": "nombre",
+	"sku": "sku", "codigo": "sku", "codigo_interno": "sku",
+	"stock": "stock", "cantidad": "stock", "existencia": "stock", "existencias": "stock",
+	"precio": "precio", "price": "precio", "pvp": "precio", "precio_venta": "precio",
+	"costo": "costo", "cost": "costo", "costo_unitario": "costo",
+	"codigo_barras": "barcodes", "codigos_barras": "barcodes", "codigo_de_barras": "barcodes", "codigos_de_barras": "barcodes", "barcode": "barcodes", "barcodes": "barcodes", "ean": "barcodes",
+	"categoria": "categoria", "category": "categoria",
+	"marca": "marca", "brand": "marca",
+}
+
+// Import carga productos desde filas de una tabla (la primera fila es la cabecera).
+// Las columnas se reconocen por el nombre de la cabecera, sin importar el orden;
+// las columnas desconocidas se ignoran y las celdas vacías conservan el valor actual.
+// Con dryRun solo valida; sin dryRun guarda todas las filas o ninguna.
+// Cada producto creado o actualizado queda en la auditoría con sus campos antes y después.
+func (s *ProductService) Import(ctx context.Context, table [][]string, dryRun bool) (domain.ImportReport, error) {
+
+	report := domain.ImportReport{DryRun: dryRun}
+
+	if len(table) == 0 {
+		return report, domain.ErrInvalidInput
+	}
+
+	columns, headerErr := mapImportHeader(table[0])
+	if headerErr != "" {
+		report.Errores = []domain.RowError{{Row: 1, Error: headerErr}}
+		return report, nil
+	}
+
+	var rows []domain.ProductImportRow
+
+	for i, record := range table[1:] {
+		if isBlankRecord(record) {
+			continue
+		}
+
+		row, msg := parseImportRow(i+2, record, columns)
+		if msg != "" {
+			report.Errores = append(report.Errores, domain.RowError{Row: i + 2, Error: msg})
+			continue
+		}
+		rows = append(rows, row)
+	}
+
+	if len(rows)+len(report.Errores) > MaxImportRows {
+		report.Errores = []domain.RowError{{Row: 1, Error: "el archivo supera el máximo de " + strconv.Itoa(MaxImportRows) + " filas"}}
+		return report, nil
+	}
+
+	// Si ya hay errores de formato se sigue validando contra la base,
+	// pero sin guardar nada.
+	result, err := s.repo.Import(rows, dryRun || len(report.Errores) > 0, productProblem,
+		s.audit.Entry(ctx, domain.AuditImport, domain.EntityProduct))
+	if err != nil {
+		return report, err
+	}
+
+	result.DryRun = dryRun
+	result.Filas += len(report.Errores)
+	result.Errores = append(result.Errores, report.Errores...)
+	sort.SliceStable(result.Errores, func(a, b int) bool {
+		return result.Errores[a].Row < result.Errores[b].Row
+	})
+
+	return result, nil
+}
+
+// mapImportHeader devuelve el índice de columna de cada campo reconocido.
+func mapImportHeader(header []string) (map[string]int, string) {
+
+	columns := make(map[string]int)
+
+	for i, name := range header {
+		field, ok := importColumns[normalizeHeader(name)]
+		if !ok {
+			continue
+		}
+		if _, dup := columns[field]; dup {
+			return nil, "columna repetida: " + name
+		}
+		columns[field] = i
+	}
+
+	_, hasNombre := columns["nombre"]
+	_, hasSKU := columns["sku"]
+	if !hasNombre && !hasSKU {
+		return nil, "falta la columna nombre o sku"
+	}
+
+	return columns, ""
+}
+
+// normalizeHeader pasa la cabecera a minúsculas sin tildes y con guiones bajos.
+func normalizeHeader(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = strings.NewReplacer("á", "a", "é", "e", "í", "i", "ó", "o", "ú", "u", "ñ", "n", "ü", "u").Replace(name)
+	return strings.Join(strings.FieldsFunc(name, func(r rune) bool {
+		return r == ' ' || r == '-' || r == '_' || r == '.'
+	}), "_")
+}
+
+// parseImportRow convierte una fila de texto en ProductImportRow.
+func parseImportRow(num int, record []string, columns map[string]int) (domain.ProductImportRow, string) {
+
+	row := domain.ProductImportRow{Row: num}
+
+	cell := func(field string) (string, bool) {
+		i, ok := columns[field]
+		if !ok || i >= len(record) {
+			return "", false
+		}
+		v := strings.TrimSpace(record[i])
+		return v, v != ""
+	}
+
+	row.Nombre, _ = cell("nombre")
+	row.SKU, _ = cell("sku")
+
+	if v, ok := cell("stock"); ok {
+		n, msg := parseDecimal("stock", v)
+		if msg != "" {
+			return row, msg
+		}
+		if n != float64(int(n)) {
+			return row, "stock debe ser un número entero: " + v
+		}
+		stock := int(n)
+		row.Stock = &stock
+	}
+
+	if v, ok := cell("precio"); ok {
+		n, msg := parseDecimal("precio", strings.TrimPrefix(v, "$"))
+		if msg != "" {
+			return row, msg
+		}
+		row.Precio = &n
+	}
+
+	if v, ok := cell("costo"); ok {
+		n, msg := parseDecimal("costo", strings.TrimPrefix(v, "$"))
+		if msg != "" {
+			return row, msg
+		}
+		row.Costo = &n
+	}
+
+	if v, ok := cell("barcodes"); ok {
+		row.Barcodes = strings.FieldsFunc(v, func(r rune) bool {
+			return r == ';' || r == '|' || r == ',' || r == ' '
+		})
+	}
+
+	if v, ok := cell("categoria"); ok {
+		row.Categoria = &v
+	}
+	if v, ok := cell("marca"); ok {
+		row.Marca = &v
+	}
+
+	if row.Nombre == "" && row.SKU == "" {
+		return row, "fila sin nombre ni sku"
+	}
+
+	return row, ""
+}
+
+// parseDecimal convierte un número con coma o punto decimal ("8,50" o "8.50").
+// En vez de adivinar, devuelve el motivo del error si trae separador de miles
+// ("1.234,50") o si es ambiguo: "1,000" o "1.000" pueden ser mil o uno.
+func parseDecimal(field, v string) (float64, string) {
+
+	if strings.Count(v, ",")+strings.Count(v, ".") > 1 {
+		return 0, field + " con separador de miles (escriba 1234,50 o 1234.50): " + v
+	}
+	if i := strings.IndexAny(v, ",."); i >= 0 && len(v)-i-1 == 3 && strings.TrimLeft(v[:i], "-0") != "" {
+		return 0, field + " ambiguo, puede ser miles o decimales (escriba 1000 o 1,00): " + v
+	}
+
+	n, err := strconv.ParseFloat(strings.Replace(v, ",", ".", 1), 64)
+	if err != nil {
+		return 0, field + " no es un número: " + v
+	}
+	return n, ""
+}
+
+func isBlankRecord(record []string) bool {
+	for _, v := range record {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,57 @@
+package service_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"ferreteria-inventario-ventas/internal/service"
+	"ferreteria-inventario-ventas/internal/storage/sqlite"
+)
+
+// Los números con separador de miles o ambiguos quedan como error de la fila
+// en vez de importarse con otro valor.
+func TestImportRejectsAmbiguousNumbers(t *testing.T) {
+
+	db, err := sqlite.OpenDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := sqlite.Migrate(db, filepath.Join("..", "..", "migrations", "schema.sql")); err != nil {
+		t.Fatal(err)
+	}
+
+	audit := service.NewAuditService(sqlite.NewAuditRepo(db))
+	products := service.NewProductService(sqlite.NewProductRepo(db), audit)
+
+	tests := []struct {
+		precio  string
+		wantErr bool
+	}{
+		{"8,50", false},
+		{"8.50", false},
+		{"$12", false},
+		{"0,125", false},
+		{"1,000", true},
+		{"1.000", true},
+		{"1.234,50", true},
+		{"1,234.50", true},
+		{"doce", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.precio, func(t *testing.T) {
+			report, err := products.Import(context.Background(), [][]string{
+				{"nombre", "precio"},
+				{"Martillo", tt.precio},
+			}, true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := len(report.Errores) > 0; got != tt.wantErr {
+				t.Errorf("errores = %v, se esperaba error: %v", report.Errores, tt.wantErr)
+			}
+		})
+	}
+}
@@ -1,50 +1,189 @@
 package service
 
-import "ferreteria-inventario-ventas/internal/domain"
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"ferreteria-inventario-ventas/internal/domain"
+)
 
 // Interfaz que debe cumplir el repositorio de productos.
 type ProductRepository interface {
-	Create(*domain.Product) error
-	List() ([]domain.Product, error)
-	Update(id int64, p *domain.Product) error
-	Delete(id int64) error
+	Create(p *domain.Product, audit domain.AuditFunc) error
+	Get(id int64) (*domain.Product, error)
+	GetByBarcode(code string) (*domain.Product, error)
+	List(f domain.ProductFilter, p domain.PageParams) (domain.Page[domain.Product], error)
+	Each(f domain.ProductFilter, fn func(domain.Product) error) error
+	Search(text string, limit int) ([]domain.Product, error)
+	Update(id int64, p *domain.Product, audit domain.AuditFunc) error
+	Delete(id int64, audit domain.AuditFunc) error
+	AssignBarcodes(list []domain.BarcodeAssignment, audit domain.AuditFunc) ([]domain.RowError, error)
+	Import(rows []domain.ProductImportRow, dryRun bool, validate func(*domain.Product) error, audit domain.AuditFunc) (domain.ImportReport, error)
+	Valuation(asOf time.Time, categoryID int64) ([]domain.ValuationRow, error)
+	DeadStock(since time.Time, categoryID int64) ([]domain.DeadStockRow, error)
 }
 
 // ProductService contiene la lógica de negocio para productos.
 type ProductService struct {
-	repo ProductRepository
+	repo  ProductRepository
+	audit Auditor
 }
 
 // Constructor del servicio.
-func NewProductService(r ProductRepository) *ProductService {
-	return &ProductService{repo: r}
+func NewProductService(r ProductRepository, audit Auditor) *ProductService {
+	return &ProductService{repo: r, audit: audit}
 }
 
-// Create valida datos antes de guardar.
-func (s *ProductService) Create(p *domain.Product) error {
+// Create valida datos antes de guardar y registra el alta en la auditoría.
+func (s *ProductService) Create(ctx context.Context, p *domain.Product) error {
+
+	if err := validateProduct(p); err != nil {
+		return err
+	}
+
+	return s.repo.Create(p, s.audit.Entry(ctx, domain.AuditCreate, domain.EntityProduct))
+}
 
-	if p.Nombre == "" || p.Stock < 0 || p.Precio <= 0 {
+// validateProduct aplica las reglas de un producto y normaliza SKU y códigos de barras.
+func validateProduct(p *domain.Product) error {
+	if productProblem(p) != nil {
 		return domain.ErrInvalidInput
 	}
+	return nil
+}
 
-	return s.repo.Create(p)
+// productProblem es validateProduct con el motivo del rechazo (para reportes por fila).
+func productProblem(p *domain.Product) error {
+
+	p.Nombre = strings.TrimSpace(p.Nombre)
+	p.SKU = strings.TrimSpace(p.SKU)
+
+	switch {
+	case p.Nombre == "":
+		return errors.New("nombre requerido")
+	case p.Stock < 0:
+		return errors.New("stock no puede ser negativo")
+	case p.Precio <= 0:
+		return errors.New("precio debe ser mayor a 0")
+	case p.Costo < 0:
+		return errors.New("costo no puede ser negativo")
+	case len(p.SKU) > 64:
+		return errors.New("sku de más de 64 caracteres")
+	case p.CategoryID != nil && *p.CategoryID < 0, p.BrandID != nil && *p.BrandID < 0:
+		return errors.New("categoría o marca inválida")
+	}
+
+	seen := make(map[string]bool)
+	for i, code := range p.Barcodes {
+		normalized, ok := domain.NormalizeBarcode(code)
+		if !ok {
+			return errors.New("código de barras inválido: " + code)
+		}
+		if seen[normalized] {
+			return errors.New("código de barras repetido: " + code)
+		}
+		seen[normalized] = true
+		p.Barcodes[i] = normalized
+	}
+
+	return nil
+}
+
+// Get devuelve un producto por ID.
+func (s *ProductService) Get(id int64) (*domain.Product, error) {
+	if id <= 0 {
+		return nil, domain.ErrInvalidInput
+	}
+	return s.repo.Get(id)
 }
 
-// List devuelve todos los productos.
-func (s *ProductService) List() ([]domain.Product, error) {
-	return s.repo.List()
+// GetByBarcode busca el producto de un código escaneado (EAN-13, UPC-A o EAN-8).
+func (s *ProductService) GetByBarcode(code string) (*domain.Product, error) {
+	normalized, ok := domain.NormalizeBarcode(code)
+	if !ok {
+		return nil, domain.ErrInvalidInput
+	}
+	return s.repo.GetByBarcode(normalized)
 }
 
-func (s *ProductService) Update(id int64, p *domain.Product) error {
-	if id <= 0 || p.Nombre == "" || p.Stock < 0 || p.Precio <= 0 {
+// AssignBarcodes asigna códigos de barras en bloque (todo o nada).
+// Si hay errores de validación o de asignación los devuelve por fila.
+func (s *ProductService) AssignBarcodes(ctx context.Context, list []domain.BarcodeAssignment) ([]domain.RowError, error) {
+
+	if len(list) == 0 {
+		return nil, domain.ErrInvalidInput
+	}
+
+	var rowErrors []domain.RowError
+
+	for i := range list {
+		a := &list[i]
+		a.SKU = strings.TrimSpace(a.SKU)
+
+		normalized, ok := domain.NormalizeBarcode(a.Barcode)
+		switch {
+		case a.ProductID < 0 || (a.ProductID == 0 && a.SKU == ""):
+			rowErrors = append(rowErrors, domain.RowError{Row: i + 1, Error: "product_id o sku requerido"})
+		case !ok:
+			rowErrors = append(rowErrors, domain.RowError{Row: i + 1, Error: "código de barras inválido"})
+		default:
+			a.Barcode = normalized
+		}
+	}
+
+	if len(rowErrors) > 0 {
+		return rowErrors, domain.ErrInvalidInput
+	}
+
+	return s.repo.AssignBarcodes(list, s.audit.Entry(ctx, domain.AuditBarcodes, domain.EntityProduct))
+}
+
+// List devuelve una página de productos filtrados.
+func (s *ProductService) List(f domain.ProductFilter, p domain.PageParams) (domain.Page[domain.Product], error) {
+	if err := normalizePage(&p); err != nil {
+		return domain.Page[domain.Product]{}, err
+	}
+	return s.repo.List(f, p)
+}
+
+// Export recorre todos los productos filtrados sin paginar.
+func (s *ProductService) Export(f domain.ProductFilter, fn func(domain.Product) error) error {
+	return s.repo.Each(f, fn)
+}
+
+// Search busca productos por texto, ordenados por relevancia.
+// El límite por defecto es 20 y el máximo 100.
+func (s *ProductService) Search(text string, limit int) ([]domain.Product, error) {
+	text = strings.TrimSpace(text)
+	if text == "" || limit < 0 || limit > 100 {
+		return nil, domain.ErrInvalidInput
+	}
+	if limit == 0 {
+		limit = 20
+	}
+	return s.repo.Search(text, limit)
+}
+
+// Update guarda los cambios y registra en la auditoría los campos que cambiaron.
+func (s *ProductService) Update(ctx context.Context, id int64, p *domain.Product) error {
+	if id <= 0 {
 		return domain.ErrInvalidInput
 	}
-	return s.repo.Update(id, p)
+	if err := validateProduct(p); err != nil {
+		return err
+	}
+
+	p.ID = id
+	return s.repo.Update(id, p, s.audit.Entry(ctx, domain.AuditUpdate, domain.EntityProduct))
 }
 
-func (s *ProductService) Delete(id int64) error {
+// Delete elimina el producto y registra en la auditoría cómo era.
+func (s *ProductService) Delete(ctx context.Context, id int64) error {
 	if id <= 0 {
 		return domain.ErrInvalidInput
 	}
-	return s.repo.Delete(id)
+
+	return s.repo.Delete(id, s.audit.Entry(ctx, domain.AuditDelete, domain.EntityProduct))
 }
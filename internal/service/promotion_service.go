@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"ferreteria-inventario-ventas/internal/domain"
+)
+
+// Interfaz que debe cumplir el repositorio de promociones.
+type PromotionRepository interface {
+	Create(p *domain.Promotion, audit domain.AuditFunc) error
+	Get(id int64) (*domain.Promotion, error)
+	List(f domain.PromotionFilter, now time.Time) ([]domain.Promotion, error)
+	Update(id int64, p *domain.Promotion, audit domain.AuditFunc) error
+	Delete(id int64, audit domain.AuditFunc) error
+}
+
+// PromotionService contiene la lógica de negocio para promociones.
+type PromotionService struct {
+	repo  PromotionRepository
+	audit Auditor
+}
+
+// Constructor del servicio.
+func NewPromotionService(r PromotionRepository, audit Auditor) *PromotionService {
+	return &PromotionService{repo: r, audit: audit}
+}
+
+// Create valida la promoción antes de guardar y registra el alta en la auditoría.
+// Sin fecha desde rige a partir de ahora.
+func (s *PromotionService) Create(ctx context.Context, p *domain.Promotion) error {
+
+	if err := validatePromotion(p); err != nil {
+		return err
+	}
+
+	// El repositorio deja en p los nombres de los objetivos
+	return s.repo.Create(p, s.audit.Entry(ctx, domain.AuditCreate, domain.EntityPromotion))
+}
+
+// validatePromotion exige nombre, un tipo conocido con sus parámetros, un rango de
+// fechas válido y objetivos de tipo producto, categoría o marca. Los parámetros que
+// no usa el tipo se ponen en cero.
+func validatePromotion(p *domain.Promotion) error {
+
+	p.Nombre = strings.TrimSpace(p.Nombre)
+	if p.Nombre == "" || p.CantidadMin < 0 || p.MontoMin < 0 {
+		return domain.ErrInvalidInput
+	}
+
+	switch p.Tipo {
+	case domain.PromoPercent:
+		if p.Porcentaje <= 0 || p.Porcentaje > 100 {
+			return domain.ErrInvalidInput
+		}
+		p.Compra, p.Gratis, p.Monto, p.MontoMin = 0, 0, 0, 0
+	case domain.PromoBuyGet:
+		if p.Compra < 1 || p.Gratis < 1 {
+			return domain.ErrInvalidInput
+		}
+		p.Porcentaje, p.Monto, p.CantidadMin, p.MontoMin = 0, 0, 0, 0
+	case domain.PromoAmount:
+		if p.Monto <= 0 {
+			return domain.ErrInvalidInput
+		}
+		p.Porcentaje, p.Compra, p.Gratis, p.CantidadMin = 0, 0, 0, 0
+	default:
+		return domain.ErrInvalidInput
+	}
+
+	if p.Desde.IsZero() {
+		p.Desde = time.Now().Truncate(time.Second)
+	}
+	if p.Hasta != nil && !p.Hasta.After(p.Desde) {
+		return domain.ErrInvalidInput
+	}
+
+	if p.Objetivos == nil {
+		p.Objetivos = []domain.PromotionTarget{}
+	}
+	for _, t := range p.Objetivos {
+		switch t.Tipo {
+		case domain.TargetProduct, domain.TargetCategory, domain.TargetBrand:
+		default:
+			return domain.ErrInvalidInput
+		}
+		if t.ID <= 0 {
+			return domain.ErrInvalidInput
+		}
+	}
+
+	return nil
+}
+
+// Get devuelve una promoción con sus objetivos.
+func (s *PromotionService) Get(id int64) (*domain.Promotion, error) {
+	if id <= 0 {
+		return nil, domain.ErrInvalidInput
+	}
+	return s.repo.Get(id)
+}
+
+// List devuelve las promociones; con Vigentes solo las que se aplicarían a una venta ahora.
+func (s *PromotionService) List(f domain.PromotionFilter) ([]domain.Promotion, error) {
+	return s.repo.List(f, time.Now())
+}
+
+// Update reemplaza la promoción y sus objetivos y registra el cambio en la auditoría.
+// Las ventas ya hechas conservan sus descuentos.
+func (s *PromotionService) Update(ctx context.Context, id int64, p *domain.Promotion) error {
+
+	if id <= 0 {
+		return domain.ErrInvalidInput
+	}
+	if err := validatePromotion(p); err != nil {
+		return err
+	}
+
+	return s.repo.Update(id, p, s.audit.Entry(ctx, domain.AuditUpdate, domain.EntityPromotion))
+}
+
+// Delete elimina una promoción que no se usó en ventas y registra en la auditoría cómo era.
+// ErrConflict si ya hizo descuentos (en ese caso se desactiva).
+func (s *PromotionService) Delete(ctx context.Context, id int64) error {
+	if id <= 0 {
+		return domain.ErrInvalidInput
+	}
+
+	return s.repo.Delete(id, s.audit.Entry(ctx, domain.AuditDelete, domain.EntityPromotion))
+}
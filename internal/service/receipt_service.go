@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"ferreteria-inventario-ventas/internal/domain"
+)
+
+// Interfaz que debe cumplir el repositorio de recepciones.
+type ReceiptRepository interface {
+	Create(rc *domain.Receipt, audit domain.AuditFunc) error
+	Get(id int64) (*domain.Receipt, error)
+	List(f domain.ReceiptFilter, p domain.PageParams) (domain.Page[domain.Receipt], error)
+}
+
+// ReceiptService contiene la lógica de negocio para recepciones de mercadería.
+type ReceiptService struct {
+	repo  ReceiptRepository
+	audit Auditor
+}
+
+// Constructor del servicio.
+func NewReceiptService(r ReceiptRepository, audit Auditor) *ReceiptService {
+	return &ReceiptService{repo: r, audit: audit}
+}
+
+// Create valida la recepción: proveedor requerido, al menos un producto,
+// cantidades positivas, costos no negativos y sin productos repetidos.
+// El cambio de stock y costo de cada producto queda en la auditoría.
+func (s *ReceiptService) Create(ctx context.Context, rc *domain.Receipt) error {
+
+	rc.Proveedor = strings.TrimSpace(rc.Proveedor)
+	rc.Nota = strings.TrimSpace(rc.Nota)
+
+	if rc.Proveedor == "" || len(rc.Items) == 0 {
+		return domain.ErrInvalidInput
+	}
+
+	seen := make(map[int64]bool)
+	for _, it := range rc.Items {
+		if it.ProductID <= 0 || it.Cantidad <= 0 || it.CostoUnitario < 0 || seen[it.ProductID] {
+			return domain.ErrInvalidInput
+		}
+		seen[it.ProductID] = true
+	}
+
+	return s.repo.Create(rc, s.audit.Entry(ctx, domain.AuditUpdate, domain.EntityProduct))
+}
+
+// Get devuelve una recepción con sus productos.
+func (s *ReceiptService) Get(id int64) (*domain.Receipt, error) {
+	if id <= 0 {
+		return nil, domain.ErrInvalidInput
+	}
+	return s.repo.Get(id)
+}
+
+// List devuelve una página de recepciones filtradas.
+func (s *ReceiptService) List(f domain.ReceiptFilter, p domain.PageParams) (domain.Page[domain.Receipt], error) {
+	if err := normalizePage(&p); err != nil {
+		return domain.Page[domain.Receipt]{}, err
+	}
+	if !f.Desde.IsZero() && !f.Hasta.IsZero() && !f.Desde.Before(f.Hasta) {
+		return domain.Page[domain.Receipt]{}, domain.ErrInvalidInput
+	}
+	return s.repo.List(f, p)
+}
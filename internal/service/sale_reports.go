@@ -0,0 +1,407 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"ferreteria-inventario-ventas/internal/domain"
+)
+
+// MaxSummaryBuckets limita la cantidad de períodos de un resumen
+// (unos 3 años agrupando por día).
+const MaxSummaryBuckets = 1100
+
+// VentasHoy devuelve los totales de las ventas del día actual.
+// Los límites de los días se calculan en time.Local, que main configura
+// con la zona horaria de la tienda.
+func (s *SaleService) VentasHoy() (domain.SalesTotals, error) {
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	totals, err := s.repo.SalesTotals([]domain.TimeRange{{Desde: today, Hasta: today.AddDate(0, 0, 1)}})
+	if err != nil {
+		return domain.SalesTotals{}, err
+	}
+
+	return finishTotals(totals[0]), nil
+}
+
+// SalesSummary resume las ventas de [desde, hasta) por día, semana o mes.
+// Los períodos se alinean al calendario de la tienda (lunes para semanas,
+// día 1 para meses) y el primero y el último se recortan al rango pedido.
+func (s *SaleService) SalesSummary(desde, hasta time.Time, groupBy string) (domain.SalesSummary, error) {
+
+	summary := domain.SalesSummary{GroupBy: groupBy, Desde: desde, Hasta: hasta}
+
+	if desde.IsZero() || hasta.IsZero() || !desde.Before(hasta) {
+		return summary, domain.ErrInvalidInput
+	}
+
+	buckets, err := summaryBuckets(desde, hasta, groupBy)
+	if err != nil {
+		return summary, err
+	}
+
+	ranges := make([]domain.TimeRange, len(buckets))
+	for i, b := range buckets {
+		ranges[i] = domain.TimeRange{Desde: b.Desde, Hasta: b.Hasta}
+	}
+
+	totals, err := s.repo.SalesTotals(ranges)
+	if err != nil {
+		return summary, err
+	}
+
+	var all domain.SalesTotals
+	for i := range buckets {
+		buckets[i].SalesTotals = finishTotals(totals[i])
+
+		all.Ventas += totals[i].Ventas
+		all.Bruto += totals[i].Bruto
+		all.Descuentos += totals[i].Descuentos
+		all.Impuestos += totals[i].Impuestos
+		all.Total += totals[i].Total
+		all.Costo += totals[i].Costo
+	}
+
+	summary.Periodos = buckets
+	summary.Totales = finishTotals(all)
+
+	return summary, nil
+}
+
+// Límites del ranking de productos.
+const (
+	DefaultTopLimit = 5
+	MaxTopLimit     = 100
+)
+
+// TopProductos devuelve el ranking de productos. Por defecto las 5 filas con más
+// unidades vendidas en todo el historial; con Asc, los menos vendidos.
+func (s *SaleService) TopProductos(q domain.TopProductsQuery) ([]domain.TopProduct, error) {
+
+	if q.Limit == 0 {
+		q.Limit = DefaultTopLimit
+	}
+	if q.Metric == "" {
+		q.Metric = domain.MetricUnits
+	}
+
+	if q.Limit < 0 || q.Limit > MaxTopLimit || q.CategoryID < 0 ||
+		!validSaleRange(domain.SaleFilter{Desde: q.Desde, Hasta: q.Hasta}) {
+		return nil, domain.ErrInvalidInput
+	}
+
+	switch q.Metric {
+	case domain.MetricUnits, domain.MetricRevenue, domain.MetricMargin:
+	default:
+		return nil, domain.ErrInvalidInput
+	}
+
+	list, err := s.repo.TopProductos(q)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range list {
+		list[i].Total = roundCents(list[i].Total)
+		list[i].Costo = roundCents(list[i].Costo)
+		list[i].Utilidad = roundCents(list[i].Utilidad)
+	}
+
+	return list, nil
+}
+
+// summaryBuckets arma los períodos vacíos que cubren [desde, hasta).
+func summaryBuckets(desde, hasta time.Time, groupBy string) ([]domain.SalesSummaryBucket, error) {
+
+	loc := time.Local
+	local := desde.In(loc)
+	start := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+
+	var next func(time.Time) time.Time
+	var label func(time.Time) string
+
+	switch groupBy {
+	case domain.PeriodDay:
+		next = func(t time.Time) time.Time { return t.AddDate(0, 0, 1) }
+		label = func(t time.Time) string { return t.Format(time.DateOnly) }
+	case domain.PeriodWeek:
+		// Retrocede al lunes
+		start = start.AddDate(0, 0, -((int(start.Weekday()) + 6) % 7))
+		next = func(t time.Time) time.Time { return t.AddDate(0, 0, 7) }
+		label = func(t time.Time) string {
+			year, week := t.ISOWeek()
+			return fmt.Sprintf("%d-W%02d", year, week)
+		}
+	case domain.PeriodMonth:
+		start = time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, loc)
+		next = func(t time.Time) time.Time { return t.AddDate(0, 1, 0) }
+		label = func(t time.Time) string { return t.Format("2006-01") }
+	default:
+		return nil, domain.ErrInvalidInput
+	}
+
+	var buckets []domain.SalesSummaryBucket
+
+	for t := start; t.Before(hasta); t = next(t) {
+		if len(buckets) == MaxSummaryBuckets {
+			return nil, domain.ErrInvalidInput
+		}
+
+		b := domain.SalesSummaryBucket{Periodo: label(t), Desde: t, Hasta: next(t)}
+		if b.Desde.Before(desde) {
+			b.Desde = desde
+		}
+		if b.Hasta.After(hasta) {
+			b.Hasta = hasta
+		}
+		buckets = append(buckets, b)
+	}
+
+	return buckets, nil
+}
+
+// finishTotals calcula neto, ticket promedio, utilidad y margen y redondea a centavos.
+func finishTotals(t domain.SalesTotals) domain.SalesTotals {
+
+	t.Bruto = roundCents(t.Bruto)
+	t.Descuentos = roundCents(t.Descuentos)
+	t.Impuestos = roundCents(t.Impuestos)
+	t.Total = roundCents(t.Total)
+	t.Neto = roundCents(t.Bruto - t.Descuentos)
+	t.Costo = roundCents(t.Costo)
+	t.Utilidad = roundCents(t.Neto - t.Costo)
+	t.MargenPct = marginPct(t.Utilidad, t.Neto)
+
+	if t.Ventas > 0 {
+		t.TicketPromedio = roundCents(t.Total / float64(t.Ventas))
+	}
+
+	return t
+}
+
+// Límites del reporte de márgenes.
+const (
+	DefaultMarginLimit = 100
+	MaxMarginLimit     = 1000
+)
+
+// Margins devuelve la utilidad bruta y el margen por venta, producto o categoría
+// en [desde, hasta) (fechas en cero = sin límite). Por período se usa SalesSummary.
+func (s *SaleService) Margins(groupBy string, desde, hasta time.Time, limit int) ([]domain.MarginRow, error) {
+
+	if limit == 0 {
+		limit = DefaultMarginLimit
+	}
+	if limit < 0 || limit > MaxMarginLimit || !validSaleRange(domain.SaleFilter{Desde: desde, Hasta: hasta}) {
+		return nil, domain.ErrInvalidInput
+	}
+
+	switch groupBy {
+	case domain.GroupBySale, domain.GroupByProduct, domain.GroupByCategory:
+	default:
+		return nil, domain.ErrInvalidInput
+	}
+
+	rows, err := s.repo.Margins(groupBy, desde, hasta, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range rows {
+		m := &rows[i]
+		m.Ingresos = roundCents(m.Ingresos)
+		m.Costo = roundCents(m.Costo)
+		m.Utilidad = roundCents(m.Ingresos - m.Costo)
+		m.MargenPct = marginPct(m.Utilidad, m.Ingresos)
+	}
+
+	return rows, nil
+}
+
+// marginPct devuelve utilidad / ingresos en porcentaje con dos decimales (0 sin ingresos).
+func marginPct(utilidad, ingresos float64) float64 {
+	if ingresos == 0 {
+		return 0
+	}
+	return roundCents(utilidad / ingresos * 100)
+}
+
+// Límites por defecto del análisis ABC (% acumulado del valor).
+const (
+	DefaultABCLimitA = 80
+	DefaultABCLimitB = 95
+)
+
+// ABC clasifica todos los productos por su aporte a la métrica en [desde, hasta):
+// se ordenan de mayor a menor y son A mientras el acumulado previo no llega a LimiteA,
+// B hasta LimiteB y C el resto. Los productos sin ventas (o con valor negativo) son C.
+// Sin fechas se usan los 12 meses que terminan hoy.
+func (s *SaleService) ABC(q domain.ABCQuery) (domain.ABCReport, error) {
+
+	if q.Hasta.IsZero() {
+		// Hasta el final de hoy, igual que to=YYYY-MM-DD
+		now := time.Now()
+		q.Hasta = time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, now.Location())
+	}
+	if q.Desde.IsZero() {
+		q.Desde = q.Hasta.AddDate(-1, 0, 0)
+	}
+	if q.Metric == "" {
+		q.Metric = domain.MetricRevenue
+	}
+	if q.LimiteA == 0 {
+		q.LimiteA = DefaultABCLimitA
+	}
+	if q.LimiteB == 0 {
+		q.LimiteB = DefaultABCLimitB
+	}
+
+	report := domain.ABCReport{Desde: q.Desde, Hasta: q.Hasta, Metric: q.Metric}
+
+	if !q.Desde.Before(q.Hasta) || q.CategoryID < 0 ||
+		q.LimiteA <= 0 || q.LimiteA > q.LimiteB || q.LimiteB > 100 {
+		return report, domain.ErrInvalidInput
+	}
+
+	value := func(t domain.TopProduct) float64 { return t.Total }
+	switch q.Metric {
+	case domain.MetricRevenue:
+	case domain.MetricUnits:
+		value = func(t domain.TopProduct) float64 { return float64(t.Cantidad) }
+	case domain.MetricMargin:
+		value = func(t domain.TopProduct) float64 { return t.Utilidad }
+	default:
+		return report, domain.ErrInvalidInput
+	}
+
+	// Vienen ordenados de mayor a menor por la métrica
+	list, err := s.repo.TopProductos(domain.TopProductsQuery{
+		Desde:      q.Desde,
+		Hasta:      q.Hasta,
+		Metric:     q.Metric,
+		CategoryID: q.CategoryID,
+		All:        true,
+	})
+	if err != nil {
+		return report, err
+	}
+
+	for _, t := range list {
+		if v := value(t); v > 0 {
+			report.Total += v
+		}
+	}
+
+	classes := map[string]*domain.ABCClass{
+		domain.ClassA: {Clase: domain.ClassA},
+		domain.ClassB: {Clase: domain.ClassB},
+		domain.ClassC: {Clase: domain.ClassC},
+	}
+
+	report.Productos = make([]domain.ABCItem, 0, len(list))
+	var acumulado float64
+
+	for _, t := range list {
+		t.Total = roundCents(t.Total)
+		t.Costo = roundCents(t.Costo)
+		t.Utilidad = roundCents(t.Utilidad)
+
+		item := domain.ABCItem{TopProduct: t, Valor: value(t), Clase: domain.ClassC}
+
+		if item.Valor > 0 && report.Total > 0 {
+			pct := item.Valor / report.Total * 100
+			switch {
+			case acumulado < q.LimiteA:
+				item.Clase = domain.ClassA
+			case acumulado < q.LimiteB:
+				item.Clase = domain.ClassB
+			}
+			acumulado += pct
+			item.Participacion = roundCents(pct)
+		}
+		item.Acumulado = roundCents(acumulado)
+		item.Valor = roundCents(item.Valor)
+
+		c := classes[item.Clase]
+		c.Productos++
+		if item.Valor > 0 {
+			c.Valor += item.Valor
+		}
+
+		report.Productos = append(report.Productos, item)
+	}
+
+	for _, name := range []string{domain.ClassA, domain.ClassB, domain.ClassC} {
+		c := classes[name]
+		c.Valor = roundCents(c.Valor)
+		if len(list) > 0 {
+			c.PctProductos = roundCents(float64(c.Productos) / float64(len(list)) * 100)
+		}
+		if report.Total > 0 {
+			c.Participacion = roundCents(c.Valor / report.Total * 100)
+		}
+		report.Clases = append(report.Clases, *c)
+	}
+	report.Total = roundCents(report.Total)
+
+	return report, nil
+}
+
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// DefaultDiscountReportDays es el rango por defecto del reporte de descuentos (incluye hoy).
+const DefaultDiscountReportDays = 30
+
+// DiscountReport resume los descuentos manuales por cajero en [desde, hasta), con el
+// porcentaje que representan sobre lo vendido y los totales. Por defecto los últimos 30 días con hoy.
+func (s *SaleService) DiscountReport(desde, hasta time.Time) (*domain.DiscountReport, error) {
+
+	if hasta.IsZero() {
+		now := time.Now()
+		hasta = time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, now.Location())
+	}
+	if desde.IsZero() {
+		desde = hasta.AddDate(0, 0, -DefaultDiscountReportDays)
+	}
+	if !desde.Before(hasta) {
+		return nil, domain.ErrInvalidInput
+	}
+
+	rows, err := s.repo.DiscountsByCashier(desde, hasta)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &domain.DiscountReport{Desde: desde, Hasta: hasta, Cajeros: rows}
+	t := &report.Totales
+	for i := range rows {
+		row := &rows[i]
+		t.Ventas += row.Ventas
+		t.Bruto += row.Bruto
+		t.VentasDescuento += row.VentasDescuento
+		t.Descuentos += row.Descuentos
+		t.Monto += row.Monto
+		t.Autorizadas += row.Autorizadas
+		t.MontoAutorizado += row.MontoAutorizado
+		roundDiscountRow(row)
+	}
+	roundDiscountRow(t)
+
+	return report, nil
+}
+
+// roundDiscountRow redondea los montos y calcula el porcentaje descontado.
+func roundDiscountRow(row *domain.DiscountReportRow) {
+	row.Bruto = roundCents(row.Bruto)
+	row.Monto = roundCents(row.Monto)
+	row.MontoAutorizado = roundCents(row.MontoAutorizado)
+	if row.Bruto > 0 {
+		row.Porcentaje = roundCents(row.Monto / row.Bruto * 100)
+	}
+}
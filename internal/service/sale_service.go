@@ -1,66 +1,274 @@
 package service
 
-import "ferreteria-inventario-ventas/internal/domain"
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"ferreteria-inventario-ventas/internal/domain"
+)
 
 // Interfaz que debe cumplir el repositorio de ventas.
 type SaleRepository interface {
-	CreateSaleTx(clientID int64, items []domain.SaleItem) (*domain.Sale, error)
-	ListSales() ([]domain.Sale, error)
+	CreateSaleTx(in domain.NewSale, idem *domain.IdempotencyKey, audit domain.AuditFunc) (*domain.Sale, error)
+	ListSales(f domain.SaleFilter, p domain.PageParams) (domain.Page[domain.Sale], error)
+	EachSale(f domain.SaleFilter, fn func(domain.Sale) error) error
+	EachSaleLine(f domain.SaleFilter, fn func(domain.SaleLine) error) error
 	GetSaleDetail(saleID int64) (*domain.Sale, error)
-
-	ClientExists(id int64) (bool, error)
-	ProductExists(id int64) (bool, error)
+	FindIdempotencyKey(key string) (*domain.IdempotencyKey, error)
 
 	// NUEVOS MÉTODOS DE REPORTE
-	VentasHoy() (int, float64, error)
-	TopProductos() ([]map[string]interface{}, error)
+	SalesTotals(ranges []domain.TimeRange) ([]domain.SalesTotals, error)
+	TopProductos(q domain.TopProductsQuery) ([]domain.TopProduct, error)
+	VentasPorGrupo(groupBy string) ([]domain.GroupSales, error)
+	Margins(groupBy string, desde, hasta time.Time, limit int) ([]domain.MarginRow, error)
+	DemandHistory(days []domain.TimeRange, categoryID int64) ([]domain.DemandSeries, error)
+	ClientPurchases(clientID int64, desde, hasta time.Time) (*domain.ClientHistory, error)
+	ClientActivity(desde, hasta time.Time) ([]domain.RFMRow, error)
+	RebuildDailySales() (ventas, filas int, err error)
+	DailySalesZone() (string, error)
+	DiscountsByCashier(desde, hasta time.Time) ([]domain.DiscountReportRow, error)
+}
+
+// DiscountAuthorizer arma los límites de descuento manual de una venta con el usuario
+// de la sesión del contexto y las credenciales del supervisor (ver UserService.DiscountAuth).
+type DiscountAuthorizer interface {
+	DiscountAuth(ctx context.Context, cred *domain.DiscountCredentials) (domain.DiscountAuth, error)
 }
 
 // SaleService contiene la lógica de negocio para ventas.
 type SaleService struct {
-	repo SaleRepository
+	repo         SaleRepository
+	audit        Auditor
+	users        DiscountAuthorizer
+	tasaImpuesto float64
 }
 
-// Constructor del servicio.
-func NewSaleService(r SaleRepository) *SaleService {
-	return &SaleService{repo: r}
+// Constructor del servicio. tasaImpuesto es el porcentaje que se cobra sobre
+// el neto de cada venta (0 si los precios ya incluyen el impuesto).
+func NewSaleService(r SaleRepository, audit Auditor, users DiscountAuthorizer, tasaImpuesto float64) *SaleService {
+	return &SaleService{repo: r, audit: audit, users: users, tasaImpuesto: tasaImpuesto}
 }
 
 // Create valida los datos antes de registrar la venta.
-func (s *SaleService) Create(clientID int64, items []domain.SaleItem) (*domain.Sale, error) {
+// La existencia del cliente, de los productos, el precio y el stock
+// se verifican en el repositorio dentro de la misma transacción.
+//
+// Si key no está vacía se usa como llave de idempotencia: un reintento con la
+// misma llave y el mismo contenido devuelve la venta original (segundo valor en true)
+// sin volver a descontar stock; con otro contenido devuelve ErrIdempotencyMismatch.
+// Las ventas nuevas (no las repetidas) quedan en la auditoría.
+//
+// Los descuentos manuales (de línea y de la venta) llevan porcentaje o monto y motivo y
+// requieren una sesión (ErrUnauthenticated): quedan a nombre del cajero. El límite con
+// que se autorizan se arma con in.Autorizacion después de buscar el reintento (un token
+// de aprobación ya usado por la venta original no impide devolverla) y se verifica en el
+// repositorio cuando ya se conocen los precios (ErrDiscountLimit si no alcanza).
+//
+// El impuesto se calcula con la tasa del servicio sobre el neto (después de todos los
+// descuentos) y se suma al total cobrado.
+func (s *SaleService) Create(ctx context.Context, in domain.NewSale, key string) (*domain.Sale, bool, error) {
 
-	if clientID <= 0 || len(items) == 0 {
-		return nil, domain.ErrInvalidInput
+	if in.ClientID <= 0 || len(in.Items) == 0 || len(key) > 255 {
+		return nil, false, domain.ErrInvalidInput
+	}
+	if in.Descuento != nil && !validManualDiscount(in.Descuento) {
+		return nil, false, domain.ErrInvalidInput
+	}
+
+	merged, err := mergeItems(in.Items)
+	if err != nil {
+		return nil, false, err
+	}
+	in.Items = merged
+	in.TasaImpuesto = s.tasaImpuesto
+
+	if hasManualDiscount(in) && domain.UserFrom(ctx) == nil {
+		return nil, false, domain.ErrUnauthenticated
+	}
+
+	audit := s.audit.Entry(ctx, domain.AuditCreate, domain.EntitySale)
+
+	if key == "" {
+		if in.Auth, err = s.users.DiscountAuth(ctx, in.Autorizacion); err != nil {
+			return nil, false, err
+		}
+		sale, err := s.repo.CreateSaleTx(in, nil, audit)
+		return sale, false, err
+	}
+
+	idem := &domain.IdempotencyKey{
+		Key:         key,
+		Fingerprint: fingerprint(in),
+	}
+
+	// Reintento: la llave ya está registrada
+	prev, err := s.replay(idem)
+	if err != domain.ErrNotFound {
+		return prev, err == nil, err
+	}
+
+	if in.Auth, err = s.users.DiscountAuth(ctx, in.Autorizacion); err != nil {
+		if err == domain.ErrBadCredentials {
+			// Otra petición con la misma llave pudo usar el token entre la búsqueda y ahora
+			if prev, replayErr := s.replay(idem); replayErr != domain.ErrNotFound {
+				return prev, replayErr == nil, replayErr
+			}
+		}
+		return nil, false, err
+	}
+
+	sale, err := s.repo.CreateSaleTx(in, idem, audit)
+	if err == domain.ErrConflict {
+		// Otra petición con la misma llave se registró entre la búsqueda y la transacción
+		sale, err = s.replay(idem)
+		return sale, err == nil, err
 	}
 
-	exists, err := s.repo.ClientExists(clientID)
+	return sale, false, err
+}
+
+// replay devuelve la venta asociada a la llave si la huella coincide.
+// Devuelve ErrNotFound si la llave no existe.
+func (s *SaleService) replay(idem *domain.IdempotencyKey) (*domain.Sale, error) {
+
+	stored, err := s.repo.FindIdempotencyKey(idem.Key)
 	if err != nil {
 		return nil, err
 	}
-	if !exists {
-		return nil, domain.ErrNotFound
+
+	if stored.Fingerprint != idem.Fingerprint {
+		return nil, domain.ErrIdempotencyMismatch
+	}
+
+	return s.repo.GetSaleDetail(stored.SaleID)
+}
+
+// fingerprint calcula la huella de la petición normalizada
+// (items unidos y ordenados por producto, con sus descuentos manuales) para comparar reintentos.
+// Sin descuentos manuales la huella es la misma que antes de que existieran.
+func fingerprint(in domain.NewSale) string {
+
+	type line struct {
+		ProductID int64                  `json:"product_id"`
+		Cantidad  int                    `json:"cantidad"`
+		Descuento *domain.ManualDiscount `json:"descuento,omitempty"`
+	}
+
+	lines := make([]line, 0, len(in.Items))
+	for _, it := range in.Items {
+		lines = append(lines, line{ProductID: it.ProductID, Cantidad: it.Cantidad, Descuento: it.DescuentoManual})
 	}
+	sort.Slice(lines, func(i, j int) bool { return lines[i].ProductID < lines[j].ProductID })
+
+	body, _ := json.Marshal(struct {
+		ClientID  int64                  `json:"client_id"`
+		Items     []line                 `json:"items"`
+		Descuento *domain.ManualDiscount `json:"descuento,omitempty"`
+	}{in.ClientID, lines, in.Descuento})
+
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+// mergeItems valida los items y junta en una sola línea los que
+// repiten el mismo producto, sumando sus cantidades. Un producto repetido
+// puede traer descuento manual en una sola de sus líneas.
+// El precio unitario lo define el repositorio con el precio vigente.
+func mergeItems(items []domain.SaleItem) ([]domain.SaleItem, error) {
+
+	var merged []domain.SaleItem
+	index := make(map[int64]int)
 
 	for _, item := range items {
 
-		if item.ProductID <= 0 || item.Cantidad <= 0 || item.PrecioUnitario <= 0 {
+		if item.ProductID <= 0 || item.Cantidad <= 0 || item.PrecioUnitario < 0 {
+			return nil, domain.ErrInvalidInput
+		}
+		if item.DescuentoManual != nil && !validManualDiscount(item.DescuentoManual) {
 			return nil, domain.ErrInvalidInput
 		}
 
-		ok, err := s.repo.ProductExists(item.ProductID)
-		if err != nil {
-			return nil, err
+		if i, ok := index[item.ProductID]; ok {
+			if item.DescuentoManual != nil {
+				if merged[i].DescuentoManual != nil {
+					return nil, domain.ErrInvalidInput
+				}
+				merged[i].DescuentoManual = item.DescuentoManual
+			}
+			merged[i].Cantidad += item.Cantidad
+			continue
 		}
-		if !ok {
-			return nil, domain.ErrNotFound
+
+		index[item.ProductID] = len(merged)
+		merged = append(merged, domain.SaleItem{
+			ProductID:       item.ProductID,
+			Cantidad:        item.Cantidad,
+			DescuentoManual: item.DescuentoManual,
+		})
+	}
+
+	return merged, nil
+}
+
+// hasManualDiscount indica si la venta trae algún descuento manual (de línea o de la venta).
+func hasManualDiscount(in domain.NewSale) bool {
+	if in.Descuento != nil {
+		return true
+	}
+	for _, it := range in.Items {
+		if it.DescuentoManual != nil {
+			return true
 		}
 	}
+	return false
+}
+
+// validManualDiscount exige porcentaje (hasta 100) o monto, no los dos, y un motivo.
+// Deja el motivo sin espacios sobrantes y limpia el monto aplicado que pudo venir en la petición.
+func validManualDiscount(d *domain.ManualDiscount) bool {
+	d.Motivo = strings.TrimSpace(d.Motivo)
+	d.Aplicado = 0
+	if d.Motivo == "" || len(d.Motivo) > 200 || d.Porcentaje < 0 || d.Monto < 0 {
+		return false
+	}
+	return (d.Porcentaje > 0) != (d.Monto > 0) && d.Porcentaje <= 100
+}
+
+// List devuelve una página de ventas filtradas.
+func (s *SaleService) List(f domain.SaleFilter, p domain.PageParams) (domain.Page[domain.Sale], error) {
+	if err := normalizePage(&p); err != nil {
+		return domain.Page[domain.Sale]{}, err
+	}
+	if !validSaleRange(f) {
+		return domain.Page[domain.Sale]{}, domain.ErrInvalidInput
+	}
+	return s.repo.ListSales(f, p)
+}
 
-	return s.repo.CreateSaleTx(clientID, items)
+// Export recorre todas las cabeceras de venta filtradas sin paginar.
+func (s *SaleService) Export(f domain.SaleFilter, fn func(domain.Sale) error) error {
+	if !validSaleRange(f) {
+		return domain.ErrInvalidInput
+	}
+	return s.repo.EachSale(f, fn)
+}
+
+// ExportLines recorre las líneas de las ventas filtradas (una fila por producto vendido).
+func (s *SaleService) ExportLines(f domain.SaleFilter, fn func(domain.SaleLine) error) error {
+	if !validSaleRange(f) {
+		return domain.ErrInvalidInput
+	}
+	return s.repo.EachSaleLine(f, fn)
 }
 
-func (s *SaleService) List() ([]domain.Sale, error) {
-	return s.repo.ListSales()
+// validSaleRange verifica que desde sea anterior a hasta cuando vienen ambas.
+func validSaleRange(f domain.SaleFilter) bool {
+	return f.Desde.IsZero() || f.Hasta.IsZero() || f.Desde.Before(f.Hasta)
 }
 
 func (s *SaleService) Detail(id int64) (*domain.Sale, error) {
@@ -72,10 +280,10 @@ func (s *SaleService) Detail(id int64) (*domain.Sale, error) {
 
 // NUEVOS MÉTODOS DE REPORTE
 
-func (s *SaleService) VentasHoy() (int, float64, error) {
-	return s.repo.VentasHoy()
-}
-
-func (s *SaleService) TopProductos() ([]map[string]interface{}, error) {
-	return s.repo.TopProductos()
+// VentasPorGrupo agrega las ventas por categoría (incluye subcategorías) o por marca.
+func (s *SaleService) VentasPorGrupo(groupBy string) ([]domain.GroupSales, error) {
+	if groupBy != domain.GroupByCategory && groupBy != domain.GroupByBrand {
+		return nil, domain.ErrInvalidInput
+	}
+	return s.repo.VentasPorGrupo(groupBy)
 }
@@ -0,0 +1,114 @@
+package service_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"ferreteria-inventario-ventas/internal/domain"
+	"ferreteria-inventario-ventas/internal/service"
+	"ferreteria-inventario-ventas/internal/storage/sqlite"
+)
+
+// Una venta autorizada con un token de aprobación que se reintenta con la misma
+// Idempotency-Key devuelve la venta original aunque el token ya se haya usado.
+func TestCreateSaleReplaysTokenApprovedSale(t *testing.T) {
+
+	db, err := sqlite.OpenDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := sqlite.Migrate(db, filepath.Join("..", "..", "migrations", "schema.sql")); err != nil {
+		t.Fatal(err)
+	}
+
+	audit := service.NewAuditService(sqlite.NewAuditRepo(db))
+	users := service.NewUserService(sqlite.NewUserRepo(db), audit)
+	sales := service.NewSaleService(sqlite.NewSaleRepo(db), audit, users, 0)
+
+	// El primer usuario se crea sin sesión; el cajero, con la del supervisor
+	supervisor := domain.User{Usuario: "sofia", Rol: domain.RoleSupervisor, Password: "clave-segura", Activo: true}
+	if err := users.Create(context.Background(), &supervisor); err != nil {
+		t.Fatal(err)
+	}
+	cajero := domain.User{Usuario: "caja1", Rol: domain.RoleCashier, Password: "clave-segura", Activo: true}
+	if err := users.Create(domain.WithUser(context.Background(), &supervisor), &cajero); err != nil {
+		t.Fatal(err)
+	}
+
+	approval, err := users.Approve(domain.DiscountCredentials{Usuario: "sofia", Password: "clave-segura"}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := domain.Client{Nombre: "Cliente", Cedula: "0102030405", Email: "cliente@test.com"}
+	if err := sqlite.NewClientRepo(db).Create(&c, nil); err != nil {
+		t.Fatal(err)
+	}
+	p := domain.Product{Nombre: "Taladro", Stock: 5, Precio: 100, Costo: 60}
+	if err := sqlite.NewProductRepo(db).Create(&p, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// 15% supera el 5% del cajero y necesita el token del supervisor (20%)
+	newSale := func() domain.NewSale {
+		return domain.NewSale{
+			ClientID: c.ID,
+			Items: []domain.SaleItem{{
+				ProductID:       p.ID,
+				Cantidad:        1,
+				DescuentoManual: &domain.ManualDiscount{Porcentaje: 15, Motivo: "cliente frecuente"},
+			}},
+			Autorizacion: &domain.DiscountCredentials{Token: approval.Token},
+		}
+	}
+	ctx := domain.WithUser(context.Background(), &cajero)
+
+	first, replayed, err := sales.Create(ctx, newSale(), "venta-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replayed {
+		t.Error("la primera venta no es un reintento")
+	}
+
+	second, replayed, err := sales.Create(ctx, newSale(), "venta-1")
+	if err != nil {
+		t.Fatalf("reintento: %v", err)
+	}
+	if !replayed || second.ID != first.ID {
+		t.Errorf("reintento = venta %d (repetida %v), se esperaba la venta %d", second.ID, replayed, first.ID)
+	}
+	if second.AutorizadoPor != "sofia" || second.Cajero != "caja1" {
+		t.Errorf("cajero %q, autorizado por %q", second.Cajero, second.AutorizadoPor)
+	}
+
+	var stock int
+	if err := db.QueryRow(`SELECT stock FROM products WHERE id = ?`, p.ID).Scan(&stock); err != nil {
+		t.Fatal(err)
+	}
+	if stock != 4 {
+		t.Errorf("stock = %d, se esperaba 4 (una sola venta)", stock)
+	}
+
+	// Con otra llave el token ya no sirve
+	if _, _, err := sales.Create(ctx, newSale(), "venta-2"); err != domain.ErrBadCredentials {
+		t.Errorf("token reutilizado con otra llave: %v, se esperaba ErrBadCredentials", err)
+	}
+}
+
+// Los descuentos manuales sin sesión se rechazan: quedan a nombre del cajero.
+func TestCreateSaleManualDiscountRequiresSession(t *testing.T) {
+
+	sales := service.NewSaleService(nil, nil, nil, 0)
+
+	_, _, err := sales.Create(context.Background(), domain.NewSale{
+		ClientID:  1,
+		Items:     []domain.SaleItem{{ProductID: 1, Cantidad: 1}},
+		Descuento: &domain.ManualDiscount{Monto: 1, Motivo: "redondeo"},
+	}, "")
+	if err != domain.ErrUnauthenticated {
+		t.Errorf("err = %v, se esperaba ErrUnauthenticated", err)
+	}
+}
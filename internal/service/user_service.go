@@ -0,0 +1,433 @@
+package service
+
+import (
+	"context"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"ferreteria-inventario-ventas/internal/domain"
+)
+
+// Interfaz que debe cumplir el repositorio de usuarios.
+type UserRepository interface {
+	Create(u *domain.User, passwordHash string, audit domain.AuditFunc) error
+	Get(id int64) (*domain.User, error)
+	GetByUsername(usuario string) (*domain.User, string, error)
+	List() ([]domain.User, error)
+	Update(id int64, u *domain.User, passwordHash string, audit domain.AuditFunc) error
+	Roles() ([]domain.Role, error)
+	Role(nombre string) (*domain.Role, error)
+	SaveRole(role domain.Role, audit domain.AuditFunc) error
+	CreateApproval(a *domain.DiscountApproval, tokenHash string) error
+	FindApproval(tokenHash string, now time.Time) (*domain.DiscountApproval, error)
+	CountUsers() (int, error)
+	CreateSession(s *domain.Session, tokenHash string) error
+	FindSession(tokenHash string, now time.Time) (*domain.User, error)
+	DeleteSession(tokenHash string, now time.Time) error
+}
+
+// Parámetros de las contraseñas, de las sesiones y de los tokens de aprobación.
+const (
+	MinPasswordLen   = 8
+	pbkdf2Iterations = 600000
+	SessionTTL       = 12 * time.Hour   // Vigencia de una sesión
+	ApprovalTTL      = 15 * time.Minute // Vigencia de un token de aprobación
+)
+
+// UserService contiene la lógica de usuarios, roles, sesiones y autorización de descuentos.
+// Usuarios y roles solo los administra un supervisor o gerente con sesión iniciada.
+type UserService struct {
+	repo  UserRepository
+	audit Auditor
+}
+
+// Constructor del servicio.
+func NewUserService(r UserRepository, audit Auditor) *UserService {
+	return &UserService{repo: r, audit: audit}
+}
+
+// Create valida el usuario, guarda el hash de su contraseña y registra el alta en la auditoría.
+// ErrNotFound si el rol no existe; ErrConflict si el usuario ya existe.
+// Mientras no hay usuarios, el primero se crea sin sesión y debe ser supervisor o gerente.
+func (s *UserService) Create(ctx context.Context, u *domain.User) error {
+
+	first := false
+	if err := s.requireManager(ctx); err != nil {
+		n, countErr := s.repo.CountUsers()
+		if countErr != nil {
+			return countErr
+		}
+		if n > 0 {
+			return err
+		}
+		first = true
+	}
+
+	if err := s.validateUser(u); err != nil {
+		return err
+	}
+	if len(u.Password) < MinPasswordLen {
+		return domain.ErrInvalidInput
+	}
+	if first && !u.CanManage() {
+		return domain.ErrForbidden
+	}
+
+	hash, err := hashPassword(u.Password)
+	if err != nil {
+		return err
+	}
+
+	u.Password = ""
+	u.Creado = time.Now().Truncate(time.Second)
+	return s.repo.Create(u, hash, s.audit.Entry(ctx, domain.AuditCreate, domain.EntityUser))
+}
+
+// validateUser exige usuario sin espacios y un rol existente (con su nombre tal como está guardado).
+// Sin nombre se usa el usuario.
+func (s *UserService) validateUser(u *domain.User) error {
+
+	u.Usuario = strings.TrimSpace(u.Usuario)
+	u.Nombre = strings.TrimSpace(u.Nombre)
+	if u.Usuario == "" || len(u.Usuario) > 100 || strings.ContainsAny(u.Usuario, " \t") {
+		return domain.ErrInvalidInput
+	}
+	if u.Nombre == "" {
+		u.Nombre = u.Usuario
+	}
+
+	role, err := s.repo.Role(strings.TrimSpace(u.Rol))
+	if err != nil {
+		return err
+	}
+	u.Rol = role.Nombre
+
+	return nil
+}
+
+// Get devuelve un usuario.
+func (s *UserService) Get(ctx context.Context, id int64) (*domain.User, error) {
+	if err := s.requireManager(ctx); err != nil {
+		return nil, err
+	}
+	if id <= 0 {
+		return nil, domain.ErrInvalidInput
+	}
+	return s.repo.Get(id)
+}
+
+// List devuelve todos los usuarios.
+func (s *UserService) List(ctx context.Context) ([]domain.User, error) {
+	if err := s.requireManager(ctx); err != nil {
+		return nil, err
+	}
+	return s.repo.List()
+}
+
+// requireManager exige una sesión de supervisor o gerente en el contexto.
+// ErrUnauthenticated sin sesión; ErrForbidden si su rol no administra.
+func (s *UserService) requireManager(ctx context.Context) error {
+	u := domain.UserFrom(ctx)
+	if u == nil {
+		return domain.ErrUnauthenticated
+	}
+	if !u.CanManage() {
+		return domain.ErrForbidden
+	}
+	return nil
+}
+
+// Update cambia nombre, rol y estado (el usuario no cambia) y la contraseña si viene.
+// Registra el cambio en la auditoría sin la contraseña.
+func (s *UserService) Update(ctx context.Context, id int64, u *domain.User) error {
+
+	if err := s.requireManager(ctx); err != nil {
+		return err
+	}
+	if id <= 0 {
+		return domain.ErrInvalidInput
+	}
+
+	before, err := s.repo.Get(id)
+	if err != nil {
+		return err
+	}
+
+	u.Usuario = before.Usuario
+	if err := s.validateUser(u); err != nil {
+		return err
+	}
+
+	var hash string
+	if u.Password != "" {
+		if len(u.Password) < MinPasswordLen {
+			return domain.ErrInvalidInput
+		}
+		if hash, err = hashPassword(u.Password); err != nil {
+			return err
+		}
+	}
+
+	u.Password = ""
+	return s.repo.Update(id, u, hash, s.audit.Entry(ctx, domain.AuditUpdate, domain.EntityUser))
+}
+
+// Roles devuelve los roles con su descuento máximo.
+func (s *UserService) Roles() ([]domain.Role, error) {
+	return s.repo.Roles()
+}
+
+// SaveRole crea un rol o cambia su descuento máximo (0 a 100) y lo registra en la auditoría.
+func (s *UserService) SaveRole(ctx context.Context, role *domain.Role) error {
+
+	if err := s.requireManager(ctx); err != nil {
+		return err
+	}
+
+	role.Nombre = strings.TrimSpace(role.Nombre)
+	if role.Nombre == "" || role.DescuentoMax < 0 || role.DescuentoMax > 100 {
+		return domain.ErrInvalidInput
+	}
+
+	before, err := s.repo.Role(role.Nombre)
+	if err != nil && err != domain.ErrNotFound {
+		return err
+	}
+	accion := domain.AuditCreate
+	if before != nil {
+		role.Nombre = before.Nombre
+		accion = domain.AuditUpdate
+	}
+
+	return s.repo.SaveRole(*role, s.audit.Entry(ctx, accion, domain.EntityRole))
+}
+
+// DiscountAuth arma los límites de descuento manual de una venta. El cajero es el usuario
+// de la sesión del contexto y tiene el descuento máximo de su rol (sin sesión, el del rol
+// cajero). Si vienen credenciales de un supervisor (usuario y contraseña, o token de
+// aprobación) se agrega el límite que él autoriza. ErrBadCredentials si no son válidas;
+// ErrForbidden si el usuario no es supervisor o gerente.
+func (s *UserService) DiscountAuth(ctx context.Context, cred *domain.DiscountCredentials) (domain.DiscountAuth, error) {
+
+	var auth domain.DiscountAuth
+
+	rol := domain.RoleCashier
+	if u := domain.UserFrom(ctx); u != nil {
+		auth.Cajero, rol = u.Usuario, u.Rol
+	}
+
+	role, err := s.repo.Role(rol)
+	if err != nil && err != domain.ErrNotFound {
+		return auth, err
+	}
+	if role != nil {
+		auth.Limite = role.DescuentoMax
+	}
+
+	if cred == nil {
+		return auth, nil
+	}
+
+	if token := strings.TrimSpace(cred.Token); token != "" {
+		a, err := s.repo.FindApproval(tokenHash(token), time.Now())
+		if err == domain.ErrNotFound {
+			return auth, domain.ErrBadCredentials
+		}
+		if err != nil {
+			return auth, err
+		}
+		auth.Supervisor, auth.LimiteSupervisor, auth.ApprovalID = a.Supervisor, a.DescuentoMax, a.ID
+		return auth, nil
+	}
+
+	supervisor, limit, err := s.authenticateManager(cred.Usuario, cred.Password)
+	if err != nil {
+		return auth, err
+	}
+	auth.Supervisor, auth.LimiteSupervisor = supervisor.Usuario, limit
+	return auth, nil
+}
+
+// Approve genera un token de aprobación de un solo uso con las credenciales de un
+// supervisor o gerente (ErrForbidden con otro rol), válido por ApprovalTTL.
+// descuentoMax en 0 toma el máximo de su rol; no puede superarlo (ErrDiscountLimit).
+func (s *UserService) Approve(cred domain.DiscountCredentials, descuentoMax float64) (*domain.DiscountApproval, error) {
+
+	if descuentoMax < 0 || descuentoMax > 100 {
+		return nil, domain.ErrInvalidInput
+	}
+
+	supervisor, limit, err := s.authenticateManager(cred.Usuario, cred.Password)
+	if err != nil {
+		return nil, err
+	}
+	if descuentoMax == 0 {
+		descuentoMax = limit
+	}
+	if descuentoMax > limit {
+		return nil, domain.ErrDiscountLimit
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().Truncate(time.Second)
+	a := &domain.DiscountApproval{
+		Supervisor:   supervisor.Usuario,
+		DescuentoMax: descuentoMax,
+		Creado:       now,
+		Expira:       now.Add(ApprovalTTL),
+	}
+	if err := s.repo.CreateApproval(a, tokenHash(token)); err != nil {
+		return nil, err
+	}
+
+	a.Token = token
+	return a, nil
+}
+
+// Login inicia una sesión con usuario y contraseña, válida por SessionTTL.
+// ErrBadCredentials si no coinciden o el usuario está inactivo.
+func (s *UserService) Login(usuario, password string) (*domain.Session, error) {
+
+	u, _, err := s.authenticate(usuario, password)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().Truncate(time.Second)
+	session := &domain.Session{Usuario: *u, Creado: now, Expira: now.Add(SessionTTL)}
+	if err := s.repo.CreateSession(session, tokenHash(token)); err != nil {
+		return nil, err
+	}
+
+	session.Token = token
+	return session, nil
+}
+
+// SessionUser devuelve el usuario de una sesión vigente.
+// ErrBadCredentials si el token no existe, venció o el usuario está inactivo.
+func (s *UserService) SessionUser(token string) (*domain.User, error) {
+
+	u, err := s.repo.FindSession(tokenHash(token), time.Now())
+	if err == domain.ErrNotFound {
+		return nil, domain.ErrBadCredentials
+	}
+	return u, err
+}
+
+// Logout cierra la sesión del token.
+func (s *UserService) Logout(token string) error {
+	return s.repo.DeleteSession(tokenHash(token), time.Now())
+}
+
+// authenticateManager es authenticate para quien autoriza descuentos: además exige
+// rol supervisor o gerente (ErrForbidden).
+func (s *UserService) authenticateManager(usuario, password string) (*domain.User, float64, error) {
+
+	u, limit, err := s.authenticate(usuario, password)
+	if err != nil {
+		return nil, 0, err
+	}
+	if !u.CanManage() {
+		return nil, 0, domain.ErrForbidden
+	}
+
+	return u, limit, nil
+}
+
+// authenticate verifica usuario y contraseña de un usuario activo y devuelve el
+// descuento máximo de su rol. ErrBadCredentials si no coinciden.
+func (s *UserService) authenticate(usuario, password string) (*domain.User, float64, error) {
+
+	u, hash, err := s.repo.GetByUsername(strings.TrimSpace(usuario))
+	if err == domain.ErrNotFound {
+		return nil, 0, domain.ErrBadCredentials
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	if !u.Activo || !checkPassword(hash, password) {
+		return nil, 0, domain.ErrBadCredentials
+	}
+
+	role, err := s.repo.Role(u.Rol)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return u, role.DescuentoMax, nil
+}
+
+// hashPassword devuelve pbkdf2-sha256$iteraciones$sal$hash con sal aleatoria (base64).
+func hashPassword(password string) (string, error) {
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key, err := pbkdf2.Key(sha256.New, password, salt, pbkdf2Iterations, 32)
+	if err != nil {
+		return "", err
+	}
+
+	enc := base64.RawStdEncoding
+	return fmt.Sprintf("pbkdf2-sha256$%d$%s$%s", pbkdf2Iterations, enc.EncodeToString(salt), enc.EncodeToString(key)), nil
+}
+
+// checkPassword compara la contraseña con un hash de hashPassword en tiempo constante.
+func checkPassword(stored, password string) bool {
+
+	parts := strings.Split(stored, "$")
+	if len(parts) != 4 || parts[0] != "pbkdf2-sha256" {
+		return false
+	}
+	iter, err := strconv.Atoi(parts[1])
+	if err != nil || iter <= 0 {
+		return false
+	}
+
+	enc := base64.RawStdEncoding
+	salt, err := enc.DecodeString(parts[2])
+	if err != nil {
+		return false
+	}
+	want, err := enc.DecodeString(parts[3])
+	if err != nil {
+		return false
+	}
+
+	got, err := pbkdf2.Key(sha256.New, password, salt, iter, len(want))
+	return err == nil && subtle.ConstantTimeCompare(got, want) == 1
+}
+
+// newToken genera un token aleatorio de 48 caracteres hexadecimales.
+func newToken() (string, error) {
+	raw := make([]byte, 24)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(raw), nil
+}
+
+// tokenHash es el sha256 (hex) con que se guarda un token de sesión o de aprobación.
+func tokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,124 @@
+package sqlite
+
+import (
+	"database/sql"
+
+	"ferreteria-inventario-ventas/internal/domain"
+)
+
+// AssociationRepo guarda y consulta los productos que se compran juntos.
+type AssociationRepo struct {
+	db *sql.DB
+}
+
+// Constructor del repositorio.
+func NewAssociationRepo(db *sql.DB) *AssociationRepo {
+	return &AssociationRepo{db: db}
+}
+
+// Rebuild recalcula product_associations con las ventas desde p.Desde en una sola
+// transacción: cada venta es una canasta con sus productos distintos. Para cada par
+// con al menos p.MinVentas ventas en común guarda soporte, confianza y lift.
+// Devuelve la cantidad de ventas analizadas y de pares guardados.
+func (r *AssociationRepo) Rebuild(p domain.AssociationParams) (ventas, pares int, err error) {
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, 0, err
+	}
+	defer tx.Rollback()
+
+	desde := formatTime(p.Desde)
+
+	err = tx.QueryRow(`SELECT COUNT(*) FROM sales WHERE fecha >= ?`, desde).Scan(&ventas)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM product_associations`); err != nil {
+		return 0, 0, err
+	}
+
+	if ventas > 0 {
+		res, err := tx.Exec(`
+			WITH basket AS (
+				SELECT DISTINCT si.sale_id, si.product_id
+				FROM sale_items si JOIN sales s ON s.id = si.sale_id
+				WHERE s.fecha >= ?
+			),
+			item AS (
+				SELECT product_id, COUNT(*) AS n FROM basket GROUP BY product_id
+			),
+			pair AS (
+				SELECT a.product_id AS x, b.product_id AS y, COUNT(*) AS n
+				FROM basket a JOIN basket b ON b.sale_id = a.sale_id AND b.product_id <> a.product_id
+				GROUP BY a.product_id, b.product_id
+				HAVING COUNT(*) >= ?
+			)
+			INSERT INTO product_associations(product_id, related_id, ventas, soporte, confianza, lift)
+			SELECT pair.x, pair.y, pair.n,
+			       CAST(pair.n AS REAL) / ?,
+			       CAST(pair.n AS REAL) / ix.n,
+			       CAST(pair.n AS REAL) * ? / (ix.n * iy.n)
+			FROM pair
+			JOIN item ix ON ix.product_id = pair.x
+			JOIN item iy ON iy.product_id = pair.y`,
+			desde, p.MinVentas, ventas, ventas,
+		)
+		if err != nil {
+			return 0, 0, err
+		}
+		n, _ := res.RowsAffected()
+		pares = int(n)
+	}
+
+	return ventas, pares, tx.Commit()
+}
+
+// Recommendations devuelve los productos asociados a productID con lift mayor a minLift,
+// ordenados por confianza y lift. Con inStock solo los que tienen stock.
+// Devuelve ErrNotFound si el producto no existe.
+func (r *AssociationRepo) Recommendations(productID int64, limit int, minLift float64, inStock bool) ([]domain.Recommendation, error) {
+
+	var exists int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM products WHERE id = ?`, productID).Scan(&exists)
+	if err != nil {
+		return nil, err
+	}
+	if exists == 0 {
+		return nil, domain.ErrNotFound
+	}
+
+	stockFilter := ""
+	if inStock {
+		stockFilter = ` AND p.stock > 0`
+	}
+
+	rows, err := r.db.Query(`
+		SELECT p.id, IFNULL(p.sku, ''), p.nombre, p.precio, p.stock, a.ventas, a.soporte, a.confianza, a.lift
+		FROM product_associations a
+		JOIN products p ON p.id = a.related_id
+		WHERE a.product_id = ? AND a.lift > ?`+stockFilter+`
+		ORDER BY a.confianza DESC, a.lift DESC, p.id
+		LIMIT ?`,
+		productID, minLift, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []domain.Recommendation{}
+
+	for rows.Next() {
+		var rc domain.Recommendation
+		err := rows.Scan(&rc.ProductID, &rc.SKU, &rc.Producto, &rc.Precio, &rc.Stock,
+			&rc.Ventas, &rc.Soporte, &rc.Confianza, &rc.Lift)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, rc)
+	}
+
+	return result, rows.Err()
+}
@@ -0,0 +1,114 @@
+package sqlite
+
+import (
+	"database/sql"
+
+	"ferreteria-inventario-ventas/internal/domain"
+)
+
+// AuditRepo lista la auditoría. Los registros los agregan los demás repositorios
+// dentro de la transacción de cada cambio (appendAudit); no hay métodos para
+// modificarlos y la tabla rechaza UPDATE y DELETE con triggers.
+type AuditRepo struct {
+	db *sql.DB
+}
+
+// Constructor del repositorio.
+func NewAuditRepo(db *sql.DB) *AuditRepo {
+	return &AuditRepo{db: db}
+}
+
+// appendAudit guarda en la transacción del cambio el registro que arma audit
+// (sin auditoría si audit es nil) y completa su ID.
+func appendAudit(tx *sql.Tx, audit domain.AuditFunc, id int64, before, after any) error {
+
+	if audit == nil {
+		return nil
+	}
+
+	e, err := audit(id, before, after)
+	if err != nil {
+		return err
+	}
+
+	result, err := tx.Exec(`
+		INSERT INTO audit_log(fecha, actor, request_id, accion, entidad, entidad_id, cambios)
+		VALUES(?,?,?,?,?,?,?)`,
+		formatTime(e.Fecha), e.Actor, e.RequestID, e.Accion, e.Entidad, e.EntidadID, string(e.Cambios),
+	)
+	if err != nil {
+		return err
+	}
+
+	e.ID, _ = result.LastInsertId()
+	return nil
+}
+
+// List devuelve una página de la auditoría que cumple el filtro.
+// Campos de orden: id, fecha.
+func (r *AuditRepo) List(f domain.AuditFilter, p domain.PageParams) (domain.Page[domain.AuditEntry], error) {
+
+	q := listQuery[domain.AuditEntry]{
+		columns: `id, fecha, actor, request_id, accion, entidad, entidad_id, cambios`,
+		from:    `audit_log`,
+		sorts: map[string]string{
+			"id":    "id",
+			"fecha": "fecha",
+		},
+		idCol: "id",
+		scan: func(rows *sql.Rows) (domain.AuditEntry, error) {
+			var e domain.AuditEntry
+			var fecha, cambios string
+			var entidadID sql.NullInt64
+
+			err := rows.Scan(&e.ID, &fecha, &e.Actor, &e.RequestID, &e.Accion, &e.Entidad, &entidadID, &cambios)
+			if err != nil {
+				return e, err
+			}
+
+			e.Fecha = parseTime(fecha)
+			e.Cambios = []byte(cambios)
+			if entidadID.Valid {
+				e.EntidadID = &entidadID.Int64
+			}
+			return e, nil
+		},
+		key: func(e domain.AuditEntry, sort string) (any, int64) {
+			if sort == "fecha" {
+				return formatTime(e.Fecha), e.ID
+			}
+			return e.ID, e.ID
+		},
+	}
+
+	if f.Actor != "" {
+		q.where = append(q.where, `actor = ?`)
+		q.args = append(q.args, f.Actor)
+	}
+	if f.Accion != "" {
+		q.where = append(q.where, `accion = ?`)
+		q.args = append(q.args, f.Accion)
+	}
+	if f.Entidad != "" {
+		q.where = append(q.where, `entidad = ?`)
+		q.args = append(q.args, f.Entidad)
+	}
+	if f.EntidadID > 0 {
+		q.where = append(q.where, `entidad_id = ?`)
+		q.args = append(q.args, f.EntidadID)
+	}
+	if f.RequestID != "" {
+		q.where = append(q.where, `request_id = ?`)
+		q.args = append(q.args, f.RequestID)
+	}
+	if !f.Desde.IsZero() {
+		q.where = append(q.where, `fecha >= ?`)
+		q.args = append(q.args, formatTime(f.Desde))
+	}
+	if !f.Hasta.IsZero() {
+		q.where = append(q.where, `fecha < ?`)
+		q.args = append(q.args, formatTime(f.Hasta))
+	}
+
+	return q.run(r.db, p)
+}
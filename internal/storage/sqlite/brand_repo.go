@@ -0,0 +1,82 @@
+package sqlite
+
+import (
+	"database/sql"
+
+	"ferreteria-inventario-ventas/internal/domain"
+)
+
+// BrandRepo maneja las operaciones de base de datos para marcas.
+type BrandRepo struct {
+	db *sql.DB
+}
+
+// Constructor del repositorio.
+func NewBrandRepo(db *sql.DB) *BrandRepo {
+	return &BrandRepo{db: db}
+}
+
+// Create inserta una nueva marca.
+func (r *BrandRepo) Create(b *domain.Brand) error {
+
+	result, err := r.db.Exec(`INSERT INTO brands(nombre) VALUES(?)`, b.Nombre)
+	if err != nil {
+		return mapConstraintError(err)
+	}
+
+	id, _ := result.LastInsertId()
+	b.ID = id
+
+	return nil
+}
+
+// List devuelve todas las marcas ordenadas por nombre.
+func (r *BrandRepo) List() ([]domain.Brand, error) {
+
+	rows, err := r.db.Query(`SELECT id, nombre FROM brands ORDER BY nombre`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	brands := []domain.Brand{}
+
+	for rows.Next() {
+		var b domain.Brand
+		if err := rows.Scan(&b.ID, &b.Nombre); err != nil {
+			return nil, err
+		}
+		brands = append(brands, b)
+	}
+
+	return brands, rows.Err()
+}
+
+func (r *BrandRepo) Update(id int64, b *domain.Brand) error {
+
+	res, err := r.db.Exec(`UPDATE brands SET nombre=? WHERE id=?`, b.Nombre, id)
+	if err != nil {
+		return mapConstraintError(err)
+	}
+
+	if n, _ := res.RowsAffected(); n == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+// Delete elimina una marca que no tenga productos.
+func (r *BrandRepo) Delete(id int64) error {
+
+	var inUse int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM products WHERE brand_id = ?`, id).Scan(&inUse); err != nil {
+		return err
+	}
+	if inUse > 0 {
+		return domain.ErrConflict
+	}
+
+	_, err := r.db.Exec(`DELETE FROM brands WHERE id=?`, id)
+	return err
+}
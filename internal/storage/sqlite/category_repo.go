@@ -0,0 +1,141 @@
+package sqlite
+
+import (
+	"database/sql"
+
+	"ferreteria-inventario-ventas/internal/domain"
+)
+
+// categorySubtreeSQL devuelve los IDs de una categoría y todas sus descendientes.
+// Recibe un parámetro: el ID de la categoría raíz del subárbol.
+const categorySubtreeSQL = `
+	WITH RECURSIVE subtree(id) AS (
+		SELECT ?
+		UNION ALL
+		SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id
+	)
+	SELECT id FROM subtree`
+
+// CategoryRepo maneja las operaciones de base de datos para categorías.
+type CategoryRepo struct {
+	db *sql.DB
+}
+
+// Constructor del repositorio.
+func NewCategoryRepo(db *sql.DB) *CategoryRepo {
+	return &CategoryRepo{db: db}
+}
+
+// Create inserta una nueva categoría.
+func (r *CategoryRepo) Create(c *domain.Category) error {
+
+	result, err := r.db.Exec(
+		`INSERT INTO categories(nombre, parent_id) VALUES(?,?)`,
+		c.Nombre, c.ParentID,
+	)
+	if err != nil {
+		return mapConstraintError(err)
+	}
+
+	id, _ := result.LastInsertId()
+	c.ID = id
+
+	return nil
+}
+
+// List devuelve todas las categorías con su ruta completa, ordenadas por ruta.
+func (r *CategoryRepo) List() ([]domain.Category, error) {
+
+	rows, err := r.db.Query(`
+		WITH RECURSIVE tree(id, nombre, parent_id, ruta) AS (
+			SELECT id, nombre, parent_id, nombre FROM categories WHERE parent_id IS NULL
+			UNION ALL
+			SELECT c.id, c.nombre, c.parent_id, t.ruta || ' > ' || c.nombre
+			FROM categories c JOIN tree t ON c.parent_id = t.id
+		)
+		SELECT id, nombre, parent_id, ruta FROM tree ORDER BY ruta
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []domain.Category{}
+
+	for rows.Next() {
+		var c domain.Category
+		if err := rows.Scan(&c.ID, &c.Nombre, &c.ParentID, &c.Ruta); err != nil {
+			return nil, err
+		}
+		list = append(list, c)
+	}
+
+	return list, rows.Err()
+}
+
+// Get devuelve una categoría por ID.
+func (r *CategoryRepo) Get(id int64) (*domain.Category, error) {
+
+	var c domain.Category
+
+	err := r.db.QueryRow(
+		`SELECT id, nombre, parent_id FROM categories WHERE id = ?`,
+		id,
+	).Scan(&c.ID, &c.Nombre, &c.ParentID)
+
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// IsDescendant indica si candidate es id o está dentro del subárbol de id.
+// Se usa para evitar ciclos al mover una categoría.
+func (r *CategoryRepo) IsDescendant(id, candidate int64) (bool, error) {
+	var count int
+	err := r.db.QueryRow(
+		`SELECT COUNT(*) FROM (`+categorySubtreeSQL+`) WHERE id = ?`,
+		id, candidate,
+	).Scan(&count)
+	return count > 0, err
+}
+
+func (r *CategoryRepo) Update(id int64, c *domain.Category) error {
+
+	res, err := r.db.Exec(
+		`UPDATE categories SET nombre=?, parent_id=? WHERE id=?`,
+		c.Nombre, c.ParentID, id,
+	)
+	if err != nil {
+		return mapConstraintError(err)
+	}
+
+	if n, _ := res.RowsAffected(); n == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+// Delete elimina una categoría sin subcategorías ni productos.
+func (r *CategoryRepo) Delete(id int64) error {
+
+	var inUse int
+	err := r.db.QueryRow(`
+		SELECT (SELECT COUNT(*) FROM categories WHERE parent_id = ?) +
+		       (SELECT COUNT(*) FROM products WHERE category_id = ?)
+	`, id, id).Scan(&inUse)
+	if err != nil {
+		return err
+	}
+	if inUse > 0 {
+		return domain.ErrConflict
+	}
+
+	_, err = r.db.Exec(`DELETE FROM categories WHERE id=?`, id)
+	return err
+}
@@ -16,55 +16,182 @@ func NewClientRepo(db *sql.DB) *ClientRepo {
 	return &ClientRepo{db: db}
 }
 
-// Create inserta un nuevo cliente en la base de datos.
-func (r *ClientRepo) Create(c *domain.Client) error {
+// Create inserta un nuevo cliente en la base de datos y registra el alta con audit
+// en la misma transacción.
+func (r *ClientRepo) Create(c *domain.Client, audit domain.AuditFunc) error {
 
-	result, err := r.db.Exec(
-		`INSERT INTO clients(nombre, cedula, email) VALUES(?,?,?)`,
-		c.Nombre, c.Cedula, c.Email,
-	)
+	tx, err := r.db.Begin()
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(
+		`INSERT INTO clients(nombre, cedula, email, price_list_id) VALUES(?,?,?,?)`,
+		c.Nombre, c.Cedula, c.Email, c.PriceListID,
+	)
+	if err != nil {
+		return mapConstraintError(err)
+	}
 
 	id, _ := result.LastInsertId()
 	c.ID = id
 
-	return nil
+	after, err := getClient(tx, id)
+	if err != nil {
+		return err
+	}
+	if err := appendAudit(tx, audit, id, nil, after); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Get devuelve un cliente por ID.
+func (r *ClientRepo) Get(id int64) (*domain.Client, error) {
+	return getClient(r.db, id)
 }
 
-// List devuelve todos los clientes.
-func (r *ClientRepo) List() ([]domain.Client, error) {
+// getClient lee un cliente con la base o dentro de una transacción.
+func getClient(q queryer, id int64) (*domain.Client, error) {
 
-	rows, err := r.db.Query(`SELECT id, nombre, cedula, email FROM clients ORDER BY id DESC`)
+	c, err := scanClient(q.QueryRow(`SELECT `+clientColumns+` FROM clients WHERE id = ?`, id))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
 
-	var clients []domain.Client
+	return &c, nil
+}
+
+// clientColumns son las columnas que se leen de un cliente, con el nombre de su lista de precios.
+const clientColumns = `id, nombre, cedula, email, price_list_id,
+	IFNULL((SELECT l.nombre FROM price_lists l WHERE l.id = clients.price_list_id), '')`
 
-	for rows.Next() {
-		var c domain.Client
-		err := rows.Scan(&c.ID, &c.Nombre, &c.Cedula, &c.Email)
-		if err != nil {
-			return nil, err
-		}
-		clients = append(clients, c)
+// scanClient lee una fila con clientColumns.
+func scanClient(row rowScanner) (domain.Client, error) {
+	var c domain.Client
+	err := row.Scan(&c.ID, &c.Nombre, &c.Cedula, &c.Email, &c.PriceListID, &c.ListaPrecios)
+	return c, err
+}
+
+// List devuelve una página de clientes que cumplen el filtro.
+// Campos de orden: id, nombre, cedula.
+func (r *ClientRepo) List(f domain.ClientFilter, p domain.PageParams) (domain.Page[domain.Client], error) {
+	return clientListQuery(f).run(r.db, p)
+}
+
+// Each recorre todos los clientes que cumplen el filtro (para exportar).
+func (r *ClientRepo) Each(f domain.ClientFilter, fn func(domain.Client) error) error {
+	return clientListQuery(f).each(r.db, fn)
+}
+
+// clientListQuery arma la consulta de clientes con los filtros aplicados.
+func clientListQuery(f domain.ClientFilter) listQuery[domain.Client] {
+
+	q := listQuery[domain.Client]{
+		columns: clientColumns,
+		from:    `clients`,
+		sorts: map[string]string{
+			"id":     "id",
+			"nombre": "nombre",
+			"cedula": "cedula",
+		},
+		idCol: "id",
+		scan: func(rows *sql.Rows) (domain.Client, error) {
+			return scanClient(rows)
+		},
+		key: func(c domain.Client, sort string) (any, int64) {
+			switch sort {
+			case "nombre":
+				return c.Nombre, c.ID
+			case "cedula":
+				return c.Cedula, c.ID
+			}
+			return c.ID, c.ID
+		},
+	}
+
+	if f.Nombre != "" {
+		q.where = append(q.where, `nombre LIKE ? ESCAPE '\'`)
+		q.args = append(q.args, "%"+escapeLike(f.Nombre)+"%")
+	}
+	if f.Cedula != "" {
+		q.where = append(q.where, `cedula LIKE ? ESCAPE '\'`)
+		q.args = append(q.args, escapeLike(f.Cedula)+"%")
+	}
+	if f.Email != "" {
+		q.where = append(q.where, `email LIKE ? ESCAPE '\'`)
+		q.args = append(q.args, "%"+escapeLike(f.Email)+"%")
 	}
 
-	return clients, nil
+	return q
 }
 
-func (r *ClientRepo) Update(id int64, c *domain.Client) error {
-	_, err := r.db.Exec(
-		`UPDATE clients SET nombre=?, cedula=?, email=? WHERE id=?`,
-		c.Nombre, c.Cedula, c.Email, id,
-	)
-	return err
+// Update guarda los datos del cliente y registra el cambio con audit en la misma transacción.
+func (r *ClientRepo) Update(id int64, c *domain.Client, audit domain.AuditFunc) error {
+	return r.update(id, audit, `UPDATE clients SET nombre=?, cedula=?, email=?, price_list_id=? WHERE id=?`,
+		c.Nombre, c.Cedula, c.Email, c.PriceListID, id)
 }
 
-func (r *ClientRepo) Delete(id int64) error {
-	_, err := r.db.Exec(`DELETE FROM clients WHERE id=?`, id)
-	return err
+// SetPriceList asigna una lista de precios al cliente (nil la quita) y registra
+// el cambio con audit. ErrNotFound si el cliente o la lista no existen.
+func (r *ClientRepo) SetPriceList(id int64, listID *int64, audit domain.AuditFunc) error {
+	return r.update(id, audit, `UPDATE clients SET price_list_id=? WHERE id=?`, listID, id)
+}
+
+// update ejecuta un UPDATE sobre el cliente id y lo audita con el antes y el después.
+func (r *ClientRepo) update(id int64, audit domain.AuditFunc, query string, args ...any) error {
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := getClient(tx, id)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(query, args...); err != nil {
+		return mapConstraintError(err)
+	}
+
+	after, err := getClient(tx, id)
+	if err != nil {
+		return err
+	}
+	if err := appendAudit(tx, audit, id, before, after); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Delete elimina el cliente y registra con audit cómo era, en la misma transacción.
+func (r *ClientRepo) Delete(id int64, audit domain.AuditFunc) error {
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := getClient(tx, id)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM clients WHERE id=?`, id); err != nil {
+		return err
+	}
+	if err := appendAudit(tx, audit, id, before, nil); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
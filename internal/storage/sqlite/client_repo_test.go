@@ -0,0 +1,45 @@
+package sqlite
+
+import (
+	"testing"
+
+	"ferreteria-inventario-ventas/internal/domain"
+)
+
+// Los filtros buscan % y _ como texto, no como comodines de LIKE.
+func TestClientListFilterEscapesWildcards(t *testing.T) {
+
+	db := newTestDB(t)
+	repo := NewClientRepo(db)
+
+	for _, c := range []domain.Client{
+		{Nombre: "Ana Pérez", Cedula: "0102030405", Email: "ana_perez@test.com"},
+		{Nombre: "Anabel", Cedula: "0102030406", Email: "anaxperez@test.com"},
+	} {
+		if err := repo.Create(&c, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		filter domain.ClientFilter
+		want   int
+	}{
+		{"guion bajo", domain.ClientFilter{Email: "ana_perez"}, 1},
+		{"porcentaje", domain.ClientFilter{Nombre: "%"}, 0},
+		{"texto normal", domain.ClientFilter{Nombre: "Ana"}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := repo.List(tt.filter, domain.PageParams{Limit: 10, Sort: "id"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(page.Items) != tt.want {
+				t.Errorf("%d clientes, se esperaban %d", len(page.Items), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package sqlite
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"ferreteria-inventario-ventas/internal/domain"
+)
+
+// ClientPurchases devuelve el cliente con el resumen de sus compras en [desde, hasta)
+// (fechas en cero = sin límite) y los productos que compró, por monto.
+// Las cabeceras paginadas las agrega el servicio. ErrNotFound si el cliente no existe.
+func (r *SaleRepo) ClientPurchases(clientID int64, desde, hasta time.Time) (*domain.ClientHistory, error) {
+
+	h := domain.ClientHistory{Productos: []domain.ClientProduct{}}
+
+	err := r.db.QueryRow(
+		`SELECT id, nombre, cedula, email FROM clients WHERE id = ?`, clientID,
+	).Scan(&h.Cliente.ID, &h.Cliente.Nombre, &h.Cliente.Cedula, &h.Cliente.Email)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	where, args := saleFilterConds(domain.SaleFilter{ClientID: clientID, Desde: desde, Hasta: hasta})
+	filter := " WHERE " + strings.Join(where, " AND ")
+
+	var first, last sql.NullString
+	err = r.db.QueryRow(`
+		SELECT COUNT(*), IFNULL(SUM(s.total), 0), MIN(s.fecha), MAX(s.fecha),
+		       IFNULL((SELECT SUM(si.cantidad) FROM sale_items si JOIN sales s ON s.id = si.sale_id`+filter+`), 0)
+		FROM sales s`+filter,
+		append(append([]any{}, args...), args...)...,
+	).Scan(&h.Ventas, &h.Total, &first, &last, &h.Unidades)
+	if err != nil {
+		return nil, err
+	}
+	if first.Valid {
+		t := parseTime(first.String)
+		h.PrimeraCompra = &t
+	}
+	if last.Valid {
+		t := parseTime(last.String)
+		h.UltimaCompra = &t
+	}
+
+	rows, err := r.db.Query(`
+		SELECT p.id, IFNULL(p.sku, ''), p.nombre, SUM(si.cantidad), SUM(si.subtotal),
+		       COUNT(DISTINCT si.sale_id), MAX(s.fecha)
+		FROM sale_items si
+		JOIN sales s ON s.id = si.sale_id
+		JOIN products p ON p.id = si.product_id`+filter+`
+		GROUP BY p.id
+		ORDER BY 5 DESC, p.id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cp domain.ClientProduct
+		var lastStr string
+		err := rows.Scan(&cp.ProductID, &cp.SKU, &cp.Producto, &cp.Cantidad, &cp.Total, &cp.Ventas, &lastStr)
+		if err != nil {
+			return nil, err
+		}
+		cp.UltimaCompra = parseTime(lastStr)
+		h.Productos = append(h.Productos, cp)
+	}
+
+	return &h, rows.Err()
+}
+
+// ClientActivity devuelve, para cada cliente que compró alguna vez antes de hasta,
+// la fecha de su última compra y la cantidad y el monto de sus compras en [desde, hasta).
+// Puntajes y segmentos los calcula el servicio.
+func (r *SaleRepo) ClientActivity(desde, hasta time.Time) ([]domain.RFMRow, error) {
+
+	d, h := formatTime(desde), formatTime(hasta)
+
+	rows, err := r.db.Query(`
+		SELECT c.id, c.nombre, c.cedula, c.email, MAX(s.fecha),
+		       SUM(CASE WHEN s.fecha >= ? THEN 1 ELSE 0 END),
+		       SUM(CASE WHEN s.fecha >= ? THEN s.total ELSE 0 END)
+		FROM clients c
+		JOIN sales s ON s.client_id = c.id
+		WHERE s.fecha < ?
+		GROUP BY c.id
+		ORDER BY c.id`, d, d, h)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []domain.RFMRow{}
+
+	for rows.Next() {
+		var row domain.RFMRow
+		var last string
+		err := rows.Scan(&row.ClientID, &row.Nombre, &row.Cedula, &row.Email, &last, &row.Frecuencia, &row.Monto)
+		if err != nil {
+			return nil, err
+		}
+		row.UltimaCompra = parseTime(last)
+		result = append(result, row)
+	}
+
+	return result, rows.Err()
+}
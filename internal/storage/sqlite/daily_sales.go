@@ -0,0 +1,179 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"ferreteria-inventario-ventas/internal/domain"
+)
+
+// addDailySales suma las líneas de la venta saleID al resumen del día dia.
+// Se llama dentro de la transacción que crea la venta (y al reconstruir).
+func addDailySales(tx *sql.Tx, saleID int64, dia string) error {
+	_, err := tx.Exec(`
+		INSERT INTO daily_product_sales(dia, product_id, cantidad, ventas, subtotal, neto, costo)
+		SELECT ?, si.product_id, SUM(si.cantidad), 1, SUM(si.subtotal),
+		       SUM(`+lineNetSQL+`), SUM(`+lineCostSQL+`)
+		FROM sale_items si
+		JOIN sales s ON s.id = si.sale_id
+		WHERE si.sale_id = ?
+		GROUP BY si.product_id
+		ON CONFLICT(dia, product_id) DO UPDATE SET
+			cantidad = cantidad + excluded.cantidad,
+			ventas = ventas + excluded.ventas,
+			subtotal = subtotal + excluded.subtotal,
+			neto = neto + excluded.neto,
+			costo = costo + excluded.costo`, dia, saleID)
+	return err
+}
+
+// localDay es el día de la tienda (time.Local) en que cae t.
+func localDay(t time.Time) string {
+	return t.In(time.Local).Format(time.DateOnly)
+}
+
+// RebuildDailySales vacía y recalcula daily_product_sales con todas las ventas
+// en la zona horaria actual, en una sola transacción. Devuelve las ventas leídas
+// y las filas (día, producto) generadas.
+func (r *SaleRepo) RebuildDailySales() (ventas, filas int, err error) {
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, 0, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM daily_product_sales`); err != nil {
+		return 0, 0, err
+	}
+
+	// Primero se leen todas: no se puede escribir mientras se recorre la consulta
+	type sale struct {
+		id  int64
+		dia string
+	}
+	var sales []sale
+
+	rows, err := tx.Query(`SELECT id, fecha FROM sales ORDER BY id`)
+	if err != nil {
+		return 0, 0, err
+	}
+	for rows.Next() {
+		var s sale
+		var fecha string
+		if err := rows.Scan(&s.id, &fecha); err != nil {
+			rows.Close()
+			return 0, 0, err
+		}
+		s.dia = localDay(parseTime(fecha))
+		sales = append(sales, s)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, 0, err
+	}
+
+	for _, s := range sales {
+		if err := addDailySales(tx, s.id, s.dia); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM daily_product_sales`).Scan(&filas); err != nil {
+		return 0, 0, err
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO daily_sales_state(id, zona, reconstruido) VALUES(1, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET zona = excluded.zona, reconstruido = excluded.reconstruido`,
+		time.Local.String(), formatTime(time.Now()))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return len(sales), filas, tx.Commit()
+}
+
+// DailySalesZone devuelve la zona horaria con la que se armó el resumen diario
+// ("" si nunca se reconstruyó).
+func (r *SaleRepo) DailySalesZone() (string, error) {
+	var zona string
+	err := r.db.QueryRow(`SELECT zona FROM daily_sales_state WHERE id = 1`).Scan(&zona)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return zona, err
+}
+
+// dailyRange indica si [desde, hasta) se puede leer del resumen diario: el resumen
+// está armado con la zona horaria actual y los límites son inicios de día locales
+// (o cero = sin límite). Devuelve los días [from, to) para filtrar por dia.
+func (r *SaleRepo) dailyRange(ctx context.Context, desde, hasta time.Time) (from, to string, ok bool, err error) {
+
+	if !isLocalMidnight(desde) || !isLocalMidnight(hasta) {
+		return "", "", false, nil
+	}
+
+	var zona string
+	err = r.db.QueryRowContext(ctx, `SELECT zona FROM daily_sales_state WHERE id = 1`).Scan(&zona)
+	if err == sql.ErrNoRows || (err == nil && zona != time.Local.String()) {
+		return "", "", false, nil
+	}
+	if err != nil {
+		return "", "", false, err
+	}
+
+	// Sin límite: cualquier día es >= "" y < "9999"
+	to = "9999"
+	if !desde.IsZero() {
+		from = localDay(desde)
+	}
+	if !hasta.IsZero() {
+		to = localDay(hasta)
+	}
+
+	return from, to, true, nil
+}
+
+func isLocalMidnight(t time.Time) bool {
+	if t.IsZero() {
+		return true
+	}
+	l := t.In(time.Local)
+	return l.Hour() == 0 && l.Minute() == 0 && l.Second() == 0 && l.Nanosecond() == 0
+}
+
+// dailyUnits llena las unidades vendidas por día de cada serie desde el resumen.
+// days son días locales completos y consecutivos.
+func (r *SaleRepo) dailyUnits(days []domain.TimeRange, from, to string, result []domain.DemandSeries, index map[int64]int) error {
+
+	pos := make(map[string]int, len(days))
+	for i, d := range days {
+		pos[localDay(d.Desde)] = i
+	}
+
+	rows, err := r.db.Query(`
+		SELECT product_id, dia, cantidad FROM daily_product_sales
+		WHERE dia >= ? AND dia < ?`, from, to)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var productID int64
+		var dia string
+		var units int
+		if err := rows.Scan(&productID, &dia, &units); err != nil {
+			return err
+		}
+		i, ok := index[productID]
+		day, inRange := pos[dia]
+		if ok && inRange {
+			result[i].Unidades[day] = units
+		}
+	}
+
+	return rows.Err()
+}
@@ -6,16 +6,36 @@ import (
 	_ "modernc.org/sqlite"
 )
 
+// OpenDB abre la base SQLite.
+// Los pragmas se pasan en el DSN para que apliquen a todas las conexiones del pool:
+// - foreign_keys: valida las llaves foráneas
+// - busy_timeout: espera en vez de fallar si otra conexión está escribiendo
+// - journal_mode WAL: lecturas concurrentes mientras se escribe
+// _txlock=immediate hace que cada transacción tome el bloqueo de escritura al iniciar,
+// así dos ventas simultáneas se ejecutan una después de otra y nunca venden el mismo stock.
 func OpenDB(path string) (*sql.DB, error) {
-	db, err := sql.Open("sqlite", path)
+	dsn := "file:" + path +
+		"?_pragma=foreign_keys(1)" +
+		"&_pragma=busy_timeout(5000)" +
+		"&_pragma=journal_mode(WAL)" +
+		"&_txlock=immediate"
+
+	db, err := sql.Open("sqlite", dsn)
 	if err != nil {
 		return nil, err
 	}
 
-	if _, err := db.Exec(`PRAGMA foreign_keys = ON;`); err != nil {
+	if err := db.Ping(); err != nil {
 		db.Close()
 		return nil, err
 	}
 
 	return db, nil
 }
+
+// queryer permite consultar con *sql.DB o *sql.Tx: las mismas lecturas sirven
+// fuera y dentro de una transacción.
+type queryer interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
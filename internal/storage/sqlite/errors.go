@@ -0,0 +1,23 @@
+package sqlite
+
+import (
+	"strings"
+
+	"ferreteria-inventario-ventas/internal/domain"
+)
+
+// mapConstraintError traduce violaciones de restricciones de SQLite a errores del dominio:
+// UNIQUE => ErrConflict, FOREIGN KEY => ErrNotFound. Otros errores se devuelven igual.
+func mapConstraintError(err error) error {
+	if err == nil {
+		return nil
+	}
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "UNIQUE constraint failed"), strings.Contains(msg, "PRIMARY KEY constraint failed"):
+		return domain.ErrConflict
+	case strings.Contains(msg, "FOREIGN KEY constraint failed"):
+		return domain.ErrNotFound
+	}
+	return err
+}
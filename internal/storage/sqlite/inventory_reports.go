@@ -0,0 +1,163 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"ferreteria-inventario-ventas/internal/domain"
+)
+
+// categoryPathSQL devuelve id y ruta completa (Plomería > Tuberías) de cada categoría.
+const categoryPathSQL = `
+	WITH RECURSIVE tree(id, ruta) AS (
+		SELECT id, nombre FROM categories WHERE parent_id IS NULL
+		UNION ALL
+		SELECT c.id, t.ruta || ' > ' || c.nombre
+		FROM categories c JOIN tree t ON c.parent_id = t.id
+	)
+	SELECT id, ruta FROM tree`
+
+// Valuation devuelve cantidad, costo y precio de cada producto con stock distinto de cero,
+// ordenados por ruta de categoría (sin categoría al final) y nombre.
+//
+// Con asOf en cero usa el stock y el costo actuales. Con una fecha reconstruye el stock
+// hacia atrás: stock actual - movimientos desde asOf + unidades vendidas desde asOf;
+// el costo es el promedio vigente en el último movimiento o venta anterior a asOf
+// (si no hay ninguno, el costo actual). El precio siempre es el actual.
+func (r *ProductRepo) Valuation(asOf time.Time, categoryID int64) ([]domain.ValuationRow, error) {
+
+	var args []any
+
+	cantidad := `p.stock`
+	costo := `p.costo`
+	joins := ``
+
+	if !asOf.IsZero() {
+		at := formatTime(asOf)
+
+		cantidad = `p.stock - IFNULL(mv.cantidad, 0) + IFNULL(sv.cantidad, 0)`
+		costo = `IFNULL((
+			SELECT e.costo FROM (
+				SELECT m.fecha AS fecha, m.costo_unitario AS costo FROM stock_movements m
+				WHERE m.product_id = p.id AND m.fecha < ?
+				UNION ALL
+				SELECT s.fecha, si.costo_unitario FROM sale_items si JOIN sales s ON s.id = si.sale_id
+				WHERE si.product_id = p.id AND s.fecha < ?
+			) e ORDER BY e.fecha DESC LIMIT 1
+		), p.costo)`
+		joins = `
+			LEFT JOIN (
+				SELECT product_id, SUM(cantidad) AS cantidad FROM stock_movements
+				WHERE fecha >= ? GROUP BY product_id
+			) mv ON mv.product_id = p.id
+			LEFT JOIN (
+				SELECT si.product_id, SUM(si.cantidad) AS cantidad
+				FROM sale_items si JOIN sales s ON s.id = si.sale_id
+				WHERE s.fecha >= ? GROUP BY si.product_id
+			) sv ON sv.product_id = p.id`
+
+		// Orden de los ? en la consulta: subconsulta de costo y luego los joins
+		args = append(args, at, at, at, at)
+	}
+
+	where := ""
+	if categoryID > 0 {
+		where = `
+			WHERE p.category_id IN (` + categorySubtreeSQL + `)`
+		args = append(args, categoryID)
+	}
+
+	rows, err := r.db.Query(`
+		WITH cat(id, ruta) AS (`+categoryPathSQL+`)
+		SELECT * FROM (
+			SELECT p.id, IFNULL(p.sku, ''), p.nombre, p.category_id, IFNULL(cat.ruta, ''),
+			       `+cantidad+` AS cantidad, `+costo+`, p.precio
+			FROM products p
+			LEFT JOIN cat ON cat.id = p.category_id`+joins+where+`
+		) v
+		WHERE v.cantidad <> 0
+		ORDER BY v.category_id IS NULL, 5, 3, 1`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []domain.ValuationRow{}
+
+	for rows.Next() {
+		var v domain.ValuationRow
+		err := rows.Scan(&v.ProductID, &v.SKU, &v.Producto, &v.CategoryID, &v.Categoria,
+			&v.Cantidad, &v.CostoUnitario, &v.PrecioUnitario)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, v)
+	}
+
+	return result, rows.Err()
+}
+
+// DeadStock devuelve los productos con stock que no tienen ventas desde since,
+// con la fecha de su última venta (si alguna vez se vendieron). No incluye productos
+// creados después de since (todavía no tuvieron tiempo de venderse).
+// Ordenados por capital inmovilizado (stock * costo).
+func (r *ProductRepo) DeadStock(since time.Time, categoryID int64) ([]domain.DeadStockRow, error) {
+
+	at := formatTime(since)
+	args := []any{at, at, domain.MovementInitial}
+
+	where := ""
+	if categoryID > 0 {
+		where = `
+			AND p.category_id IN (` + categorySubtreeSQL + `)`
+		args = append(args, categoryID)
+	}
+
+	rows, err := r.db.Query(`
+		WITH cat(id, ruta) AS (`+categoryPathSQL+`),
+		last AS (
+			SELECT si.product_id, MAX(s.fecha) AS fecha
+			FROM sale_items si JOIN sales s ON s.id = si.sale_id
+			GROUP BY si.product_id
+		)
+		SELECT p.id, IFNULL(p.sku, ''), p.nombre, IFNULL(cat.ruta, ''), p.stock, p.costo, last.fecha
+		FROM products p
+		LEFT JOIN cat ON cat.id = p.category_id
+		LEFT JOIN last ON last.product_id = p.id
+		WHERE p.stock > 0
+		  AND (last.fecha IS NULL OR last.fecha < ?)
+		  AND NOT EXISTS (
+			SELECT 1 FROM stock_movements m
+			WHERE m.product_id = p.id AND m.fecha >= ? AND m.tipo = ?
+		  )`+where+`
+		ORDER BY p.stock * p.costo DESC, p.id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []domain.DeadStockRow{}
+
+	for rows.Next() {
+		var d domain.DeadStockRow
+		var last sql.NullString
+		if err := rows.Scan(&d.ProductID, &d.SKU, &d.Producto, &d.Categoria, &d.Stock, &d.Costo, &last); err != nil {
+			return nil, err
+		}
+		if last.Valid {
+			t := parseTime(last.String)
+			d.UltimaVenta = &t
+		}
+		result = append(result, d)
+	}
+
+	return result, rows.Err()
+}
+
+// CountLowStock cuenta los productos con stock menor que threshold.
+func (r *ProductRepo) CountLowStock(ctx context.Context, threshold int) (int, error) {
+	var n int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM products WHERE stock < ?`, threshold).Scan(&n)
+	return n, err
+}
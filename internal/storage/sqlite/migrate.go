@@ -5,6 +5,32 @@ import (
 	"os"
 )
 
+// columnMigration agrega una columna nueva a una tabla que ya existía.
+// En bases nuevas la columna viene en el CREATE TABLE de schema.sql.
+type columnMigration struct {
+	table      string
+	column     string
+	definition string
+}
+
+// columnMigrations lista las columnas agregadas después de la primera versión del esquema.
+// Se aplican antes de schema.sql para que sus índices y triggers encuentren la columna.
+var columnMigrations = []columnMigration{
+	{"products", "sku", "TEXT"},
+	{"products", "category_id", "INTEGER REFERENCES categories(id)"},
+	{"products", "brand_id", "INTEGER REFERENCES brands(id)"},
+	{"sales", "descuento", "REAL NOT NULL DEFAULT 0"},
+	{"sales", "impuesto", "REAL NOT NULL DEFAULT 0"},
+	{"products", "costo", "REAL NOT NULL DEFAULT 0"},
+	{"sale_items", "costo_unitario", "REAL NOT NULL DEFAULT 0"},
+	{"clients", "price_list_id", "INTEGER REFERENCES price_lists(id)"},
+	{"sale_items", "price_list_id", "INTEGER REFERENCES price_lists(id)"},
+	{"sales", "descuento_lineas", "REAL NOT NULL DEFAULT 0"},
+	{"sale_items", "descuento", "REAL NOT NULL DEFAULT 0"},
+	{"sales", "cajero", "TEXT"},
+	{"sales", "autorizado_por", "TEXT"},
+}
+
 // Migrate ejecuta el archivo schema.sql.
 // Su función es crear las tablas si no existen.
 func Migrate(db *sql.DB, schemaPath string) error {
@@ -15,11 +41,72 @@ func Migrate(db *sql.DB, schemaPath string) error {
 		return err
 	}
 
+	// Agregar columnas nuevas a tablas existentes
+	for _, m := range columnMigrations {
+		if err := addColumnIfMissing(db, m); err != nil {
+			return err
+		}
+	}
+
+	// Revisar si el índice de búsqueda ya existía antes de migrar
+	ftsExists, err := tableExists(db, "products_fts")
+	if err != nil {
+		return err
+	}
+
+	// Y si el historial de precios ya existía
+	pricesExist, err := tableExists(db, "price_history")
+	if err != nil {
+		return err
+	}
+
 	// Ejecutar el SQL en la base de datos
 	_, err = db.Exec(string(content))
 	if err != nil {
 		return err
 	}
 
+	// Si el índice es nuevo, indexar los productos que ya estaban cargados
+	if !ftsExists {
+		if _, err := db.Exec(`INSERT INTO products_fts(products_fts) VALUES ('rebuild')`); err != nil {
+			return err
+		}
+	}
+
+	// Si el historial es nuevo, armarlo con los precios de los productos cargados
+	if !pricesExist {
+		if err := seedPriceHistory(db); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
+
+// tableExists indica si existe una tabla (o tabla virtual) con ese nombre.
+func tableExists(db *sql.DB, name string) (bool, error) {
+	var count int
+	err := db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?`, name).Scan(&count)
+	return count > 0, err
+}
+
+// addColumnIfMissing ejecuta ALTER TABLE ADD COLUMN solo si la tabla existe y no tiene la columna.
+func addColumnIfMissing(db *sql.DB, m columnMigration) error {
+
+	exists, err := tableExists(db, m.table)
+	if err != nil || !exists {
+		return err
+	}
+
+	var count int
+	err = db.QueryRow(
+		`SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?`,
+		m.table, m.column,
+	).Scan(&count)
+	if err != nil || count > 0 {
+		return err
+	}
+
+	_, err = db.Exec(`ALTER TABLE ` + m.table + ` ADD COLUMN ` + m.column + ` ` + m.definition)
+	return err
+}
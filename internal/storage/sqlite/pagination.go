@@ -0,0 +1,175 @@
+package sqlite
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	"ferreteria-inventario-ventas/internal/domain"
+)
+
+// listQuery describe un listado paginado con cursor (keyset).
+// El orden siempre se desempata por id para que el cursor sea estable.
+type listQuery[T any] struct {
+	columns string            // Columnas del SELECT
+	from    string            // FROM y JOINs
+	where   []string          // Condiciones de los filtros
+	args    []any             // Argumentos de las condiciones
+	sorts   map[string]string // Campo de orden permitido -> columna SQL
+	idCol   string            // Columna id usada como desempate
+
+	scan func(*sql.Rows) (T, error)
+	// key devuelve el valor del campo de orden y el id de una fila, para armar el cursor
+	key func(item T, sort string) (any, int64)
+}
+
+// cursor es el contenido del cursor opaco.
+type cursor struct {
+	Sort  string `json:"s"`
+	Desc  bool   `json:"d"`
+	Value any    `json:"v"`
+	ID    int64  `json:"id"`
+}
+
+func encodeCursor(c cursor) string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(s string) (cursor, error) {
+	var c cursor
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, domain.ErrInvalidInput
+	}
+	if err := json.Unmarshal(b, &c); err != nil {
+		return c, domain.ErrInvalidInput
+	}
+	return c, nil
+}
+
+// run ejecuta el conteo total y la consulta de la página.
+func (q listQuery[T]) run(db *sql.DB, p domain.PageParams) (domain.Page[T], error) {
+
+	var page domain.Page[T]
+
+	col, ok := q.sorts[p.Sort]
+	if !ok {
+		return page, domain.ErrInvalidInput
+	}
+
+	where := ""
+	if len(q.where) > 0 {
+		where = " WHERE " + strings.Join(q.where, " AND ")
+	}
+
+	// 1) Total sin cursor
+	if err := db.QueryRow(`SELECT COUNT(*) FROM `+q.from+where, q.args...).Scan(&page.Total); err != nil {
+		return page, err
+	}
+
+	// 2) Condición del cursor: (col, id) después del último valor visto
+	conds := append([]string{}, q.where...)
+	args := append([]any{}, q.args...)
+
+	op, dir := ">", "ASC"
+	if p.Desc {
+		op, dir = "<", "DESC"
+	}
+
+	if p.Cursor != "" {
+		c, err := decodeCursor(p.Cursor)
+		if err != nil {
+			return page, err
+		}
+		if c.Sort != p.Sort || c.Desc != p.Desc {
+			return page, domain.ErrInvalidInput
+		}
+
+		if col == q.idCol {
+			conds = append(conds, q.idCol+" "+op+" ?")
+			args = append(args, c.ID)
+		} else {
+			conds = append(conds, "("+col+" "+op+" ? OR ("+col+" = ? AND "+q.idCol+" "+op+" ?))")
+			args = append(args, c.Value, c.Value, c.ID)
+		}
+	}
+
+	where = ""
+	if len(conds) > 0 {
+		where = " WHERE " + strings.Join(conds, " AND ")
+	}
+
+	order := " ORDER BY " + col + " " + dir
+	if col != q.idCol {
+		order += ", " + q.idCol + " " + dir
+	}
+
+	// Se pide una fila extra para saber si hay otra página
+	args = append(args, p.Limit+1)
+
+	rows, err := db.Query(`SELECT `+q.columns+` FROM `+q.from+where+order+` LIMIT ?`, args...)
+	if err != nil {
+		return page, err
+	}
+	defer rows.Close()
+
+	page.Items = []T{}
+
+	for rows.Next() {
+		item, err := q.scan(rows)
+		if err != nil {
+			return page, err
+		}
+		page.Items = append(page.Items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return page, err
+	}
+
+	if len(page.Items) > p.Limit {
+		page.Items = page.Items[:p.Limit]
+		v, id := q.key(page.Items[p.Limit-1], p.Sort)
+		page.NextCursor = encodeCursor(cursor{Sort: p.Sort, Desc: p.Desc, Value: v, ID: id})
+	}
+
+	return page, nil
+}
+
+// each recorre todas las filas que cumplen los filtros, ordenadas por id,
+// llamando fn por cada una sin juntarlas en memoria (para exportaciones).
+// Si fn devuelve error se corta el recorrido.
+func (q listQuery[T]) each(db *sql.DB, fn func(T) error) error {
+
+	where := ""
+	if len(q.where) > 0 {
+		where = " WHERE " + strings.Join(q.where, " AND ")
+	}
+
+	rows, err := db.Query(`SELECT `+q.columns+` FROM `+q.from+where+` ORDER BY `+q.idCol, q.args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := q.scan(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// likeEscaper escapa los comodines de LIKE para que el texto del filtro se busque literal.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// escapeLike prepara un texto para usarlo en un filtro LIKE ... ESCAPE '\'.
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
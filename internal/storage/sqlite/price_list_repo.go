@@ -0,0 +1,302 @@
+package sqlite
+
+import (
+	"database/sql"
+	"math"
+
+	"ferreteria-inventario-ventas/internal/domain"
+)
+
+// PriceListRepo maneja las operaciones de base de datos para listas de precios.
+type PriceListRepo struct {
+	db *sql.DB
+}
+
+// Constructor del repositorio.
+func NewPriceListRepo(db *sql.DB) *PriceListRepo {
+	return &PriceListRepo{db: db}
+}
+
+// Create inserta la lista con sus reglas en una transacción y registra el alta con audit.
+// l queda como se guardó (con los nombres de productos y categorías de las reglas).
+func (r *PriceListRepo) Create(l *domain.PriceList, audit domain.AuditFunc) error {
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`INSERT INTO price_lists(nombre, descripcion) VALUES(?,?)`, l.Nombre, l.Descripcion)
+	if err != nil {
+		return mapConstraintError(err)
+	}
+	l.ID, _ = result.LastInsertId()
+
+	if err := insertPriceRules(tx, l.ID, l.Reglas); err != nil {
+		return err
+	}
+
+	after, err := getPriceList(tx, l.ID)
+	if err != nil {
+		return err
+	}
+	if err := appendAudit(tx, audit, l.ID, nil, after); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	*l = *after
+	return nil
+}
+
+// insertPriceRules agrega las reglas a una lista y completa sus IDs.
+// Un producto o categoría inexistente es ErrNotFound; una regla repetida, ErrConflict.
+func insertPriceRules(tx *sql.Tx, listID int64, rules []domain.PriceListRule) error {
+	for i := range rules {
+		rule := &rules[i]
+		result, err := tx.Exec(
+			`INSERT INTO price_list_rules(list_id, product_id, category_id, cantidad_min, precio, porcentaje)
+			 VALUES(?,?,?,?,?,?)`,
+			listID, rule.ProductID, rule.CategoryID, rule.CantidadMin, rule.Precio, rule.Porcentaje,
+		)
+		if err != nil {
+			return mapConstraintError(err)
+		}
+		rule.ID, _ = result.LastInsertId()
+	}
+	return nil
+}
+
+// Get devuelve una lista con sus reglas.
+func (r *PriceListRepo) Get(id int64) (*domain.PriceList, error) {
+	return getPriceList(r.db, id)
+}
+
+// getPriceList lee una lista con la base o dentro de una transacción.
+func getPriceList(q queryer, id int64) (*domain.PriceList, error) {
+
+	var l domain.PriceList
+	err := q.QueryRow(
+		`SELECT id, nombre, descripcion, (SELECT COUNT(*) FROM clients c WHERE c.price_list_id = l.id)
+		 FROM price_lists l WHERE id = ?`,
+		id,
+	).Scan(&l.ID, &l.Nombre, &l.Descripcion, &l.Clientes)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	lists := map[int64]*domain.PriceList{l.ID: &l}
+	if err := loadPriceRules(q, lists, `WHERE r.list_id = ?`, id); err != nil {
+		return nil, err
+	}
+
+	return &l, nil
+}
+
+// List devuelve todas las listas con sus reglas, ordenadas por nombre.
+func (r *PriceListRepo) List() ([]domain.PriceList, error) {
+
+	rows, err := r.db.Query(
+		`SELECT id, nombre, descripcion, (SELECT COUNT(*) FROM clients c WHERE c.price_list_id = l.id)
+		 FROM price_lists l ORDER BY nombre`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []domain.PriceList{}
+	for rows.Next() {
+		var l domain.PriceList
+		if err := rows.Scan(&l.ID, &l.Nombre, &l.Descripcion, &l.Clientes); err != nil {
+			return nil, err
+		}
+		list = append(list, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	lists := make(map[int64]*domain.PriceList, len(list))
+	for i := range list {
+		lists[list[i].ID] = &list[i]
+	}
+	if err := loadPriceRules(r.db, lists, ``); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// loadPriceRules carga las reglas que cumplen where en las listas del mapa
+// (por producto, luego por categoría, luego generales; cada grupo por cantidad).
+func loadPriceRules(q queryer, lists map[int64]*domain.PriceList, where string, args ...any) error {
+
+	for _, l := range lists {
+		l.Reglas = []domain.PriceListRule{}
+	}
+
+	rows, err := q.Query(`
+		SELECT r.list_id, r.id, r.product_id, IFNULL(p.nombre, ''), r.category_id, IFNULL(c.nombre, ''),
+			r.cantidad_min, r.precio, r.porcentaje
+		FROM price_list_rules r
+		LEFT JOIN products p ON p.id = r.product_id
+		LEFT JOIN categories c ON c.id = r.category_id
+		`+where+`
+		ORDER BY r.list_id, r.product_id IS NULL, r.category_id IS NULL, p.nombre, c.nombre, r.cantidad_min`,
+		args...,
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var listID int64
+		var rule domain.PriceListRule
+		var productID, categoryID sql.NullInt64
+		var precio, porcentaje sql.NullFloat64
+
+		err := rows.Scan(&listID, &rule.ID, &productID, &rule.Producto, &categoryID, &rule.Categoria,
+			&rule.CantidadMin, &precio, &porcentaje)
+		if err != nil {
+			return err
+		}
+		if productID.Valid {
+			rule.ProductID = &productID.Int64
+		}
+		if categoryID.Valid {
+			rule.CategoryID = &categoryID.Int64
+		}
+		rule.Precio = nullFloat(precio)
+		rule.Porcentaje = nullFloat(porcentaje)
+
+		if l, ok := lists[listID]; ok {
+			l.Reglas = append(l.Reglas, rule)
+		}
+	}
+
+	return rows.Err()
+}
+
+// Update cambia nombre y descripción y reemplaza todas las reglas en una transacción,
+// y registra el cambio con audit. l queda como se guardó.
+func (r *PriceListRepo) Update(id int64, l *domain.PriceList, audit domain.AuditFunc) error {
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := getPriceList(tx, id)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`UPDATE price_lists SET nombre=?, descripcion=? WHERE id=?`, l.Nombre, l.Descripcion, id); err != nil {
+		return mapConstraintError(err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM price_list_rules WHERE list_id = ?`, id); err != nil {
+		return err
+	}
+	if err := insertPriceRules(tx, id, l.Reglas); err != nil {
+		return err
+	}
+
+	after, err := getPriceList(tx, id)
+	if err != nil {
+		return err
+	}
+	if err := appendAudit(tx, audit, id, before, after); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	*l = *after
+	return nil
+}
+
+// Delete elimina una lista que no tenga clientes asignados ni ventas que la usaron
+// y registra con audit cómo era, en la misma transacción.
+func (r *PriceListRepo) Delete(id int64, audit domain.AuditFunc) error {
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := getPriceList(tx, id)
+	if err != nil {
+		return err
+	}
+
+	var inUse int
+	err = tx.QueryRow(
+		`SELECT (SELECT COUNT(*) FROM clients WHERE price_list_id = ?) +
+			(SELECT COUNT(*) FROM sale_items WHERE price_list_id = ?)`,
+		id, id,
+	).Scan(&inUse)
+	if err != nil {
+		return err
+	}
+	if inUse > 0 {
+		return domain.ErrConflict
+	}
+
+	if _, err := tx.Exec(`DELETE FROM price_lists WHERE id=?`, id); err != nil {
+		return err
+	}
+	if err := appendAudit(tx, audit, id, before, nil); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// listPrice busca en la lista la regla que aplica a cantidad unidades del producto
+// y devuelve el precio resultante. Si ninguna aplica devuelve el precio base y false.
+// Gana la regla del producto, luego la de la categoría más cercana (la del producto o
+// un ancestro) y luego la general; dentro de cada una, la escala más alta alcanzada.
+func listPrice(tx *sql.Tx, listID, productID int64, categoryID *int64, cantidad int, base float64) (float64, bool, error) {
+
+	var precio, porcentaje sql.NullFloat64
+	err := tx.QueryRow(`
+		WITH RECURSIVE up(id, depth) AS (
+			SELECT ?, 0
+			UNION ALL
+			SELECT c.parent_id, u.depth + 1 FROM categories c JOIN up u ON c.id = u.id
+			WHERE c.parent_id IS NOT NULL
+		)
+		SELECT r.precio, r.porcentaje
+		FROM price_list_rules r
+		LEFT JOIN up ON up.id = r.category_id
+		WHERE r.list_id = ? AND r.cantidad_min <= ?
+			AND (r.product_id = ? OR up.id IS NOT NULL OR (r.product_id IS NULL AND r.category_id IS NULL))
+		ORDER BY r.product_id IS NULL, r.category_id IS NULL, up.depth, r.cantidad_min DESC
+		LIMIT 1`,
+		categoryID, listID, cantidad, productID,
+	).Scan(&precio, &porcentaje)
+	if err == sql.ErrNoRows {
+		return base, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+
+	if precio.Valid {
+		return precio.Float64, true, nil
+	}
+	// Porcentaje sobre el precio del producto, redondeado a centavos (mínimo un centavo)
+	return math.Max(math.Round(base*(1+porcentaje.Float64/100)*100)/100, 0.01), true, nil
+}
@@ -0,0 +1,511 @@
+package sqlite
+
+import (
+	"database/sql"
+	"time"
+
+	"ferreteria-inventario-ventas/internal/domain"
+)
+
+// PriceRepo maneja el historial de precios y los cambios programados.
+type PriceRepo struct {
+	db *sql.DB
+}
+
+// Constructor del repositorio.
+func NewPriceRepo(db *sql.DB) *PriceRepo {
+	return &PriceRepo{db: db}
+}
+
+// priceChangeColumns son las columnas que se leen de un cambio programado (tabla con alias c).
+const priceChangeColumns = `c.id, c.product_id, IFNULL((SELECT p.nombre FROM products p WHERE p.id = c.product_id), ''),
+	c.precio, c.porcentaje, c.desde, c.estado, c.creado, c.aplicado, c.precio_anterior, c.precio_nuevo`
+
+// scanPriceChange lee una fila con priceChangeColumns.
+func scanPriceChange(row rowScanner) (domain.PriceChange, error) {
+	var c domain.PriceChange
+	var precio, porcentaje, anterior, nuevo sql.NullFloat64
+	var desde, creado string
+	var aplicado sql.NullString
+
+	err := row.Scan(&c.ID, &c.ProductID, &c.Producto, &precio, &porcentaje, &desde, &c.Estado,
+		&creado, &aplicado, &anterior, &nuevo)
+	if err != nil {
+		return c, err
+	}
+
+	c.Desde = parseTime(desde)
+	c.Creado = parseTime(creado)
+	c.Precio = nullFloat(precio)
+	c.Porcentaje = nullFloat(porcentaje)
+	c.PrecioAnterior = nullFloat(anterior)
+	c.PrecioNuevo = nullFloat(nuevo)
+	if aplicado.Valid {
+		t := parseTime(aplicado.String)
+		c.Aplicado = &t
+	}
+	return c, nil
+}
+
+func nullFloat(v sql.NullFloat64) *float64 {
+	if !v.Valid {
+		return nil
+	}
+	return &v.Float64
+}
+
+// insertPrice agrega un precio al historial del producto.
+func insertPrice(tx *sql.Tx, productID int64, precio float64, desde time.Time, origen string, changeID *int64) error {
+	_, err := tx.Exec(
+		`INSERT INTO price_history(product_id, precio, desde, origen, change_id) VALUES(?,?,?,?,?)`,
+		productID, precio, formatTime(desde), origen, changeID,
+	)
+	return err
+}
+
+// History devuelve el historial de precios del producto y sus cambios pendientes.
+func (r *PriceRepo) History(productID int64) (*domain.PriceHistory, error) {
+
+	h := domain.PriceHistory{ProductID: productID, Historial: []domain.PricePoint{}, Programados: []domain.PriceChange{}}
+
+	err := r.db.QueryRow(`SELECT nombre, precio FROM products WHERE id = ?`, productID).Scan(&h.Producto, &h.Precio)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(
+		`SELECT precio, desde, origen, change_id FROM price_history
+		 WHERE product_id = ? ORDER BY desde DESC, id DESC`,
+		productID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		pt, err := scanPricePoint(rows)
+		if err != nil {
+			return nil, err
+		}
+		h.Historial = append(h.Historial, pt)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	pending, err := r.db.Query(
+		`SELECT `+priceChangeColumns+` FROM price_changes c
+		 WHERE c.product_id = ? AND c.estado = ? ORDER BY c.desde, c.id`,
+		productID, domain.PriceChangePending,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer pending.Close()
+
+	for pending.Next() {
+		c, err := scanPriceChange(pending)
+		if err != nil {
+			return nil, err
+		}
+		h.Programados = append(h.Programados, c)
+	}
+
+	return &h, pending.Err()
+}
+
+// scanPricePoint lee precio, desde, origen y change_id del historial.
+func scanPricePoint(row rowScanner) (domain.PricePoint, error) {
+	var pt domain.PricePoint
+	var desde string
+	var changeID sql.NullInt64
+
+	if err := row.Scan(&pt.Precio, &desde, &pt.Origen, &changeID); err != nil {
+		return pt, err
+	}
+	pt.Desde = parseTime(desde)
+	if changeID.Valid {
+		pt.ChangeID = &changeID.Int64
+	}
+	return pt, nil
+}
+
+// PriceAt devuelve el precio que regía en la fecha at: la última entrada del historial
+// con desde <= at. ErrNotFound si el producto no existe o todavía no tenía precio.
+func (r *PriceRepo) PriceAt(productID int64, at time.Time) (*domain.PriceAt, error) {
+
+	pt, err := scanPricePoint(r.db.QueryRow(
+		`SELECT precio, desde, origen, change_id FROM price_history
+		 WHERE product_id = ? AND desde <= ?
+		 ORDER BY desde DESC, id DESC LIMIT 1`,
+		productID, formatTime(at),
+	))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.PriceAt{ProductID: productID, Fecha: at, Precio: pt.Precio, Vigente: pt}, nil
+}
+
+// CreateChange guarda un cambio programado pendiente y registra el alta con audit en la
+// misma transacción. ErrNotFound si el producto no existe.
+func (r *PriceRepo) CreateChange(c *domain.PriceChange, audit domain.AuditFunc) error {
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(`SELECT nombre FROM products WHERE id = ?`, c.ProductID).Scan(&c.Producto)
+	if err == sql.ErrNoRows {
+		return domain.ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	c.Estado = domain.PriceChangePending
+	result, err := tx.Exec(
+		`INSERT INTO price_changes(product_id, precio, porcentaje, desde, estado, creado) VALUES(?,?,?,?,?,?)`,
+		c.ProductID, c.Precio, c.Porcentaje, formatTime(c.Desde), c.Estado, formatTime(c.Creado),
+	)
+	if err != nil {
+		return mapConstraintError(err)
+	}
+
+	c.ID, _ = result.LastInsertId()
+
+	if err := appendAudit(tx, audit, c.ID, nil, c); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// CreateBulk programa el mismo porcentaje para todos los productos de la categoría
+// (con subcategorías) o del proveedor, en una transacción. Devuelve los cambios creados.
+// Si crea alguno, registra con audit el aumento y la cantidad de productos.
+func (r *PriceRepo) CreateBulk(b domain.BulkPriceChange, creado time.Time, audit domain.AuditFunc) ([]domain.PriceChange, error) {
+
+	var cond string
+	var arg any
+	if b.CategoryID > 0 {
+		cond, arg = `p.category_id IN (`+categorySubtreeSQL+`)`, b.CategoryID
+	} else {
+		cond, arg = `p.id IN (
+			SELECT gi.product_id FROM goods_receipt_items gi
+			JOIN goods_receipts g ON g.id = gi.receipt_id
+			WHERE g.proveedor = ? COLLATE NOCASE)`, b.Proveedor
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`SELECT p.id, p.nombre FROM products p WHERE `+cond+` ORDER BY p.id`, arg)
+	if err != nil {
+		return nil, err
+	}
+
+	var list []domain.PriceChange
+	for rows.Next() {
+		c := domain.PriceChange{
+			Porcentaje: &b.Porcentaje,
+			Desde:      b.Desde,
+			Estado:     domain.PriceChangePending,
+			Creado:     creado,
+		}
+		if err := rows.Scan(&c.ProductID, &c.Producto); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		list = append(list, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := range list {
+		c := &list[i]
+		result, err := tx.Exec(
+			`INSERT INTO price_changes(product_id, porcentaje, desde, estado, creado) VALUES(?,?,?,?,?)`,
+			c.ProductID, b.Porcentaje, formatTime(c.Desde), c.Estado, formatTime(creado),
+		)
+		if err != nil {
+			return nil, err
+		}
+		c.ID, _ = result.LastInsertId()
+	}
+
+	if len(list) > 0 {
+		err := appendAudit(tx, audit, 0, nil, struct {
+			domain.BulkPriceChange
+			Productos int `json:"productos"`
+		}{b, len(list)})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return list, tx.Commit()
+}
+
+// GetChange devuelve un cambio programado por ID.
+func (r *PriceRepo) GetChange(id int64) (*domain.PriceChange, error) {
+	return getPriceChange(r.db, id)
+}
+
+// getPriceChange lee un cambio programado con la base o dentro de una transacción.
+func getPriceChange(q queryer, id int64) (*domain.PriceChange, error) {
+
+	c, err := scanPriceChange(q.QueryRow(`SELECT `+priceChangeColumns+` FROM price_changes c WHERE c.id = ?`, id))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// ListChanges devuelve una página de cambios programados.
+// Campos de orden: id, desde.
+func (r *PriceRepo) ListChanges(f domain.PriceChangeFilter, p domain.PageParams) (domain.Page[domain.PriceChange], error) {
+
+	q := listQuery[domain.PriceChange]{
+		columns: priceChangeColumns,
+		from:    `price_changes c`,
+		sorts: map[string]string{
+			"id":    "c.id",
+			"desde": "c.desde",
+		},
+		idCol: "c.id",
+		scan: func(rows *sql.Rows) (domain.PriceChange, error) {
+			return scanPriceChange(rows)
+		},
+		key: func(c domain.PriceChange, sort string) (any, int64) {
+			if sort == "desde" {
+				return formatTime(c.Desde), c.ID
+			}
+			return c.ID, c.ID
+		},
+	}
+
+	if f.ProductID > 0 {
+		q.where = append(q.where, `c.product_id = ?`)
+		q.args = append(q.args, f.ProductID)
+	}
+	if f.Estado != "" {
+		q.where = append(q.where, `c.estado = ?`)
+		q.args = append(q.args, f.Estado)
+	}
+
+	return q.run(r.db, p)
+}
+
+// CancelChange cancela un cambio pendiente y registra el cambio con audit en la misma
+// transacción. ErrNotFound si no existe; ErrConflict si ya se aplicó o se canceló.
+func (r *PriceRepo) CancelChange(id int64, audit domain.AuditFunc) error {
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := getPriceChange(tx, id)
+	if err != nil {
+		return err
+	}
+	if before.Estado != domain.PriceChangePending {
+		return domain.ErrConflict
+	}
+
+	if _, err := tx.Exec(`UPDATE price_changes SET estado = ? WHERE id = ?`, domain.PriceChangeCancelled, id); err != nil {
+		return err
+	}
+
+	after, err := getPriceChange(tx, id)
+	if err != nil {
+		return err
+	}
+	if err := appendAudit(tx, audit, id, before, after); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ApplyDue aplica, en orden de fecha, los cambios pendientes con desde <= now en una
+// transacción: actualiza el precio del producto, lo agrega al historial desde la fecha
+// programada (las ventas ya lo cobran desde entonces, ver duePrice) y marca el cambio
+// como aplicado en now. Cada precio que cambia se registra con audit como una
+// modificación del producto, en la misma transacción.
+func (r *PriceRepo) ApplyDue(now time.Time, audit domain.AuditFunc) ([]domain.AppliedPrice, error) {
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	due, err := dueChanges(tx, now, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []domain.AppliedPrice
+	for _, c := range due {
+
+		var actual float64
+		if err := tx.QueryRow(`SELECT precio FROM products WHERE id = ?`, c.ProductID).Scan(&actual); err != nil {
+			return nil, err
+		}
+		nuevo := c.NewPrice(actual)
+
+		if _, err := tx.Exec(`UPDATE products SET precio = ? WHERE id = ?`, nuevo, c.ProductID); err != nil {
+			return nil, err
+		}
+		if nuevo != actual {
+			if err := insertPrice(tx, c.ProductID, nuevo, changeStart(c), domain.PriceOriginScheduled, &c.ID); err != nil {
+				return nil, err
+			}
+			err := appendAudit(tx, audit, c.ProductID,
+				map[string]float64{"precio": actual}, map[string]float64{"precio": nuevo})
+			if err != nil {
+				return nil, err
+			}
+		}
+		_, err := tx.Exec(
+			`UPDATE price_changes SET estado = ?, aplicado = ?, precio_anterior = ?, precio_nuevo = ? WHERE id = ?`,
+			domain.PriceChangeApplied, formatTime(now), actual, nuevo, c.ID,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		applied = append(applied, domain.AppliedPrice{ChangeID: c.ID, ProductID: c.ProductID, Antes: actual, Despues: nuevo})
+	}
+
+	return applied, tx.Commit()
+}
+
+// dueChanges devuelve los cambios pendientes con desde <= now en orden de fecha,
+// de un producto o de todos (productID 0).
+func dueChanges(q queryer, now time.Time, productID int64) ([]domain.PriceChange, error) {
+
+	rows, err := q.Query(
+		`SELECT `+priceChangeColumns+` FROM price_changes c
+		 WHERE c.estado = ? AND c.desde <= ? AND (? = 0 OR c.product_id = ?)
+		 ORDER BY c.desde, c.id`,
+		domain.PriceChangePending, formatTime(now), productID, productID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var due []domain.PriceChange
+	for rows.Next() {
+		c, err := scanPriceChange(rows)
+		if err != nil {
+			return nil, err
+		}
+		due = append(due, c)
+	}
+
+	return due, rows.Err()
+}
+
+// duePrice devuelve el precio del producto en la fecha at con los cambios programados
+// que ya llegaron a su fecha y el proceso todavía no aplicó, para que una venta no
+// cobre el precio anterior en ese intervalo.
+func duePrice(q queryer, productID int64, precio float64, at time.Time) (float64, error) {
+
+	due, err := dueChanges(q, at, productID)
+	if err != nil {
+		return 0, err
+	}
+	for _, c := range due {
+		precio = c.NewPrice(precio)
+	}
+
+	return precio, nil
+}
+
+// changeStart es la fecha desde la que rige un cambio: la programada, o la de su
+// creación si se programó con una fecha pasada.
+func changeStart(c domain.PriceChange) time.Time {
+	if c.Creado.After(c.Desde) {
+		return c.Creado
+	}
+	return c.Desde
+}
+
+// seedPriceHistory arma el historial de precios la primera vez, con los productos que ya
+// estaban cargados: cada cambio del precio cobrado en sus ventas, y el precio actual
+// desde el alta del producto (o desde ahora si difiere del último precio vendido).
+func seedPriceHistory(db *sql.DB) error {
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO price_history(product_id, precio, desde, origen)
+		SELECT product_id, precio_unitario, fecha, ? FROM (
+			SELECT si.product_id, si.precio_unitario, s.fecha,
+				LAG(si.precio_unitario) OVER (PARTITION BY si.product_id ORDER BY s.fecha, si.id) AS anterior
+			FROM sale_items si
+			JOIN sales s ON s.id = si.sale_id
+			JOIN products p ON p.id = si.product_id
+		)
+		WHERE anterior IS NULL OR anterior <> precio_unitario`,
+		domain.PriceOriginSales,
+	)
+	if err != nil {
+		return err
+	}
+
+	now := formatTime(time.Now())
+
+	_, err = tx.Exec(`
+		INSERT INTO price_history(product_id, precio, desde, origen)
+		SELECT p.id, p.precio, ?, ? FROM products p
+		WHERE p.precio <> (
+			SELECT h.precio FROM price_history h WHERE h.product_id = p.id
+			ORDER BY h.desde DESC, h.id DESC LIMIT 1
+		)`,
+		now, domain.PriceOriginManual,
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO price_history(product_id, precio, desde, origen)
+		SELECT p.id, p.precio, IFNULL((SELECT MIN(m.fecha) FROM stock_movements m WHERE m.product_id = p.id), ?), ?
+		FROM products p
+		WHERE NOT EXISTS (SELECT 1 FROM price_history h WHERE h.product_id = p.id)`,
+		now, domain.PriceOriginInitial,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
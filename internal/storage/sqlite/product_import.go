@@ -0,0 +1,252 @@
+package sqlite
+
+import (
+	"database/sql"
+	"strings"
+
+	"ferreteria-inventario-ventas/internal/domain"
+)
+
+// Import crea o actualiza productos en una sola transacción.
+// Cada fila busca el producto por SKU y, si no lo encuentra, por nombre;
+// los campos que no vienen en la fila conservan el valor actual.
+// validate recibe el producto ya combinado y devuelve el motivo si no es válido.
+// Si hay errores o dryRun es true se deshace todo; el reporte indica lo que se haría.
+// Cada producto creado o actualizado se registra con audit (antes y después) en la
+// misma transacción.
+func (r *ProductRepo) Import(rows []domain.ProductImportRow, dryRun bool, validate func(*domain.Product) error,
+	audit domain.AuditFunc) (domain.ImportReport, error) {
+
+	report := domain.ImportReport{DryRun: dryRun, Filas: len(rows)}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return report, err
+	}
+	defer tx.Rollback()
+
+	categories, err := importCategoryIndex(tx)
+	if err != nil {
+		return report, err
+	}
+	brands, err := importBrandIndex(tx)
+	if err != nil {
+		return report, err
+	}
+
+	for _, row := range rows {
+
+		// Cada fila en su propio savepoint: si falla no deja cambios a medias
+		// que afecten la validación de las siguientes.
+		if _, err := tx.Exec(`SAVEPOINT fila`); err != nil {
+			return report, err
+		}
+
+		created, msg, err := importRow(tx, row, categories, brands, validate, audit)
+		if err != nil {
+			return report, err
+		}
+
+		if msg != "" {
+			report.Errores = append(report.Errores, domain.RowError{Row: row.Row, Error: msg})
+			if _, err := tx.Exec(`ROLLBACK TO fila`); err != nil {
+				return report, err
+			}
+		} else if created {
+			report.Creados++
+		} else {
+			report.Actualizados++
+		}
+
+		if _, err := tx.Exec(`RELEASE fila`); err != nil {
+			return report, err
+		}
+	}
+
+	if dryRun || len(report.Errores) > 0 {
+		return report, nil
+	}
+
+	if err := tx.Commit(); err != nil {
+		return report, err
+	}
+	report.Guardado = true
+
+	return report, nil
+}
+
+// importRow aplica una fila y la audita. Devuelve el motivo si la fila no se puede importar;
+// err solo se usa para fallas de la base de datos.
+func importRow(tx *sql.Tx, row domain.ProductImportRow, categories, brands map[string]int64,
+	validate func(*domain.Product) error, audit domain.AuditFunc) (created bool, msg string, err error) {
+
+	p, found, err := findImportTarget(tx, row)
+	if err != nil {
+		return false, "", err
+	}
+	var before *domain.Product
+	if found {
+		prev := p
+		before = &prev
+	}
+	if !found && row.Nombre == "" {
+		return false, "producto no encontrado y sin nombre para crearlo", nil
+	}
+
+	if row.Nombre != "" {
+		p.Nombre = row.Nombre
+	}
+	if row.SKU != "" {
+		p.SKU = row.SKU
+	}
+	if row.Stock != nil {
+		p.Stock = *row.Stock
+	}
+	if row.Precio != nil {
+		p.Precio = *row.Precio
+	}
+	if row.Costo != nil {
+		p.Costo = *row.Costo
+	}
+
+	// Solo se reemplazan los códigos si la fila los trae
+	p.Barcodes = row.Barcodes
+
+	if row.Categoria != nil {
+		id, ok := categories[importKey(*row.Categoria)]
+		if !ok {
+			return false, "categoría no encontrada o ambigua: " + *row.Categoria, nil
+		}
+		p.CategoryID = &id
+	}
+	if row.Marca != nil {
+		id, ok := brands[importKey(*row.Marca)]
+		if !ok {
+			return false, "marca no encontrada: " + *row.Marca, nil
+		}
+		p.BrandID = &id
+	}
+
+	if err := validate(&p); err != nil {
+		return false, err.Error(), nil
+	}
+
+	if found {
+		err = updateProduct(tx, p.ID, &p)
+	} else {
+		err = createProduct(tx, &p)
+	}
+
+	switch err {
+	case nil:
+		after, err := getProduct(tx, p.ID)
+		if err != nil {
+			return false, "", err
+		}
+		return !found, "", appendAudit(tx, audit, p.ID, before, after)
+	case domain.ErrConflict:
+		return false, "nombre, sku o código de barras repetido", nil
+	case domain.ErrNotFound:
+		return false, "categoría o marca no encontrada", nil
+	}
+	return false, "", err
+}
+
+// findImportTarget busca el producto existente de la fila: primero por SKU y luego por nombre.
+func findImportTarget(tx *sql.Tx, row domain.ProductImportRow) (domain.Product, bool, error) {
+
+	lookups := []struct{ where, value string }{
+		{`p.sku = ?`, row.SKU},
+		{`p.nombre = ?`, row.Nombre},
+	}
+
+	for _, l := range lookups {
+		if l.value == "" {
+			continue
+		}
+		p, err := scanProduct(tx.QueryRow(`SELECT `+productColumns+` FROM products p WHERE `+l.where, l.value))
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return p, false, err
+		}
+		return p, true, nil
+	}
+
+	return domain.Product{}, false, nil
+}
+
+// importCategoryIndex indexa las categorías por ruta completa y por nombre.
+// Un nombre repetido en distintas ramas no se indexa (hay que usar la ruta).
+func importCategoryIndex(tx *sql.Tx) (map[string]int64, error) {
+
+	rows, err := tx.Query(`
+		WITH RECURSIVE tree(id, nombre, ruta) AS (
+			SELECT id, nombre, nombre FROM categories WHERE parent_id IS NULL
+			UNION ALL
+			SELECT c.id, c.nombre, t.ruta || ' > ' || c.nombre
+			FROM categories c JOIN tree t ON c.parent_id = t.id
+		)
+		SELECT id, nombre, ruta FROM tree
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	index := make(map[string]int64)
+	byName := make(map[string][]int64)
+
+	for rows.Next() {
+		var id int64
+		var nombre, ruta string
+		if err := rows.Scan(&id, &nombre, &ruta); err != nil {
+			return nil, err
+		}
+		index[importKey(ruta)] = id
+		byName[importKey(nombre)] = append(byName[importKey(nombre)], id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for name, ids := range byName {
+		if _, isRuta := index[name]; !isRuta && len(ids) == 1 {
+			index[name] = ids[0]
+		}
+	}
+
+	return index, nil
+}
+
+// importBrandIndex indexa las marcas por nombre.
+func importBrandIndex(tx *sql.Tx) (map[string]int64, error) {
+
+	rows, err := tx.Query(`SELECT id, nombre FROM brands`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	index := make(map[string]int64)
+	for rows.Next() {
+		var id int64
+		var nombre string
+		if err := rows.Scan(&id, &nombre); err != nil {
+			return nil, err
+		}
+		index[importKey(nombre)] = id
+	}
+
+	return index, rows.Err()
+}
+
+// importKey normaliza nombres y rutas para compararlos sin mayúsculas ni espacios extra.
+func importKey(s string) string {
+	parts := strings.Split(s, ">")
+	for i, part := range parts {
+		parts[i] = strings.ToLower(strings.Join(strings.Fields(part), " "))
+	}
+	return strings.Join(parts, " > ")
+}
@@ -2,6 +2,8 @@ package sqlite
 
 import (
 	"database/sql"
+	"strings"
+	"time"
 
 	"ferreteria-inventario-ventas/internal/domain"
 )
@@ -16,55 +18,409 @@ func NewProductRepo(db *sql.DB) *ProductRepo {
 	return &ProductRepo{db: db}
 }
 
-// Create inserta un nuevo producto en la base de datos.
-func (r *ProductRepo) Create(p *domain.Product) error {
+// productColumns son las columnas que se leen de un producto (tabla con alias p).
+// Los códigos de barras vienen concatenados con comas.
+const productColumns = `p.id, p.nombre, p.stock, p.precio, p.costo, IFNULL(p.sku, ''),
+	IFNULL((SELECT GROUP_CONCAT(b.code) FROM product_barcodes b WHERE b.product_id = p.id), ''),
+	p.category_id, IFNULL((SELECT c.nombre FROM categories c WHERE c.id = p.category_id), ''),
+	p.brand_id, IFNULL((SELECT m.nombre FROM brands m WHERE m.id = p.brand_id), '')`
 
-	result, err := r.db.Exec(
-		`INSERT INTO products(nombre, stock, precio) VALUES(?,?,?)`,
-		p.Nombre, p.Stock, p.Precio,
-	)
+// rowScanner permite usar el mismo scan con *sql.Row y *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanProduct lee una fila con productColumns.
+func scanProduct(row rowScanner) (domain.Product, error) {
+	var p domain.Product
+	var barcodes string
+
+	err := row.Scan(&p.ID, &p.Nombre, &p.Stock, &p.Precio, &p.Costo, &p.SKU, &barcodes,
+		&p.CategoryID, &p.Categoria, &p.BrandID, &p.Marca)
+	if barcodes != "" {
+		p.Barcodes = strings.Split(barcodes, ",")
+	}
+	return p, err
+}
+
+// Create inserta un nuevo producto en la base de datos junto con sus códigos de barras
+// y registra el alta con audit en la misma transacción.
+// Si no trae SKU, el trigger products_sku_ai lo genera y aquí se lee de vuelta.
+func (r *ProductRepo) Create(p *domain.Product, audit domain.AuditFunc) error {
+
+	tx, err := r.db.Begin()
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
+
+	if err := createProduct(tx, p); err != nil {
+		return err
+	}
+
+	after, err := getProduct(tx, p.ID)
+	if err != nil {
+		return err
+	}
+	if err := appendAudit(tx, audit, p.ID, nil, after); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// createProduct inserta el producto y sus códigos dentro de una transacción.
+// El stock y el costo iniciales quedan registrados como movimiento y el precio en el historial.
+func createProduct(tx *sql.Tx, p *domain.Product) error {
+
+	result, err := tx.Exec(
+		`INSERT INTO products(nombre, stock, precio, costo, sku, category_id, brand_id) VALUES(?,?,?,?,NULLIF(?, ''),?,?)`,
+		p.Nombre, p.Stock, p.Precio, p.Costo, p.SKU, p.CategoryID, p.BrandID,
+	)
+	if err != nil {
+		return mapConstraintError(err)
+	}
 
 	id, _ := result.LastInsertId()
 	p.ID = id
 
+	if err := tx.QueryRow(`SELECT sku FROM products WHERE id = ?`, id).Scan(&p.SKU); err != nil {
+		return err
+	}
+
+	if err := insertMovement(tx, id, domain.MovementInitial, p.Stock, p.Costo, nil); err != nil {
+		return err
+	}
+	if err := insertPrice(tx, id, p.Precio, time.Now(), domain.PriceOriginInitial, nil); err != nil {
+		return err
+	}
+
+	return insertBarcodes(tx, id, p.Barcodes)
+}
+
+// Get devuelve un producto por ID.
+func (r *ProductRepo) Get(id int64) (*domain.Product, error) {
+	return getProduct(r.db, id)
+}
+
+// getProduct lee un producto con la base o dentro de una transacción.
+func getProduct(q queryer, id int64) (*domain.Product, error) {
+
+	p, err := scanProduct(q.QueryRow(`SELECT `+productColumns+` FROM products p WHERE p.id = ?`, id))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+// GetByBarcode devuelve el producto que tiene asignado el código (ya normalizado).
+func (r *ProductRepo) GetByBarcode(code string) (*domain.Product, error) {
+
+	p, err := scanProduct(r.db.QueryRow(
+		`SELECT `+productColumns+`
+		 FROM product_barcodes pb
+		 JOIN products p ON p.id = pb.product_id
+		 WHERE pb.code = ?`,
+		code,
+	))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+// List devuelve una página de productos que cumplen el filtro.
+// Campos de orden: id, nombre, stock, precio, sku.
+func (r *ProductRepo) List(f domain.ProductFilter, p domain.PageParams) (domain.Page[domain.Product], error) {
+	return productListQuery(f).run(r.db, p)
+}
+
+// Each recorre todos los productos que cumplen el filtro (para exportar).
+func (r *ProductRepo) Each(f domain.ProductFilter, fn func(domain.Product) error) error {
+	return productListQuery(f).each(r.db, fn)
+}
+
+// productListQuery arma la consulta de productos con los filtros aplicados.
+func productListQuery(f domain.ProductFilter) listQuery[domain.Product] {
+
+	q := listQuery[domain.Product]{
+		columns: productColumns,
+		from:    `products p`,
+		sorts: map[string]string{
+			"id":     "p.id",
+			"nombre": "p.nombre",
+			"stock":  "p.stock",
+			"precio": "p.precio",
+			"sku":    "p.sku",
+		},
+		idCol: "p.id",
+		scan: func(rows *sql.Rows) (domain.Product, error) {
+			return scanProduct(rows)
+		},
+		key: func(p domain.Product, sort string) (any, int64) {
+			switch sort {
+			case "nombre":
+				return p.Nombre, p.ID
+			case "stock":
+				return p.Stock, p.ID
+			case "precio":
+				return p.Precio, p.ID
+			case "sku":
+				return p.SKU, p.ID
+			}
+			return p.ID, p.ID
+		},
+	}
+
+	if f.Nombre != "" {
+		q.where = append(q.where, `p.nombre LIKE ? ESCAPE '\'`)
+		q.args = append(q.args, "%"+escapeLike(f.Nombre)+"%")
+	}
+	if f.StockLT != nil {
+		q.where = append(q.where, `p.stock < ?`)
+		q.args = append(q.args, *f.StockLT)
+	}
+	if f.PrecioMin != nil {
+		q.where = append(q.where, `p.precio >= ?`)
+		q.args = append(q.args, *f.PrecioMin)
+	}
+	if f.PrecioMax != nil {
+		q.where = append(q.where, `p.precio <= ?`)
+		q.args = append(q.args, *f.PrecioMax)
+	}
+	if f.CategoryID > 0 {
+		q.where = append(q.where, `p.category_id IN (`+categorySubtreeSQL+`)`)
+		q.args = append(q.args, f.CategoryID)
+	}
+	if f.BrandID > 0 {
+		q.where = append(q.where, `p.brand_id = ?`)
+		q.args = append(q.args, f.BrandID)
+	}
+
+	return q
+}
+
+// Update actualiza un producto. Si no trae SKU, costo, categoría o marca conserva los actuales;
+// category_id o brand_id en 0 los quitan. Si trae Barcodes (aunque sea vacío)
+// reemplaza todos sus códigos. El cambio se registra con audit en la misma transacción.
+func (r *ProductRepo) Update(id int64, p *domain.Product, audit domain.AuditFunc) error {
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := getProduct(tx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := updateProduct(tx, id, p); err != nil {
+		return err
+	}
+
+	after, err := getProduct(tx, id)
+	if err != nil {
+		return err
+	}
+	if err := appendAudit(tx, audit, id, before, after); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// updateProduct actualiza el producto y, si corresponde, sus códigos dentro de una transacción.
+// Si cambia el stock o el costo, la diferencia queda registrada como ajuste;
+// si cambia el precio, se agrega al historial.
+func updateProduct(tx *sql.Tx, id int64, p *domain.Product) error {
+
+	var oldStock int
+	var oldCosto, oldPrecio float64
+	err := tx.QueryRow(`SELECT stock, costo, precio FROM products WHERE id = ?`, id).Scan(&oldStock, &oldCosto, &oldPrecio)
+	if err == sql.ErrNoRows {
+		return domain.ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	costo := oldCosto
+	if p.Costo > 0 {
+		costo = p.Costo
+	}
+
+	_, err = tx.Exec(
+		`UPDATE products SET nombre=?, stock=?, precio=?, costo=?, sku=COALESCE(NULLIF(?, ''), sku),
+		 category_id=NULLIF(COALESCE(?, category_id), 0), brand_id=NULLIF(COALESCE(?, brand_id), 0)
+		 WHERE id=?`,
+		p.Nombre, p.Stock, p.Precio, costo, p.SKU, p.CategoryID, p.BrandID, id,
+	)
+	if err != nil {
+		return mapConstraintError(err)
+	}
+	p.Costo = costo
+
+	// Se leen de vuelta la categoría y la marca que quedaron
+	err = tx.QueryRow(`SELECT category_id, brand_id FROM products WHERE id = ?`, id).Scan(&p.CategoryID, &p.BrandID)
+	if err != nil {
+		return err
+	}
+
+	if p.Stock != oldStock || costo != oldCosto {
+		if err := insertMovement(tx, id, domain.MovementAdjustment, p.Stock-oldStock, costo, nil); err != nil {
+			return err
+		}
+	}
+	if p.Precio != oldPrecio {
+		if err := insertPrice(tx, id, p.Precio, time.Now(), domain.PriceOriginManual, nil); err != nil {
+			return err
+		}
+	}
+
+	if p.Barcodes != nil {
+		if _, err := tx.Exec(`DELETE FROM product_barcodes WHERE product_id = ?`, id); err != nil {
+			return err
+		}
+		if err := insertBarcodes(tx, id, p.Barcodes); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
-// List devuelve todos los productos.
-func (r *ProductRepo) List() ([]domain.Product, error) {
+// Delete elimina el producto y registra con audit cómo era, en la misma transacción.
+func (r *ProductRepo) Delete(id int64, audit domain.AuditFunc) error {
 
-	rows, err := r.db.Query(`SELECT id, nombre, stock, precio FROM products ORDER BY id DESC`)
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := getProduct(tx, id)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM products WHERE id=?`, id); err != nil {
+		return err
+	}
+	if err := appendAudit(tx, audit, id, before, nil); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// AssignBarcodes asigna códigos de barras en bloque dentro de una transacción:
+// si alguna fila falla no se asigna ninguna. Devuelve los errores por fila.
+// Reasignar un código al mismo producto no es error; a otro producto sí.
+// Cada producto que recibe códigos se registra con audit (antes y después) en la
+// misma transacción.
+func (r *ProductRepo) AssignBarcodes(list []domain.BarcodeAssignment, audit domain.AuditFunc) ([]domain.RowError, error) {
+
+	tx, err := r.db.Begin()
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
+	defer tx.Rollback()
+
+	var rowErrors []domain.RowError
 
-	var products []domain.Product
+	// Cómo era cada producto antes de su primer código nuevo, en el orden en que aparecen
+	before := make(map[int64]*domain.Product)
+	var changed []int64
 
-	for rows.Next() {
-		var p domain.Product
-		err := rows.Scan(&p.ID, &p.Nombre, &p.Stock, &p.Precio)
+	for i, a := range list {
+
+		productID := a.ProductID
+		if productID == 0 {
+			err := tx.QueryRow(`SELECT id FROM products WHERE sku = ?`, a.SKU).Scan(&productID)
+			if err == sql.ErrNoRows {
+				rowErrors = append(rowErrors, domain.RowError{Row: i + 1, Error: "sku no encontrado"})
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		var owner int64
+		err := tx.QueryRow(`SELECT product_id FROM product_barcodes WHERE code = ?`, a.Barcode).Scan(&owner)
+		switch {
+		case err == sql.ErrNoRows:
+		case err != nil:
+			return nil, err
+		case owner == productID:
+			continue
+		default:
+			rowErrors = append(rowErrors, domain.RowError{Row: i + 1, Error: "código asignado a otro producto"})
+			continue
+		}
+
+		if _, ok := before[productID]; !ok {
+			p, err := getProduct(tx, productID)
+			if err == domain.ErrNotFound {
+				rowErrors = append(rowErrors, domain.RowError{Row: i + 1, Error: "producto no encontrado"})
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			before[productID] = p
+			changed = append(changed, productID)
+		}
+
+		_, err = tx.Exec(`INSERT INTO product_barcodes(code, product_id) VALUES(?,?)`, a.Barcode, productID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(rowErrors) > 0 {
+		return rowErrors, domain.ErrConflict
+	}
+
+	for _, id := range changed {
+		after, err := getProduct(tx, id)
 		if err != nil {
 			return nil, err
 		}
-		products = append(products, p)
+		if err := appendAudit(tx, audit, id, before[id], after); err != nil {
+			return nil, err
+		}
 	}
 
-	return products, nil
+	return nil, tx.Commit()
 }
 
-func (r *ProductRepo) Update(id int64, p *domain.Product) error {
-	_, err := r.db.Exec(
-		`UPDATE products SET nombre=?, stock=?, precio=? WHERE id=?`,
-		p.Nombre, p.Stock, p.Precio, id,
+// insertMovement registra un cambio de stock que no es venta.
+// costo es el costo promedio del producto después del movimiento.
+func insertMovement(tx *sql.Tx, productID int64, tipo string, cantidad int, costo float64, refID *int64) error {
+	_, err := tx.Exec(
+		`INSERT INTO stock_movements(product_id, fecha, tipo, cantidad, costo_unitario, ref_id) VALUES(?,?,?,?,?,?)`,
+		productID, formatTime(time.Now()), tipo, cantidad, costo, refID,
 	)
 	return err
 }
 
-func (r *ProductRepo) Delete(id int64) error {
-	_, err := r.db.Exec(`DELETE FROM products WHERE id=?`, id)
-	return err
+// insertBarcodes agrega códigos (ya normalizados) a un producto.
+func insertBarcodes(tx *sql.Tx, productID int64, codes []string) error {
+	for _, code := range codes {
+		_, err := tx.Exec(`INSERT INTO product_barcodes(code, product_id) VALUES(?,?)`, code, productID)
+		if err != nil {
+			return mapConstraintError(err)
+		}
+	}
+	return nil
 }
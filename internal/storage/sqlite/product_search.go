@@ -0,0 +1,65 @@
+package sqlite
+
+import (
+	"strings"
+	"unicode"
+
+	"ferreteria-inventario-ventas/internal/domain"
+)
+
+// Search busca productos por nombre usando el índice FTS5.
+// Cada palabra se busca como prefijo ("torn" encuentra "tornillo") y
+// los resultados se ordenan por relevancia (bm25).
+func (r *ProductRepo) Search(text string, limit int) ([]domain.Product, error) {
+
+	match := ftsQuery(text)
+	if match == "" {
+		return []domain.Product{}, nil
+	}
+
+	rows, err := r.db.Query(`
+		SELECT `+productColumns+`
+		FROM products_fts f
+		JOIN products p ON p.id = f.rowid
+		WHERE products_fts MATCH ?
+		ORDER BY f.rank, p.nombre
+		LIMIT ?
+	`, match, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	products := []domain.Product{}
+
+	for rows.Next() {
+		p, err := scanProduct(rows)
+		if err != nil {
+			return nil, err
+		}
+		products = append(products, p)
+	}
+
+	return products, rows.Err()
+}
+
+// ftsQuery convierte el texto del usuario en una consulta FTS5 segura.
+// Cada palabra se vuelve una frase con prefijo; los símbolos dentro de una
+// palabra separan tokens contiguos, así "1/2" busca "1" seguido de "2...".
+// Ejemplo: `tornillo autoperforante 1/2` => `"tornillo"* "autoperforante"* "1 2"*`
+func ftsQuery(text string) string {
+
+	var phrases []string
+
+	for _, word := range strings.Fields(text) {
+		tokens := strings.FieldsFunc(word, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		if len(tokens) == 0 {
+			continue
+		}
+		phrases = append(phrases, `"`+strings.Join(tokens, " ")+`"*`)
+	}
+
+	return strings.Join(phrases, " ")
+}
@@ -0,0 +1,347 @@
+package sqlite
+
+import (
+	"database/sql"
+	"time"
+
+	"ferreteria-inventario-ventas/internal/domain"
+)
+
+// PromotionRepo maneja las operaciones de base de datos para promociones.
+type PromotionRepo struct {
+	db *sql.DB
+}
+
+// Constructor del repositorio.
+func NewPromotionRepo(db *sql.DB) *PromotionRepo {
+	return &PromotionRepo{db: db}
+}
+
+// promotionColumns son las columnas que se leen de una promoción.
+const promotionColumns = `id, nombre, tipo, porcentaje, compra, gratis, monto, cantidad_min, monto_min,
+	desde, hasta, activa, acumulable, prioridad`
+
+// scanPromotion lee una fila con promotionColumns.
+func scanPromotion(row rowScanner) (domain.Promotion, error) {
+	var p domain.Promotion
+	var desde string
+	var hasta sql.NullString
+
+	err := row.Scan(&p.ID, &p.Nombre, &p.Tipo, &p.Porcentaje, &p.Compra, &p.Gratis, &p.Monto,
+		&p.CantidadMin, &p.MontoMin, &desde, &hasta, &p.Activa, &p.Acumulable, &p.Prioridad)
+	if err != nil {
+		return p, err
+	}
+
+	p.Desde = parseTime(desde)
+	if hasta.Valid {
+		t := parseTime(hasta.String)
+		p.Hasta = &t
+	}
+	return p, nil
+}
+
+// queryPromotions lee las promociones que cumplen where (las más recientes primero), con sus objetivos.
+func queryPromotions(q queryer, where string, args ...any) ([]domain.Promotion, error) {
+
+	rows, err := q.Query(`SELECT `+promotionColumns+` FROM promotions `+where+`
+		ORDER BY promotions.desde DESC, promotions.id DESC`, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	list := []domain.Promotion{}
+	index := make(map[int64]int)
+	for rows.Next() {
+		p, err := scanPromotion(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		p.Objetivos = []domain.PromotionTarget{}
+		index[p.ID] = len(list)
+		list = append(list, p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil || len(list) == 0 {
+		return list, err
+	}
+
+	targets, err := q.Query(`
+		SELECT t.promotion_id, t.tipo, t.ref_id,
+			IFNULL(CASE t.tipo
+				WHEN 'product' THEN (SELECT nombre FROM products WHERE id = t.ref_id)
+				WHEN 'category' THEN (SELECT nombre FROM categories WHERE id = t.ref_id)
+				WHEN 'brand' THEN (SELECT nombre FROM brands WHERE id = t.ref_id)
+			END, '')
+		FROM promotion_targets t
+		JOIN promotions ON promotions.id = t.promotion_id `+where+`
+		ORDER BY t.promotion_id, t.tipo, t.ref_id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer targets.Close()
+
+	for targets.Next() {
+		var id int64
+		var t domain.PromotionTarget
+		if err := targets.Scan(&id, &t.Tipo, &t.ID, &t.Nombre); err != nil {
+			return nil, err
+		}
+		if i, ok := index[id]; ok {
+			list[i].Objetivos = append(list[i].Objetivos, t)
+		}
+	}
+
+	return list, targets.Err()
+}
+
+// activePromotions devuelve las promociones activas y vigentes en la fecha at.
+func activePromotions(q queryer, at time.Time) ([]domain.Promotion, error) {
+	t := formatTime(at)
+	return queryPromotions(q,
+		`WHERE promotions.activa = 1 AND promotions.desde <= ? AND (promotions.hasta IS NULL OR promotions.hasta > ?)`,
+		t, t)
+}
+
+// Create inserta la promoción con sus objetivos en una transacción y registra el alta
+// con audit. p queda como se guardó (con los nombres de los objetivos).
+// ErrNotFound si algún producto, categoría o marca no existe.
+func (r *PromotionRepo) Create(p *domain.Promotion, audit domain.AuditFunc) error {
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(
+		`INSERT INTO promotions(nombre, tipo, porcentaje, compra, gratis, monto, cantidad_min, monto_min,
+			desde, hasta, activa, acumulable, prioridad)
+		 VALUES(?,?,?,?,?,?,?,?,?,?,?,?,?)`,
+		p.Nombre, p.Tipo, p.Porcentaje, p.Compra, p.Gratis, p.Monto, p.CantidadMin, p.MontoMin,
+		formatTime(p.Desde), optionalTime(p.Hasta), p.Activa, p.Acumulable, p.Prioridad,
+	)
+	if err != nil {
+		return err
+	}
+	p.ID, _ = result.LastInsertId()
+
+	if err := insertPromotionTargets(tx, p.ID, p.Objetivos); err != nil {
+		return err
+	}
+
+	after, err := getPromotion(tx, p.ID)
+	if err != nil {
+		return err
+	}
+	if err := appendAudit(tx, audit, p.ID, nil, after); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	*p = *after
+	return nil
+}
+
+// optionalTime guarda nil como NULL.
+func optionalTime(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return formatTime(*t)
+}
+
+// insertPromotionTargets agrega los objetivos verificando que existan.
+func insertPromotionTargets(tx *sql.Tx, id int64, targets []domain.PromotionTarget) error {
+
+	tables := map[string]string{
+		domain.TargetProduct:  "products",
+		domain.TargetCategory: "categories",
+		domain.TargetBrand:    "brands",
+	}
+
+	for _, t := range targets {
+		var count int
+		if err := tx.QueryRow(`SELECT COUNT(*) FROM `+tables[t.Tipo]+` WHERE id = ?`, t.ID).Scan(&count); err != nil {
+			return err
+		}
+		if count == 0 {
+			return domain.ErrNotFound
+		}
+
+		_, err := tx.Exec(
+			`INSERT OR IGNORE INTO promotion_targets(promotion_id, tipo, ref_id) VALUES(?,?,?)`,
+			id, t.Tipo, t.ID,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Get devuelve una promoción con sus objetivos.
+func (r *PromotionRepo) Get(id int64) (*domain.Promotion, error) {
+	return getPromotion(r.db, id)
+}
+
+// getPromotion lee una promoción con la base o dentro de una transacción.
+func getPromotion(q queryer, id int64) (*domain.Promotion, error) {
+
+	list, err := queryPromotions(q, `WHERE promotions.id = ?`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(list) == 0 {
+		return nil, domain.ErrNotFound
+	}
+
+	return &list[0], nil
+}
+
+// List devuelve las promociones (las más recientes primero); con Vigentes solo las
+// activas que rigen en la fecha now.
+func (r *PromotionRepo) List(f domain.PromotionFilter, now time.Time) ([]domain.Promotion, error) {
+	if f.Vigentes {
+		return activePromotions(r.db, now)
+	}
+	return queryPromotions(r.db, ``)
+}
+
+// Update reemplaza los datos y los objetivos de la promoción en una transacción y
+// registra el cambio con audit. p queda como se guardó.
+// Las ventas ya hechas conservan sus descuentos.
+func (r *PromotionRepo) Update(id int64, p *domain.Promotion, audit domain.AuditFunc) error {
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := getPromotion(tx, id)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(
+		`UPDATE promotions SET nombre=?, tipo=?, porcentaje=?, compra=?, gratis=?, monto=?, cantidad_min=?,
+			monto_min=?, desde=?, hasta=?, activa=?, acumulable=?, prioridad=?
+		 WHERE id=?`,
+		p.Nombre, p.Tipo, p.Porcentaje, p.Compra, p.Gratis, p.Monto, p.CantidadMin, p.MontoMin,
+		formatTime(p.Desde), optionalTime(p.Hasta), p.Activa, p.Acumulable, p.Prioridad, id,
+	)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM promotion_targets WHERE promotion_id = ?`, id); err != nil {
+		return err
+	}
+	if err := insertPromotionTargets(tx, id, p.Objetivos); err != nil {
+		return err
+	}
+
+	after, err := getPromotion(tx, id)
+	if err != nil {
+		return err
+	}
+	if err := appendAudit(tx, audit, id, before, after); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	*p = *after
+	return nil
+}
+
+// Delete elimina una promoción que no se usó en ventas (las usadas se desactivan)
+// y registra con audit cómo era, en la misma transacción.
+func (r *PromotionRepo) Delete(id int64, audit domain.AuditFunc) error {
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := getPromotion(tx, id)
+	if err != nil {
+		return err
+	}
+
+	var inUse int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM sale_promotions WHERE promotion_id = ?`, id).Scan(&inUse); err != nil {
+		return err
+	}
+	if inUse > 0 {
+		return domain.ErrConflict
+	}
+
+	if _, err := tx.Exec(`DELETE FROM promotions WHERE id=?`, id); err != nil {
+		return err
+	}
+	if err := appendAudit(tx, audit, id, before, nil); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// categoryChain devuelve la categoría y sus ancestros (vacío si no tiene categoría).
+func categoryChain(tx *sql.Tx, categoryID *int64) ([]int64, error) {
+
+	if categoryID == nil {
+		return nil, nil
+	}
+
+	rows, err := tx.Query(`
+		WITH RECURSIVE up(id) AS (
+			SELECT ?
+			UNION ALL
+			SELECT c.parent_id FROM categories c JOIN up u ON c.id = u.id WHERE c.parent_id IS NOT NULL
+		)
+		SELECT id FROM up`, *categoryID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var chain []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		chain = append(chain, id)
+	}
+
+	return chain, rows.Err()
+}
+
+// insertSalePromotions guarda los descuentos aplicados en una línea (itemID > 0) o en la venta.
+func insertSalePromotions(tx *sql.Tx, saleID, itemID int64, applied []domain.AppliedPromotion) error {
+
+	var item any
+	if itemID > 0 {
+		item = itemID
+	}
+
+	for _, a := range applied {
+		_, err := tx.Exec(
+			`INSERT INTO sale_promotions(sale_id, sale_item_id, promotion_id, nombre, monto) VALUES(?,?,?,?,?)`,
+			saleID, item, a.PromotionID, a.Nombre, a.Monto,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,177 @@
+package sqlite
+
+import (
+	"database/sql"
+	"time"
+
+	"ferreteria-inventario-ventas/internal/domain"
+)
+
+// ReceiptRepo maneja las recepciones de mercadería.
+type ReceiptRepo struct {
+	db *sql.DB
+}
+
+// Constructor del repositorio.
+func NewReceiptRepo(db *sql.DB) *ReceiptRepo {
+	return &ReceiptRepo{db: db}
+}
+
+// Create registra una recepción en una sola transacción:
+// por cada producto suma el stock, recalcula el costo promedio ponderado
+// y deja el movimiento de stock. Si un producto no existe no se guarda nada.
+// Cada producto se registra con audit (antes y después) en la misma transacción.
+func (r *ReceiptRepo) Create(rc *domain.Receipt, audit domain.AuditFunc) error {
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	rc.Fecha = time.Now().Truncate(time.Second)
+	rc.Total = 0
+	for _, it := range rc.Items {
+		rc.Total += float64(it.Cantidad) * it.CostoUnitario
+	}
+
+	result, err := tx.Exec(
+		`INSERT INTO goods_receipts(fecha, proveedor, nota, total) VALUES(?,?,?,?)`,
+		formatTime(rc.Fecha), rc.Proveedor, rc.Nota, rc.Total,
+	)
+	if err != nil {
+		return err
+	}
+	rc.ID, _ = result.LastInsertId()
+
+	for i := range rc.Items {
+		it := &rc.Items[i]
+
+		before, err := getProduct(tx, it.ProductID)
+		if err != nil {
+			return err
+		}
+		it.Producto = before.Nombre
+		it.CostoAnterior = before.Costo
+
+		it.CostoNuevo = domain.WeightedAverageCost(before.Stock, it.CostoAnterior, it.Cantidad, it.CostoUnitario)
+
+		_, err = tx.Exec(
+			`UPDATE products SET stock = stock + ?, costo = ? WHERE id = ?`,
+			it.Cantidad, it.CostoNuevo, it.ProductID,
+		)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(
+			`INSERT INTO goods_receipt_items(receipt_id, product_id, cantidad, costo_unitario) VALUES(?,?,?,?)`,
+			rc.ID, it.ProductID, it.Cantidad, it.CostoUnitario,
+		)
+		if err != nil {
+			return err
+		}
+
+		if err := insertMovement(tx, it.ProductID, domain.MovementReceipt, it.Cantidad, it.CostoNuevo, &rc.ID); err != nil {
+			return err
+		}
+
+		after, err := getProduct(tx, it.ProductID)
+		if err != nil {
+			return err
+		}
+		if err := appendAudit(tx, audit, it.ProductID, before, after); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Get devuelve una recepción con sus productos.
+func (r *ReceiptRepo) Get(id int64) (*domain.Receipt, error) {
+
+	var rc domain.Receipt
+	var fechaStr string
+
+	err := r.db.QueryRow(
+		`SELECT id, fecha, proveedor, nota, total FROM goods_receipts WHERE id = ?`, id,
+	).Scan(&rc.ID, &fechaStr, &rc.Proveedor, &rc.Nota, &rc.Total)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	rc.Fecha = parseTime(fechaStr)
+
+	rows, err := r.db.Query(
+		`SELECT gi.product_id, p.nombre, gi.cantidad, gi.costo_unitario
+		 FROM goods_receipt_items gi
+		 JOIN products p ON p.id = gi.product_id
+		 WHERE gi.receipt_id = ?
+		 ORDER BY gi.id`,
+		id,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var it domain.ReceiptItem
+		if err := rows.Scan(&it.ProductID, &it.Producto, &it.Cantidad, &it.CostoUnitario); err != nil {
+			return nil, err
+		}
+		rc.Items = append(rc.Items, it)
+	}
+
+	return &rc, rows.Err()
+}
+
+// List devuelve una página de recepciones (sin productos).
+// Campos de orden: id, fecha, total.
+func (r *ReceiptRepo) List(f domain.ReceiptFilter, p domain.PageParams) (domain.Page[domain.Receipt], error) {
+
+	q := listQuery[domain.Receipt]{
+		columns: `id, fecha, proveedor, nota, total`,
+		from:    `goods_receipts`,
+		sorts: map[string]string{
+			"id":    "id",
+			"fecha": "fecha",
+			"total": "total",
+		},
+		idCol: "id",
+		scan: func(rows *sql.Rows) (domain.Receipt, error) {
+			var rc domain.Receipt
+			var fechaStr string
+			err := rows.Scan(&rc.ID, &fechaStr, &rc.Proveedor, &rc.Nota, &rc.Total)
+			rc.Fecha = parseTime(fechaStr)
+			return rc, err
+		},
+		key: func(rc domain.Receipt, sort string) (any, int64) {
+			switch sort {
+			case "fecha":
+				return formatTime(rc.Fecha), rc.ID
+			case "total":
+				return rc.Total, rc.ID
+			}
+			return rc.ID, rc.ID
+		},
+	}
+
+	if f.Proveedor != "" {
+		q.where = append(q.where, `proveedor LIKE ? ESCAPE '\'`)
+		q.args = append(q.args, "%"+escapeLike(f.Proveedor)+"%")
+	}
+	if !f.Desde.IsZero() {
+		q.where = append(q.where, `fecha >= ?`)
+		q.args = append(q.args, formatTime(f.Desde))
+	}
+	if !f.Hasta.IsZero() {
+		q.where = append(q.where, `fecha < ?`)
+		q.args = append(q.args, formatTime(f.Hasta))
+	}
+
+	return q.run(r.db, p)
+}
@@ -2,6 +2,7 @@ package sqlite
 
 import (
 	"database/sql"
+	"math"
 	"time"
 
 	"ferreteria-inventario-ventas/internal/domain"
@@ -17,11 +18,26 @@ func NewSaleRepo(db *sql.DB) *SaleRepo {
 	return &SaleRepo{db: db}
 }
 
-// CreateSaleTx crea una venta completa usando transacción.
-// 1) Inserta la cabecera
-// 2) Inserta los productos vendidos
-// 3) Descuenta el stock
-func (r *SaleRepo) CreateSaleTx(clientID int64, items []domain.SaleItem) (*domain.Sale, error) {
+// CreateSaleTx crea una venta completa usando una sola transacción.
+// 1) Verifica que el cliente exista y lee su lista de precios
+// 2) Verifica existencia, precio vigente y stock de cada producto y toma su costo;
+// si el cliente tiene lista, el precio sale de la regla que aplique a la cantidad
+// y la línea guarda la lista usada
+// 3) Aplica las promociones vigentes (descuentos por línea y sobre la venta)
+// 4) Aplica los descuentos manuales y verifica que el porcentaje no supere el límite del
+// cajero o, si hay, el del supervisor que autoriza (ErrDiscountLimit)
+// 5) Inserta la cabecera con el cajero y quien autorizó, y consume el token de aprobación usado
+// 6) Inserta los productos vendidos, descuenta el stock y guarda qué promoción o descuento
+// manual hizo cada descuento
+// 7) Suma la venta al resumen diario (daily_product_sales)
+// 8) Registra la llave de idempotencia (si viene)
+// 9) Registra la venta con audit
+// Todas las validaciones se hacen dentro de la misma transacción para que
+// un producto no pueda borrarse ni venderse dos veces entre la validación y el insert.
+// Si la llave de idempotencia ya existe devuelve domain.ErrConflict sin crear nada.
+func (r *SaleRepo) CreateSaleTx(in domain.NewSale, idem *domain.IdempotencyKey, audit domain.AuditFunc) (*domain.Sale, error) {
+
+	clientID, items := in.ClientID, in.Items
 
 	tx, err := r.db.Begin()
 	if err != nil {
@@ -29,22 +45,180 @@ func (r *SaleRepo) CreateSaleTx(clientID int64, items []domain.SaleItem) (*domai
 	}
 	defer tx.Rollback()
 
-	fecha := time.Now()
+	// Validar llave de idempotencia (otra petición con la misma llave pudo ganar la carrera)
+	if idem != nil {
+		var count int
+		err := tx.QueryRow(`SELECT COUNT(*) FROM idempotency_keys WHERE key = ?`, idem.Key).Scan(&count)
+		if err != nil {
+			return nil, err
+		}
+		if count > 0 {
+			return nil, domain.ErrConflict
+		}
+	}
+
+	// Validar cliente
+	var clientName, listName string
+	var listID sql.NullInt64
+	err = tx.QueryRow(
+		`SELECT c.nombre, c.price_list_id, IFNULL(l.nombre, '')
+		 FROM clients c LEFT JOIN price_lists l ON l.id = c.price_list_id
+		 WHERE c.id = ?`,
+		clientID,
+	).Scan(&clientName, &listID, &listName)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Se guarda con precisión de segundos, igual que al leerla
+	fecha := time.Now().Truncate(time.Second)
+
+	promos, err := activePromotions(tx, fecha)
+	if err != nil {
+		return nil, err
+	}
 
 	var total float64
+	lines := make([]domain.PromoLine, len(items))
 
-	// Calcular total y subtotales
+	// Validar productos y calcular subtotales con el precio vigente
 	for i := range items {
-		items[i].Subtotal = float64(items[i].Cantidad) * items[i].PrecioUnitario
+		var precio, costo float64
+		var stock int
+		var categoryID, brandID *int64
+
+		err := tx.QueryRow(
+			`SELECT precio, costo, stock, category_id, brand_id FROM products WHERE id = ?`,
+			items[i].ProductID,
+		).Scan(&precio, &costo, &stock, &categoryID, &brandID)
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// Un cambio programado rige desde su fecha aunque el proceso aún no lo haya aplicado
+		if precio, err = duePrice(tx, items[i].ProductID, precio, fecha); err != nil {
+			return nil, err
+		}
+
+		if precio <= 0 {
+			return nil, domain.ErrInvalidInput
+		}
+		if stock < items[i].Cantidad {
+			return nil, domain.ErrInsufficientStock
+		}
+
+		items[i].PriceListID, items[i].ListaPrecios = nil, ""
+		if listID.Valid {
+			listed, ok, err := listPrice(tx, listID.Int64, items[i].ProductID, categoryID, items[i].Cantidad, precio)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				precio = listed
+				items[i].PriceListID = &listID.Int64
+				items[i].ListaPrecios = listName
+			}
+		}
+
+		items[i].PrecioUnitario = precio
+		items[i].CostoUnitario = costo
+		items[i].Subtotal = float64(items[i].Cantidad) * precio
 		total += items[i].Subtotal
+
+		categories, err := categoryChain(tx, categoryID)
+		if err != nil {
+			return nil, err
+		}
+		lines[i] = domain.PromoLine{
+			ProductID:      items[i].ProductID,
+			Categories:     categories,
+			Cantidad:       items[i].Cantidad,
+			PrecioUnitario: precio,
+		}
+		if brandID != nil {
+			lines[i].BrandID = *brandID
+		}
+	}
+
+	// Aplicar promociones: el subtotal de la línea queda bruto y el descuento aparte
+	lineDiscounts, header := domain.ApplyPromotions(promos, lines)
+	var descuentoLineas, descuento float64
+	for i := range items {
+		items[i].Descuento, items[i].Promociones = 0, lineDiscounts[i]
+		for _, a := range lineDiscounts[i] {
+			items[i].Descuento += a.Monto
+		}
+		items[i].Descuento = roundCents(items[i].Descuento)
+		descuentoLineas += items[i].Descuento
+	}
+	descuento = descuentoLineas
+	for _, a := range header {
+		descuento += a.Monto
+	}
+	descuentoLineas, descuento = roundCents(descuentoLineas), roundCents(descuento)
+	total = roundCents(total - descuento)
+
+	// Descuentos manuales: después de las promociones, sobre lo que queda por cobrar
+	manual := make([]*domain.ManualDiscount, len(items))
+	remaining := make([]float64, len(items))
+	for i := range items {
+		manual[i] = items[i].DescuentoManual
+		remaining[i] = roundCents(items[i].Subtotal - items[i].Descuento)
+	}
+	required, err := domain.ApplyManualDiscounts(manual, remaining, in.Descuento, total)
+	if err != nil {
+		return nil, err
+	}
+
+	var autorizadoPor *string
+	if required > in.Auth.Limite {
+		if in.Auth.Supervisor == "" || required > in.Auth.LimiteSupervisor {
+			return nil, domain.ErrDiscountLimit
+		}
+		autorizadoPor = &in.Auth.Supervisor
+	}
+
+	var manualTotal float64
+	for i, d := range manual {
+		if d != nil {
+			items[i].Descuento = roundCents(items[i].Descuento + d.Aplicado)
+			descuentoLineas = roundCents(descuentoLineas + d.Aplicado)
+			manualTotal += d.Aplicado
+		}
+	}
+	if in.Descuento != nil {
+		manualTotal += in.Descuento.Aplicado
+	}
+	descuento = roundCents(descuento + manualTotal)
+	total = roundCents(total - manualTotal)
+
+	// Impuesto sobre el neto: lo cobrado es el neto más el impuesto
+	impuesto := roundCents(total * in.TasaImpuesto / 100)
+	total = roundCents(total + impuesto)
+
+	var cajero *string
+	if in.Auth.Cajero != "" {
+		cajero = &in.Auth.Cajero
 	}
 
 	// Insertar cabecera de venta
 	result, err := tx.Exec(
-		`INSERT INTO sales(client_id, fecha, total) VALUES(?,?,?)`,
+		`INSERT INTO sales(client_id, fecha, total, descuento, descuento_lineas, impuesto, cajero, autorizado_por)
+		 VALUES(?,?,?,?,?,?,?,?)`,
 		clientID,
-		fecha.Format(time.RFC3339),
+		formatTime(fecha),
 		total,
+		descuento,
+		descuentoLineas,
+		impuesto,
+		cajero,
+		autorizadoPor,
 	)
 	if err != nil {
 		return nil, err
@@ -52,6 +226,20 @@ func (r *SaleRepo) CreateSaleTx(clientID int64, items []domain.SaleItem) (*domai
 
 	saleID, _ := result.LastInsertId()
 
+	// El token de aprobación se usa una sola vez (otra venta pudo usarlo entre la validación y acá)
+	if autorizadoPor != nil && in.Auth.ApprovalID > 0 {
+		res, err := tx.Exec(
+			`UPDATE discount_approvals SET sale_id = ? WHERE id = ? AND sale_id IS NULL`,
+			saleID, in.Auth.ApprovalID,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return nil, domain.ErrBadCredentials
+		}
+	}
+
 	// Insertar detalle y descontar stock
 	for _, item := range items {
 
@@ -74,70 +262,218 @@ func (r *SaleRepo) CreateSaleTx(clientID int64, items []domain.SaleItem) (*domai
 		}
 
 		// Insertar detalle
-		_, err = tx.Exec(
-			`INSERT INTO sale_items(sale_id, product_id, cantidad, precio_unitario, subtotal)
-			 VALUES(?,?,?,?,?)`,
+		res, err = tx.Exec(
+			`INSERT INTO sale_items(sale_id, product_id, cantidad, precio_unitario, subtotal, costo_unitario, price_list_id, descuento)
+			 VALUES(?,?,?,?,?,?,?,?)`,
 			saleID,
 			item.ProductID,
 			item.Cantidad,
 			item.PrecioUnitario,
 			item.Subtotal,
+			item.CostoUnitario,
+			item.PriceListID,
+			item.Descuento,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		itemID, _ := res.LastInsertId()
+		if err := insertSalePromotions(tx, saleID, itemID, item.Promociones); err != nil {
+			return nil, err
+		}
+		if err := insertSaleDiscount(tx, saleID, itemID, item.DescuentoManual); err != nil {
+			return nil, err
+		}
+	}
+
+	// Descuentos sobre la venta
+	if err := insertSalePromotions(tx, saleID, 0, header); err != nil {
+		return nil, err
+	}
+	if err := insertSaleDiscount(tx, saleID, 0, in.Descuento); err != nil {
+		return nil, err
+	}
+
+	// Sumar al resumen diario por producto
+	if err := addDailySales(tx, saleID, localDay(fecha)); err != nil {
+		return nil, err
+	}
+
+	// Registrar llave de idempotencia junto con la venta
+	if idem != nil {
+		_, err = tx.Exec(
+			`INSERT INTO idempotency_keys(key, fingerprint, sale_id, created_at) VALUES(?,?,?,?)`,
+			idem.Key,
+			idem.Fingerprint,
+			saleID,
+			formatTime(fecha),
 		)
 		if err != nil {
 			return nil, err
 		}
 	}
 
+	sale := &domain.Sale{
+		ID:          saleID,
+		ClientID:    clientID,
+		ClientName:  clientName,
+		Fecha:       fecha,
+		Total:       total,
+		Descuento:   descuento,
+		Impuesto:    impuesto,
+		Items:       items,
+		Promociones: header,
+
+		DescuentoManual: in.Descuento,
+		Cajero:          in.Auth.Cajero,
+		AutorizadoPor:   stringValue(autorizadoPor),
+	}
+
+	if err := appendAudit(tx, audit, saleID, nil, sale); err != nil {
+		return nil, err
+	}
+
 	err = tx.Commit()
 	if err != nil {
 		return nil, err
 	}
 
-	return &domain.Sale{
-		ID:       saleID,
-		ClientID: clientID,
-		Fecha:    fecha,
-		Total:    total,
-		Items:    items,
-	}, nil
+	return sale, nil
 }
 
-// ListSales devuelve todas las ventas registradas.
-// ListSales devuelve todas las ventas registradas.
-func (r *SaleRepo) ListSales() ([]domain.Sale, error) {
+// insertSaleDiscount guarda un descuento manual de una línea (itemID > 0) o de la venta.
+func insertSaleDiscount(tx *sql.Tx, saleID, itemID int64, d *domain.ManualDiscount) error {
 
-	rows, err := r.db.Query(`
-		SELECT s.id, s.client_id, c.nombre, s.fecha, s.total
-		FROM sales s
-		JOIN clients c ON c.id = s.client_id
-		ORDER BY s.id DESC
-	`)
-	if err != nil {
-		return nil, err
+	if d == nil {
+		return nil
 	}
-	defer rows.Close()
 
-	var sales []domain.Sale
+	var item, pct any
+	if itemID > 0 {
+		item = itemID
+	}
+	if d.Porcentaje > 0 {
+		pct = d.Porcentaje
+	}
 
-	for rows.Next() {
-		var s domain.Sale
-		var fechaStr string
+	_, err := tx.Exec(
+		`INSERT INTO sale_discounts(sale_id, sale_item_id, porcentaje, monto, motivo) VALUES(?,?,?,?,?)`,
+		saleID, item, pct, d.Aplicado, d.Motivo,
+	)
+	return err
+}
 
-		if err := rows.Scan(&s.ID, &s.ClientID, &s.ClientName, &fechaStr, &s.Total); err != nil {
-			return nil, err
-		}
+// stringValue devuelve "" para nil.
+func stringValue(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return *v
+}
 
-		if t, e := time.Parse(time.RFC3339, fechaStr); e == nil {
-			s.Fecha = t
-		}
+// roundCents redondea a centavos (los descuentos se suman en float).
+func roundCents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// ListSales devuelve una página de ventas (cabecera) que cumplen el filtro.
+// Campos de orden: id, fecha, total.
+func (r *SaleRepo) ListSales(f domain.SaleFilter, p domain.PageParams) (domain.Page[domain.Sale], error) {
+	return saleListQuery(f).run(r.db, p)
+}
 
-		sales = append(sales, s)
+// EachSale recorre todas las cabeceras de venta que cumplen el filtro (para exportar).
+func (r *SaleRepo) EachSale(f domain.SaleFilter, fn func(domain.Sale) error) error {
+	return saleListQuery(f).each(r.db, fn)
+}
+
+// EachSaleLine recorre las líneas de las ventas que cumplen el filtro,
+// con los datos de la venta y del producto (para exportar).
+func (r *SaleRepo) EachSaleLine(f domain.SaleFilter, fn func(domain.SaleLine) error) error {
+
+	q := listQuery[domain.SaleLine]{
+		columns: `si.id, s.id, s.fecha, s.client_id, c.nombre, si.product_id, p.nombre, IFNULL(p.sku, ''),
+			si.cantidad, si.precio_unitario, si.subtotal, si.descuento`,
+		from: `sale_items si
+			JOIN sales s ON s.id = si.sale_id
+			JOIN clients c ON c.id = s.client_id
+			JOIN products p ON p.id = si.product_id`,
+		idCol: "si.id",
+		scan: func(rows *sql.Rows) (domain.SaleLine, error) {
+			var l domain.SaleLine
+			var fechaStr string
+
+			err := rows.Scan(&l.ID, &l.SaleID, &fechaStr, &l.ClientID, &l.ClientName, &l.ProductID,
+				&l.Producto, &l.SKU, &l.Cantidad, &l.PrecioUnitario, &l.Subtotal, &l.Descuento)
+			l.Fecha = parseTime(fechaStr)
+			return l, err
+		},
 	}
+	q.where, q.args = saleFilterConds(f)
 
-	return sales, rows.Err()
+	return q.each(r.db, fn)
 }
 
-// GetSaleDetail devuelve una venta con sus items.
+// saleListQuery arma la consulta de cabeceras de venta con los filtros aplicados.
+func saleListQuery(f domain.SaleFilter) listQuery[domain.Sale] {
+
+	q := listQuery[domain.Sale]{
+		columns: `s.id, s.client_id, c.nombre, s.fecha, s.total, s.descuento, IFNULL(s.cajero, '')`,
+		from:    `sales s JOIN clients c ON c.id = s.client_id`,
+		sorts: map[string]string{
+			"id":    "s.id",
+			"fecha": "s.fecha",
+			"total": "s.total",
+		},
+		idCol: "s.id",
+		scan: func(rows *sql.Rows) (domain.Sale, error) {
+			var s domain.Sale
+			var fechaStr string
+
+			if err := rows.Scan(&s.ID, &s.ClientID, &s.ClientName, &fechaStr, &s.Total, &s.Descuento, &s.Cajero); err != nil {
+				return s, err
+			}
+
+			s.Fecha = parseTime(fechaStr)
+			return s, nil
+		},
+		key: func(s domain.Sale, sort string) (any, int64) {
+			switch sort {
+			case "fecha":
+				return formatTime(s.Fecha), s.ID
+			case "total":
+				return s.Total, s.ID
+			}
+			return s.ID, s.ID
+		},
+	}
+
+	q.where, q.args = saleFilterConds(f)
+
+	return q
+}
+
+// saleFilterConds devuelve las condiciones del filtro de ventas (tabla con alias s).
+func saleFilterConds(f domain.SaleFilter) (where []string, args []any) {
+
+	if f.ClientID > 0 {
+		where = append(where, `s.client_id = ?`)
+		args = append(args, f.ClientID)
+	}
+	if !f.Desde.IsZero() {
+		where = append(where, `s.fecha >= ?`)
+		args = append(args, formatTime(f.Desde))
+	}
+	if !f.Hasta.IsZero() {
+		where = append(where, `s.fecha < ?`)
+		args = append(args, formatTime(f.Hasta))
+	}
+
+	return where, args
+}
+
+// GetSaleDetail devuelve una venta con sus items, las promociones aplicadas y los descuentos manuales.
 func (r *SaleRepo) GetSaleDetail(saleID int64) (*domain.Sale, error) {
 
 	// 1) Cabecera
@@ -145,9 +481,13 @@ func (r *SaleRepo) GetSaleDetail(saleID int64) (*domain.Sale, error) {
 	var fechaStr string
 
 	err := r.db.QueryRow(
-		`SELECT id, client_id, fecha, total FROM sales WHERE id = ?`,
+		`SELECT s.id, s.client_id, c.nombre, s.fecha, s.total, s.descuento, s.impuesto,
+			IFNULL(s.cajero, ''), IFNULL(s.autorizado_por, '')
+		 FROM sales s
+		 JOIN clients c ON c.id = s.client_id
+		 WHERE s.id = ?`,
 		saleID,
-	).Scan(&s.ID, &s.ClientID, &fechaStr, &s.Total)
+	).Scan(&s.ID, &s.ClientID, &s.ClientName, &fechaStr, &s.Total, &s.Descuento, &s.Impuesto, &s.Cajero, &s.AutorizadoPor)
 
 	if err == sql.ErrNoRows {
 		return nil, domain.ErrNotFound
@@ -156,17 +496,17 @@ func (r *SaleRepo) GetSaleDetail(saleID int64) (*domain.Sale, error) {
 		return nil, err
 	}
 
-	// Intentar parsear fecha (si falla, no rompe)
-	if t, e := time.Parse(time.RFC3339, fechaStr); e == nil {
-		s.Fecha = t
-	}
+	// Si la fecha no se puede leer queda en cero (no rompe el detalle)
+	s.Fecha = parseTime(fechaStr)
 
 	// 2) Items
 	rows, err := r.db.Query(
-		`SELECT product_id, cantidad, precio_unitario, subtotal
-		 FROM sale_items
-		 WHERE sale_id = ?
-		 ORDER BY id ASC`,
+		`SELECT si.id, si.product_id, si.cantidad, si.precio_unitario, si.subtotal, si.costo_unitario,
+			si.price_list_id, IFNULL(l.nombre, ''), si.descuento
+		 FROM sale_items si
+		 LEFT JOIN price_lists l ON l.id = si.price_list_id
+		 WHERE si.sale_id = ?
+		 ORDER BY si.id ASC`,
 		saleID,
 	)
 	if err != nil {
@@ -174,84 +514,97 @@ func (r *SaleRepo) GetSaleDetail(saleID int64) (*domain.Sale, error) {
 	}
 	defer rows.Close()
 
+	lineIndex := make(map[int64]int)
 	for rows.Next() {
+		var itemID int64
 		var it domain.SaleItem
-		if err := rows.Scan(&it.ProductID, &it.Cantidad, &it.PrecioUnitario, &it.Subtotal); err != nil {
+		err := rows.Scan(&itemID, &it.ProductID, &it.Cantidad, &it.PrecioUnitario, &it.Subtotal, &it.CostoUnitario,
+			&it.PriceListID, &it.ListaPrecios, &it.Descuento)
+		if err != nil {
 			return nil, err
 		}
+		lineIndex[itemID] = len(s.Items)
 		s.Items = append(s.Items, it)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
-	return &s, rows.Err()
-}
-
-func (r *SaleRepo) ClientExists(id int64) (bool, error) {
-	var count int
-	err := r.db.QueryRow(`SELECT COUNT(*) FROM clients WHERE id = ?`, id).Scan(&count)
-	return count > 0, err
-}
-
-func (r *SaleRepo) ProductExists(id int64) (bool, error) {
-	var count int
-	err := r.db.QueryRow(`SELECT COUNT(*) FROM products WHERE id = ?`, id).Scan(&count)
-	return count > 0, err
-}
-
-// VentasHoy devuelve total de ventas y monto del día actual.
-func (r *SaleRepo) VentasHoy() (int, float64, error) {
-
-	rows, err := r.db.Query(`
-		SELECT COUNT(*), IFNULL(SUM(total),0)
-		FROM sales
-		WHERE DATE(fecha) = DATE('now')
-	`)
+	// 3) Promociones (por línea o sobre la venta)
+	promos, err := r.db.Query(
+		`SELECT sale_item_id, promotion_id, nombre, monto FROM sale_promotions WHERE sale_id = ? ORDER BY id`,
+		saleID,
+	)
 	if err != nil {
-		return 0, 0, err
+		return nil, err
 	}
-	defer rows.Close()
-
-	var count int
-	var total float64
+	defer promos.Close()
 
-	if rows.Next() {
-		if err := rows.Scan(&count, &total); err != nil {
-			return 0, 0, err
+	for promos.Next() {
+		var itemID sql.NullInt64
+		var a domain.AppliedPromotion
+		if err := promos.Scan(&itemID, &a.PromotionID, &a.Nombre, &a.Monto); err != nil {
+			return nil, err
+		}
+		if i, ok := lineIndex[itemID.Int64]; ok && itemID.Valid {
+			s.Items[i].Promociones = append(s.Items[i].Promociones, a)
+		} else {
+			s.Promociones = append(s.Promociones, a)
 		}
 	}
 
-	return count, total, nil
-}
+	if err := promos.Err(); err != nil {
+		return nil, err
+	}
 
-// TopProductos devuelve productos más vendidos.
-func (r *SaleRepo) TopProductos() ([]map[string]interface{}, error) {
-
-	rows, err := r.db.Query(`
-		SELECT p.nombre, SUM(si.cantidad) as total_vendido
-		FROM sale_items si
-		JOIN products p ON p.id = si.product_id
-		GROUP BY p.nombre
-		ORDER BY total_vendido DESC
-		LIMIT 5
-	`)
+	// 4) Descuentos manuales (por línea o sobre la venta)
+	discounts, err := r.db.Query(
+		`SELECT sale_item_id, IFNULL(porcentaje, 0), monto, motivo FROM sale_discounts WHERE sale_id = ? ORDER BY id`,
+		saleID,
+	)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
+	defer discounts.Close()
 
-	var result []map[string]interface{}
-
-	for rows.Next() {
-		var nombre string
-		var total int
-		if err := rows.Scan(&nombre, &total); err != nil {
+	for discounts.Next() {
+		var itemID sql.NullInt64
+		d := &domain.ManualDiscount{}
+		if err := discounts.Scan(&itemID, &d.Porcentaje, &d.Aplicado, &d.Motivo); err != nil {
 			return nil, err
 		}
+		if d.Porcentaje == 0 {
+			d.Monto = d.Aplicado
+		}
+		if i, ok := lineIndex[itemID.Int64]; ok && itemID.Valid {
+			s.Items[i].DescuentoManual = d
+		} else {
+			s.DescuentoManual = d
+		}
+	}
+
+	return &s, discounts.Err()
+}
 
-		result = append(result, map[string]interface{}{
-			"producto": nombre,
-			"cantidad": total,
-		})
+// FindIdempotencyKey busca una llave de idempotencia registrada.
+func (r *SaleRepo) FindIdempotencyKey(key string) (*domain.IdempotencyKey, error) {
+
+	var k domain.IdempotencyKey
+	var createdStr string
+
+	err := r.db.QueryRow(
+		`SELECT key, fingerprint, sale_id, created_at FROM idempotency_keys WHERE key = ?`,
+		key,
+	).Scan(&k.Key, &k.Fingerprint, &k.SaleID, &createdStr)
+
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound
 	}
+	if err != nil {
+		return nil, err
+	}
+
+	k.CreatedAt = parseTime(createdStr)
 
-	return result, nil
+	return &k, nil
 }
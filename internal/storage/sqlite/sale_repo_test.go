@@ -0,0 +1,149 @@
+package sqlite
+
+import (
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"ferreteria-inventario-ventas/internal/domain"
+)
+
+// newTestDB abre una base nueva en un archivo temporal con el esquema aplicado.
+func newTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := OpenDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if err := Migrate(db, filepath.Join("..", "..", "..", "migrations", "schema.sql")); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// seedSale crea un cliente y un producto con el stock indicado y devuelve sus IDs.
+func seedSale(t *testing.T, db *sql.DB, stock int) (clientID, productID int64) {
+	t.Helper()
+
+	c := domain.Client{Nombre: "Cliente", Cedula: "0102030405", Email: "cliente@test.com"}
+	if err := NewClientRepo(db).Create(&c, nil); err != nil {
+		t.Fatal(err)
+	}
+	p := domain.Product{Nombre: "Martillo", Stock: stock, Precio: 12.5, Costo: 8}
+	if err := NewProductRepo(db).Create(&p, nil); err != nil {
+		t.Fatal(err)
+	}
+	return c.ID, p.ID
+}
+
+// Ventas en paralelo sobre poco stock: se venden exactamente las unidades que hay,
+// el resto falla con ErrInsufficientStock y el stock nunca queda negativo.
+func TestCreateSaleTxConcurrentNeverOversells(t *testing.T) {
+
+	const (
+		stock   = 10
+		workers = 40
+	)
+
+	db := newTestDB(t)
+	clientID, productID := seedSale(t, db, stock)
+	repo := NewSaleRepo(db)
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		successes int
+		failures  []error
+	)
+
+	start := make(chan struct{})
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+
+			_, err := repo.CreateSaleTx(domain.NewSale{
+				ClientID: clientID,
+				Items:    []domain.SaleItem{{ProductID: productID, Cantidad: 1}},
+			}, nil, nil)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err == nil {
+				successes++
+			} else {
+				failures = append(failures, err)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	if successes != stock {
+		t.Errorf("ventas exitosas = %d, se esperaban %d", successes, stock)
+	}
+	for _, err := range failures {
+		if !errors.Is(err, domain.ErrInsufficientStock) {
+			t.Errorf("error inesperado: %v", err)
+		}
+	}
+
+	var final int
+	if err := db.QueryRow(`SELECT stock FROM products WHERE id = ?`, productID).Scan(&final); err != nil {
+		t.Fatal(err)
+	}
+	if final != 0 {
+		t.Errorf("stock final = %d, se esperaba 0", final)
+	}
+
+	var negatives int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM products WHERE stock < 0`).Scan(&negatives); err != nil {
+		t.Fatal(err)
+	}
+	if negatives != 0 {
+		t.Errorf("%d productos con stock negativo", negatives)
+	}
+
+	var sold int
+	if err := db.QueryRow(`SELECT IFNULL(SUM(cantidad), 0) FROM sale_items WHERE product_id = ?`, productID).Scan(&sold); err != nil {
+		t.Fatal(err)
+	}
+	if sold != stock {
+		t.Errorf("unidades vendidas = %d, se esperaban %d", sold, stock)
+	}
+}
+
+// El impuesto se calcula sobre el neto, se suma al total y sale en el resumen de ventas.
+func TestCreateSaleTxChargesTax(t *testing.T) {
+
+	db := newTestDB(t)
+	clientID, productID := seedSale(t, db, 5)
+	repo := NewSaleRepo(db)
+
+	sale, err := repo.CreateSaleTx(domain.NewSale{
+		ClientID:     clientID,
+		Items:        []domain.SaleItem{{ProductID: productID, Cantidad: 2}},
+		TasaImpuesto: 15,
+	}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sale.Impuesto != 3.75 || sale.Total != 28.75 {
+		t.Errorf("impuesto = %v, total = %v; se esperaban 3.75 y 28.75", sale.Impuesto, sale.Total)
+	}
+
+	totals, err := repo.SalesTotals([]domain.TimeRange{{Desde: sale.Fecha.Add(-time.Hour), Hasta: sale.Fecha.Add(time.Hour)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := totals[0]; got.Bruto != 25 || got.Impuestos != 3.75 || got.Total != 28.75 {
+		t.Errorf("resumen: bruto %v, impuestos %v, total %v; se esperaban 25, 3.75 y 28.75", got.Bruto, got.Impuestos, got.Total)
+	}
+}
@@ -0,0 +1,498 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"ferreteria-inventario-ventas/internal/domain"
+)
+
+// VentasPorGrupo devuelve unidades y monto vendidos por categoría o por marca,
+// ordenados por monto. En categorías cada fila incluye las ventas de sus subcategorías.
+// Lee del resumen diario si está al día (tiene las mismas columnas cantidad y subtotal).
+func (r *SaleRepo) VentasPorGrupo(groupBy string) ([]domain.GroupSales, error) {
+
+	_, _, daily, err := r.dailyRange(context.Background(), time.Time{}, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	source := "sale_items"
+	if daily {
+		source = "daily_product_sales"
+	}
+
+	var query string
+
+	switch groupBy {
+	case domain.GroupByCategory:
+		query = `
+			WITH RECURSIVE ` + categoryAncestorsSQL + `
+			SELECT cat.id, cat.nombre, cat.parent_id, SUM(si.cantidad), SUM(si.subtotal)
+			FROM ` + source + ` si
+			JOIN products p ON p.id = si.product_id
+			JOIN ancestors a ON a.category_id = p.category_id
+			JOIN categories cat ON cat.id = a.ancestor_id
+			GROUP BY cat.id
+			ORDER BY 5 DESC`
+	case domain.GroupByBrand:
+		query = `
+			SELECT b.id, b.nombre, NULL, SUM(si.cantidad), SUM(si.subtotal)
+			FROM ` + source + ` si
+			JOIN products p ON p.id = si.product_id
+			JOIN brands b ON b.id = p.brand_id
+			GROUP BY b.id
+			ORDER BY 5 DESC`
+	default:
+		return nil, domain.ErrInvalidInput
+	}
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []domain.GroupSales{}
+
+	for rows.Next() {
+		var g domain.GroupSales
+		if err := rows.Scan(&g.ID, &g.Nombre, &g.ParentID, &g.Cantidad, &g.Total); err != nil {
+			return nil, err
+		}
+		result = append(result, g)
+	}
+
+	return result, rows.Err()
+}
+
+// SalesTotals devuelve los totales de ventas de cada rango, en el mismo orden.
+// Los rangos sin ventas vienen en cero. Los rangos se pasan como una tabla
+// VALUES y se cruzan con las ventas en una sola consulta.
+func (r *SaleRepo) SalesTotals(ranges []domain.TimeRange) ([]domain.SalesTotals, error) {
+	return r.SalesTotalsContext(context.Background(), ranges)
+}
+
+// SalesTotalsContext es SalesTotals con un contexto que puede cancelar la consulta.
+func (r *SaleRepo) SalesTotalsContext(ctx context.Context, ranges []domain.TimeRange) ([]domain.SalesTotals, error) {
+
+	if len(ranges) == 0 {
+		return nil, nil
+	}
+
+	values := make([]string, len(ranges))
+	args := make([]any, 0, len(ranges)*3+2)
+	for i, tr := range ranges {
+		values[i] = "(?,?,?)"
+		args = append(args, i, formatTime(tr.Desde), formatTime(tr.Hasta))
+	}
+	// Límites generales para filtrar las ventas una sola vez
+	args = append(args, formatTime(ranges[0].Desde), formatTime(ranges[len(ranges)-1].Hasta))
+
+	rows, err := r.db.QueryContext(ctx, `
+		WITH b(i, desde, hasta) AS (VALUES `+strings.Join(values, ",")+`),
+		s AS (
+			SELECT fecha AS f, total, descuento, impuesto,
+			       (SELECT IFNULL(SUM(si.cantidad * si.costo_unitario), 0)
+			        FROM sale_items si WHERE si.sale_id = sales.id) AS costo
+			FROM sales
+			WHERE fecha >= ? AND fecha < ?
+		)
+		SELECT b.i, COUNT(s.f),
+		       IFNULL(SUM(s.total + s.descuento - s.impuesto), 0),
+		       IFNULL(SUM(s.descuento), 0),
+		       IFNULL(SUM(s.impuesto), 0),
+		       IFNULL(SUM(s.total), 0),
+		       IFNULL(SUM(s.costo), 0)
+		FROM b LEFT JOIN s ON s.f >= b.desde AND s.f < b.hasta
+		GROUP BY b.i
+		ORDER BY b.i`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]domain.SalesTotals, len(ranges))
+
+	for rows.Next() {
+		var i int
+		var t domain.SalesTotals
+		if err := rows.Scan(&i, &t.Ventas, &t.Bruto, &t.Descuentos, &t.Impuestos, &t.Total, &t.Costo); err != nil {
+			return nil, err
+		}
+		result[i] = t
+	}
+
+	return result, rows.Err()
+}
+
+// lineNetSQL es el ingreso neto de una línea de venta (alias si y s): el subtotal
+// menos su descuento y menos la parte proporcional del descuento de la cabecera, sin impuestos.
+// La cabecera se reparte según lo que cada línea cobra después de su propio descuento.
+const lineNetSQL = `IFNULL((si.subtotal - si.descuento) * (s.total - s.impuesto) /
+	NULLIF(s.total + s.descuento - s.descuento_lineas - s.impuesto, 0), si.subtotal - si.descuento)`
+
+// lineCostSQL es el costo de una línea de venta (alias si).
+const lineCostSQL = `si.cantidad * si.costo_unitario`
+
+// topProductMetrics relaciona cada métrica con la columna de orden.
+var topProductMetrics = map[string]string{
+	domain.MetricUnits:   "cantidad",
+	domain.MetricRevenue: "total",
+	domain.MetricMargin:  "utilidad",
+}
+
+// TopProductos devuelve el ranking de productos según la métrica pedida,
+// agrupado por ID de producto (un producto renombrado sigue siendo uno solo).
+// En orden ascendente o con All se incluyen los productos sin ventas en el rango.
+// Si el rango son días completos y el resumen diario lo cubre, se lee de ahí.
+func (r *SaleRepo) TopProductos(q domain.TopProductsQuery) ([]domain.TopProduct, error) {
+	return r.TopProductosContext(context.Background(), q)
+}
+
+// TopProductosContext es TopProductos con un contexto que puede cancelar la consulta.
+func (r *SaleRepo) TopProductosContext(ctx context.Context, q domain.TopProductsQuery) ([]domain.TopProduct, error) {
+
+	metric, ok := topProductMetrics[q.Metric]
+	if !ok {
+		return nil, domain.ErrInvalidInput
+	}
+
+	// Totales por producto del rango: del resumen diario si lo cubre, si no de las ventas
+	from, to, daily, err := r.dailyRange(ctx, q.Desde, q.Hasta)
+	if err != nil {
+		return nil, err
+	}
+
+	var totals string
+	var args []any
+
+	if daily {
+		totals = `
+			SELECT product_id, SUM(cantidad) AS cantidad, SUM(subtotal) AS total,
+			       SUM(ventas) AS ventas, SUM(neto) AS neto, SUM(costo) AS costo
+			FROM daily_product_sales
+			WHERE dia >= ? AND dia < ?
+			GROUP BY product_id`
+		args = append(args, from, to)
+	} else {
+		var saleWhere []string
+		saleWhere, args = saleFilterConds(domain.SaleFilter{Desde: q.Desde, Hasta: q.Hasta})
+		salesFilter := ""
+		if len(saleWhere) > 0 {
+			salesFilter = " WHERE " + strings.Join(saleWhere, " AND ")
+		}
+		totals = `
+			SELECT si.product_id, SUM(si.cantidad) AS cantidad, SUM(si.subtotal) AS total,
+			       COUNT(DISTINCT si.sale_id) AS ventas,
+			       SUM(` + lineNetSQL + `) AS neto, SUM(` + lineCostSQL + `) AS costo
+			FROM sale_items si
+			JOIN sales s ON s.id = si.sale_id` + salesFilter + `
+			GROUP BY si.product_id`
+	}
+
+	var where []string
+	if !q.Asc && !q.All {
+		where = append(where, `t.product_id IS NOT NULL`)
+	}
+	if q.CategoryID > 0 {
+		where = append(where, `p.category_id IN (`+categorySubtreeSQL+`)`)
+		args = append(args, q.CategoryID)
+	}
+	productFilter := ""
+	if len(where) > 0 {
+		productFilter = " WHERE " + strings.Join(where, " AND ")
+	}
+
+	dir := "DESC"
+	if q.Asc {
+		dir = "ASC"
+	}
+
+	limit := ""
+	if !q.All {
+		limit = `
+		LIMIT ?`
+		args = append(args, q.Limit)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT p.id, IFNULL(p.sku, ''), p.nombre,
+		       IFNULL(t.cantidad, 0) AS cantidad, IFNULL(t.total, 0) AS total, IFNULL(t.ventas, 0),
+		       IFNULL(t.costo, 0), IFNULL(t.neto - t.costo, 0) AS utilidad
+		FROM products p
+		LEFT JOIN (`+totals+`
+		) t ON t.product_id = p.id`+productFilter+`
+		ORDER BY `+metric+` `+dir+`, p.id`+limit, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []domain.TopProduct{}
+
+	for rows.Next() {
+		var t domain.TopProduct
+		if err := rows.Scan(&t.ProductID, &t.SKU, &t.Producto, &t.Cantidad, &t.Total, &t.Ventas, &t.Costo, &t.Utilidad); err != nil {
+			return nil, err
+		}
+		result = append(result, t)
+	}
+
+	return result, rows.Err()
+}
+
+// categoryAncestorsSQL relaciona cada categoría consigo misma y con sus ancestros
+// (para sumar las subcategorías en sus padres). Va después de WITH RECURSIVE.
+const categoryAncestorsSQL = `ancestors(category_id, ancestor_id) AS (
+				SELECT id, id FROM categories
+				UNION ALL
+				SELECT a.category_id, c.parent_id
+				FROM ancestors a JOIN categories c ON c.id = a.ancestor_id
+				WHERE c.parent_id IS NOT NULL
+			)`
+
+// Margins devuelve ingresos netos y costo de lo vendido en [desde, hasta)
+// agrupados por venta (más recientes primero), producto o categoría (ordenados por utilidad).
+// En categorías cada fila incluye las ventas de sus subcategorías.
+// Por producto y categoría usa el resumen diario si cubre el rango.
+// Utilidad y margen los calcula el servicio.
+func (r *SaleRepo) Margins(groupBy string, desde, hasta time.Time, limit int) ([]domain.MarginRow, error) {
+
+	where, args := saleFilterConds(domain.SaleFilter{Desde: desde, Hasta: hasta})
+	filter := ""
+	if len(where) > 0 {
+		filter = " WHERE " + strings.Join(where, " AND ")
+	}
+
+	// Por producto y categoría se lee del resumen diario si cubre el rango
+	from, to, daily, err := r.dailyRange(context.Background(), desde, hasta)
+	if err != nil {
+		return nil, err
+	}
+	if daily && groupBy != domain.GroupBySale {
+		args = []any{from, to}
+	}
+
+	var query string
+
+	switch {
+	case groupBy == domain.GroupByProduct && daily:
+		query = `
+			SELECT p.id, p.nombre, NULL, SUM(d.cantidad), SUM(d.neto), SUM(d.costo)
+			FROM daily_product_sales d
+			JOIN products p ON p.id = d.product_id
+			WHERE d.dia >= ? AND d.dia < ?
+			GROUP BY p.id
+			ORDER BY SUM(d.neto) - SUM(d.costo) DESC, p.id`
+	case groupBy == domain.GroupByCategory && daily:
+		query = `
+			WITH RECURSIVE ` + categoryAncestorsSQL + `
+			SELECT cat.id, cat.nombre, NULL, SUM(d.cantidad), SUM(d.neto), SUM(d.costo)
+			FROM daily_product_sales d
+			JOIN products p ON p.id = d.product_id
+			JOIN ancestors a ON a.category_id = p.category_id
+			JOIN categories cat ON cat.id = a.ancestor_id
+			WHERE d.dia >= ? AND d.dia < ?
+			GROUP BY cat.id
+			ORDER BY SUM(d.neto) - SUM(d.costo) DESC, cat.id`
+	case groupBy == domain.GroupBySale:
+		query = `
+			SELECT s.id, c.nombre, s.fecha, SUM(si.cantidad), s.total - s.impuesto, SUM(` + lineCostSQL + `)
+			FROM sales s
+			JOIN clients c ON c.id = s.client_id
+			JOIN sale_items si ON si.sale_id = s.id` + filter + `
+			GROUP BY s.id
+			ORDER BY s.fecha DESC, s.id DESC`
+	case groupBy == domain.GroupByProduct:
+		query = `
+			SELECT p.id, p.nombre, NULL, SUM(si.cantidad), SUM(` + lineNetSQL + `), SUM(` + lineCostSQL + `)
+			FROM sale_items si
+			JOIN sales s ON s.id = si.sale_id
+			JOIN products p ON p.id = si.product_id` + filter + `
+			GROUP BY p.id
+			ORDER BY SUM(` + lineNetSQL + `) - SUM(` + lineCostSQL + `) DESC, p.id`
+	case groupBy == domain.GroupByCategory:
+		query = `
+			WITH RECURSIVE ` + categoryAncestorsSQL + `
+			SELECT cat.id, cat.nombre, NULL, SUM(si.cantidad), SUM(` + lineNetSQL + `), SUM(` + lineCostSQL + `)
+			FROM sale_items si
+			JOIN sales s ON s.id = si.sale_id
+			JOIN products p ON p.id = si.product_id
+			JOIN ancestors a ON a.category_id = p.category_id
+			JOIN categories cat ON cat.id = a.ancestor_id` + filter + `
+			GROUP BY cat.id
+			ORDER BY SUM(` + lineNetSQL + `) - SUM(` + lineCostSQL + `) DESC, cat.id`
+	default:
+		return nil, domain.ErrInvalidInput
+	}
+
+	args = append(args, limit)
+
+	rows, err := r.db.Query(query+` LIMIT ?`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []domain.MarginRow{}
+
+	for rows.Next() {
+		var m domain.MarginRow
+		var fecha sql.NullString
+		if err := rows.Scan(&m.ID, &m.Nombre, &fecha, &m.Cantidad, &m.Ingresos, &m.Costo); err != nil {
+			return nil, err
+		}
+		if fecha.Valid {
+			t := parseTime(fecha.String)
+			m.Fecha = &t
+		}
+		result = append(result, m)
+	}
+
+	return result, rows.Err()
+}
+
+// DemandHistory devuelve, para cada producto (filtrado por categoría), las unidades
+// vendidas en cada rango de days (normalmente días consecutivos), en el mismo orden.
+// Los rangos se cruzan con las ventas en una sola consulta, igual que SalesTotals;
+// si son días completos y el resumen diario los cubre, se leen de ahí.
+func (r *SaleRepo) DemandHistory(days []domain.TimeRange, categoryID int64) ([]domain.DemandSeries, error) {
+
+	if len(days) == 0 {
+		return nil, nil
+	}
+
+	args := []any{domain.MovementInitial}
+	where := ""
+	if categoryID > 0 {
+		where = ` WHERE p.category_id IN (` + categorySubtreeSQL + `)`
+		args = append(args, categoryID)
+	}
+
+	rows, err := r.db.Query(`
+		SELECT p.id, IFNULL(p.sku, ''), p.nombre, p.stock, p.costo,
+		       (SELECT MIN(m.fecha) FROM stock_movements m WHERE m.product_id = p.id AND m.tipo = ?)
+		FROM products p`+where+`
+		ORDER BY p.id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []domain.DemandSeries{}
+	index := make(map[int64]int)
+
+	for rows.Next() {
+		d := domain.DemandSeries{Unidades: make([]int, len(days))}
+		var alta sql.NullString
+		if err := rows.Scan(&d.ProductID, &d.SKU, &d.Producto, &d.Stock, &d.Costo, &alta); err != nil {
+			return nil, err
+		}
+		if alta.Valid {
+			t := parseTime(alta.String)
+			d.Alta = &t
+		}
+		index[d.ProductID] = len(result)
+		result = append(result, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Días locales completos: se leen del resumen diario si lo cubre
+	fullDays := true
+	for _, d := range days {
+		if !d.Hasta.Equal(d.Desde.AddDate(0, 0, 1)) {
+			fullDays = false
+			break
+		}
+	}
+	if fullDays {
+		from, to, daily, err := r.dailyRange(context.Background(), days[0].Desde, days[len(days)-1].Hasta)
+		if err != nil {
+			return nil, err
+		}
+		if daily {
+			return result, r.dailyUnits(days, from, to, result, index)
+		}
+	}
+
+	values := make([]string, len(days))
+	args = make([]any, 0, len(days)*3+2)
+	for i, tr := range days {
+		values[i] = "(?,?,?)"
+		args = append(args, i, formatTime(tr.Desde), formatTime(tr.Hasta))
+	}
+	args = append(args, formatTime(days[0].Desde), formatTime(days[len(days)-1].Hasta))
+
+	rows, err = r.db.Query(`
+		WITH b(i, desde, hasta) AS (VALUES `+strings.Join(values, ",")+`)
+		SELECT si.product_id, b.i, SUM(si.cantidad)
+		FROM sales s
+		JOIN b ON s.fecha >= b.desde AND s.fecha < b.hasta
+		JOIN sale_items si ON si.sale_id = s.id
+		WHERE s.fecha >= ? AND s.fecha < ?
+		GROUP BY si.product_id, b.i`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var productID int64
+		var day, units int
+		if err := rows.Scan(&productID, &day, &units); err != nil {
+			return nil, err
+		}
+		if i, ok := index[productID]; ok {
+			result[i].Unidades[day] = units
+		}
+	}
+
+	return result, rows.Err()
+}
+
+// DiscountsByCashier suma por cajero sus ventas y los descuentos manuales que dio,
+// separando los de las ventas que autorizó un supervisor. Ordenado por monto descontado.
+func (r *SaleRepo) DiscountsByCashier(desde, hasta time.Time) ([]domain.DiscountReportRow, error) {
+
+	where, args := saleFilterConds(domain.SaleFilter{Desde: desde, Hasta: hasta})
+	filter := ""
+	if len(where) > 0 {
+		filter = " WHERE " + strings.Join(where, " AND ")
+	}
+
+	rows, err := r.db.Query(`
+		SELECT IFNULL(s.cajero, ''), COUNT(*), IFNULL(SUM(s.total + s.descuento - s.impuesto), 0),
+			COUNT(d.sale_id), IFNULL(SUM(d.descuentos), 0), IFNULL(SUM(d.monto), 0),
+			SUM(s.autorizado_por IS NOT NULL),
+			IFNULL(SUM(CASE WHEN s.autorizado_por IS NOT NULL THEN d.monto END), 0)
+		FROM sales s
+		LEFT JOIN (
+			SELECT sale_id, COUNT(*) AS descuentos, SUM(monto) AS monto
+			FROM sale_discounts GROUP BY sale_id
+		) d ON d.sale_id = s.id`+filter+`
+		GROUP BY IFNULL(s.cajero, '')
+		ORDER BY IFNULL(SUM(d.monto), 0) DESC, IFNULL(s.cajero, '')`,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []domain.DiscountReportRow{}
+	for rows.Next() {
+		var row domain.DiscountReportRow
+		err := rows.Scan(&row.Cajero, &row.Ventas, &row.Bruto, &row.VentasDescuento, &row.Descuentos,
+			&row.Monto, &row.Autorizadas, &row.MontoAutorizado)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, row)
+	}
+
+	return list, rows.Err()
+}
@@ -0,0 +1,20 @@
+package sqlite
+
+import "time"
+
+// Las fechas se guardan en UTC con formato RFC3339 ("2026-10-19T00:30:00Z").
+// Así se comparan como texto (y usan los índices) sin importar la zona del servidor.
+// Al leerlas se pasan a time.Local, que main configura con la zona horaria de la tienda.
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// parseTime devuelve la fecha cero si el texto no es RFC3339.
+func parseTime(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}
+	}
+	return t.Local()
+}
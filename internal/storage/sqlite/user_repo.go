@@ -0,0 +1,330 @@
+package sqlite
+
+import (
+	"database/sql"
+	"time"
+
+	"ferreteria-inventario-ventas/internal/domain"
+)
+
+// UserRepo maneja las operaciones de base de datos para usuarios, roles, sesiones
+// y aprobaciones de descuentos.
+type UserRepo struct {
+	db *sql.DB
+}
+
+// Constructor del repositorio.
+func NewUserRepo(db *sql.DB) *UserRepo {
+	return &UserRepo{db: db}
+}
+
+// userColumns son las columnas que se leen de un usuario (sin la contraseña).
+const userColumns = `id, usuario, nombre, rol, activo, creado`
+
+// scanUser lee una fila con userColumns.
+func scanUser(row rowScanner) (domain.User, error) {
+	var u domain.User
+	var creado string
+
+	err := row.Scan(&u.ID, &u.Usuario, &u.Nombre, &u.Rol, &u.Activo, &creado)
+	u.Creado = parseTime(creado)
+	return u, err
+}
+
+// Create inserta un usuario con el hash de su contraseña y registra el alta con audit
+// en la misma transacción. ErrConflict si el usuario ya existe; ErrNotFound si el rol no existe.
+func (r *UserRepo) Create(u *domain.User, passwordHash string, audit domain.AuditFunc) error {
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(
+		`INSERT INTO users(usuario, nombre, rol, password_hash, activo, creado) VALUES(?,?,?,?,?,?)`,
+		u.Usuario, u.Nombre, u.Rol, passwordHash, u.Activo, formatTime(u.Creado),
+	)
+	if err != nil {
+		return mapConstraintError(err)
+	}
+
+	u.ID, _ = result.LastInsertId()
+
+	if err := appendAudit(tx, audit, u.ID, nil, u); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Get devuelve un usuario por ID.
+func (r *UserRepo) Get(id int64) (*domain.User, error) {
+	return getUser(r.db, id)
+}
+
+// getUser lee un usuario con la base o dentro de una transacción.
+func getUser(q queryer, id int64) (*domain.User, error) {
+
+	u, err := scanUser(q.QueryRow(`SELECT `+userColumns+` FROM users WHERE id = ?`, id))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &u, nil
+}
+
+// GetByUsername devuelve un usuario y el hash de su contraseña.
+func (r *UserRepo) GetByUsername(usuario string) (*domain.User, string, error) {
+
+	var hash string
+	var creado string
+	var u domain.User
+
+	err := r.db.QueryRow(
+		`SELECT `+userColumns+`, password_hash FROM users WHERE usuario = ?`,
+		usuario,
+	).Scan(&u.ID, &u.Usuario, &u.Nombre, &u.Rol, &u.Activo, &creado, &hash)
+	if err == sql.ErrNoRows {
+		return nil, "", domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	u.Creado = parseTime(creado)
+	return &u, hash, nil
+}
+
+// List devuelve todos los usuarios ordenados por usuario.
+func (r *UserRepo) List() ([]domain.User, error) {
+
+	rows, err := r.db.Query(`SELECT ` + userColumns + ` FROM users ORDER BY usuario`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []domain.User{}
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, u)
+	}
+
+	return list, rows.Err()
+}
+
+// Update cambia nombre, rol y estado; y la contraseña si passwordHash no está vacío.
+// El cambio se registra con audit en la misma transacción (la contraseña sin su valor)
+// y u queda como se guardó.
+func (r *UserRepo) Update(id int64, u *domain.User, passwordHash string, audit domain.AuditFunc) error {
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := getUser(tx, id)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(
+		`UPDATE users SET nombre=?, rol=?, activo=?,
+			password_hash = CASE WHEN ? = '' THEN password_hash ELSE ? END
+		 WHERE id=?`,
+		u.Nombre, u.Rol, u.Activo, passwordHash, passwordHash, id,
+	)
+	if err != nil {
+		return mapConstraintError(err)
+	}
+
+	after, err := getUser(tx, id)
+	if err != nil {
+		return err
+	}
+
+	var changes any = after
+	if passwordHash != "" {
+		changes = struct {
+			*domain.User
+			Password string `json:"password"`
+		}{after, "cambiada"}
+	}
+	if err := appendAudit(tx, audit, id, before, changes); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	*u = *after
+	return nil
+}
+
+// Roles devuelve los roles ordenados por descuento máximo.
+func (r *UserRepo) Roles() ([]domain.Role, error) {
+
+	rows, err := r.db.Query(`SELECT nombre, descuento_max FROM roles ORDER BY descuento_max, nombre`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	list := []domain.Role{}
+	for rows.Next() {
+		var role domain.Role
+		if err := rows.Scan(&role.Nombre, &role.DescuentoMax); err != nil {
+			return nil, err
+		}
+		list = append(list, role)
+	}
+
+	return list, rows.Err()
+}
+
+// Role devuelve un rol por nombre.
+func (r *UserRepo) Role(nombre string) (*domain.Role, error) {
+	return getRole(r.db, nombre)
+}
+
+// getRole lee un rol con la base o dentro de una transacción.
+func getRole(q queryer, nombre string) (*domain.Role, error) {
+
+	var role domain.Role
+	err := q.QueryRow(`SELECT nombre, descuento_max FROM roles WHERE nombre = ?`, nombre).
+		Scan(&role.Nombre, &role.DescuentoMax)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &role, nil
+}
+
+// SaveRole crea el rol o cambia su descuento máximo y registra el cambio con audit
+// en la misma transacción. La auditoría de roles usa el nombre como campo:
+// {"cajero": {"antes": 5, "despues": 8}}.
+func (r *UserRepo) SaveRole(role domain.Role, audit domain.AuditFunc) error {
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var before map[string]float64
+	prev, err := getRole(tx, role.Nombre)
+	switch {
+	case err == nil:
+		before = map[string]float64{prev.Nombre: prev.DescuentoMax}
+	case err != domain.ErrNotFound:
+		return err
+	}
+
+	_, err = tx.Exec(
+		`INSERT INTO roles(nombre, descuento_max) VALUES(?,?)
+		 ON CONFLICT(nombre) DO UPDATE SET descuento_max = excluded.descuento_max`,
+		role.Nombre, role.DescuentoMax,
+	)
+	if err != nil {
+		return err
+	}
+
+	after := map[string]float64{role.Nombre: role.DescuentoMax}
+	if err := appendAudit(tx, audit, 0, before, after); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// CreateApproval guarda un token de aprobación (solo su hash).
+func (r *UserRepo) CreateApproval(a *domain.DiscountApproval, tokenHash string) error {
+
+	result, err := r.db.Exec(
+		`INSERT INTO discount_approvals(token_hash, supervisor, descuento_max, creado, expira) VALUES(?,?,?,?,?)`,
+		tokenHash, a.Supervisor, a.DescuentoMax, formatTime(a.Creado), formatTime(a.Expira),
+	)
+	if err != nil {
+		return err
+	}
+
+	a.ID, _ = result.LastInsertId()
+	return nil
+}
+
+// FindApproval devuelve el token de aprobación sin usar y vigente en now.
+// ErrNotFound si no existe, ya se usó o venció.
+func (r *UserRepo) FindApproval(tokenHash string, now time.Time) (*domain.DiscountApproval, error) {
+
+	var a domain.DiscountApproval
+	var creado, expira string
+
+	err := r.db.QueryRow(
+		`SELECT id, supervisor, descuento_max, creado, expira FROM discount_approvals
+		 WHERE token_hash = ? AND sale_id IS NULL AND expira > ?`,
+		tokenHash, formatTime(now),
+	).Scan(&a.ID, &a.Supervisor, &a.DescuentoMax, &creado, &expira)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	a.Creado = parseTime(creado)
+	a.Expira = parseTime(expira)
+	return &a, nil
+}
+
+// CountUsers devuelve cuántos usuarios hay registrados.
+func (r *UserRepo) CountUsers() (int, error) {
+	var n int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM users`).Scan(&n)
+	return n, err
+}
+
+// CreateSession guarda una sesión del usuario (solo el hash del token).
+func (r *UserRepo) CreateSession(s *domain.Session, tokenHash string) error {
+	_, err := r.db.Exec(
+		`INSERT INTO sessions(token_hash, user_id, creado, expira) VALUES(?,?,?,?)`,
+		tokenHash, s.Usuario.ID, formatTime(s.Creado), formatTime(s.Expira),
+	)
+	return err
+}
+
+// FindSession devuelve el usuario de la sesión vigente en now.
+// ErrNotFound si no existe, venció o el usuario está inactivo.
+func (r *UserRepo) FindSession(tokenHash string, now time.Time) (*domain.User, error) {
+
+	u, err := scanUser(r.db.QueryRow(
+		`SELECT u.id, u.usuario, u.nombre, u.rol, u.activo, u.creado
+		 FROM sessions s JOIN users u ON u.id = s.user_id
+		 WHERE s.token_hash = ? AND s.expira > ? AND u.activo = 1`,
+		tokenHash, formatTime(now),
+	))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &u, nil
+}
+
+// DeleteSession cierra una sesión y borra las vencidas en now.
+func (r *UserRepo) DeleteSession(tokenHash string, now time.Time) error {
+	_, err := r.db.Exec(`DELETE FROM sessions WHERE token_hash = ? OR expira <= ?`, tokenHash, formatTime(now))
+	return err
+}
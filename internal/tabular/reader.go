@@ -0,0 +1,87 @@
+// Package tabular lee y escribe archivos de tabla (CSV, XLSX y NDJSON)
+// usando solo la librería estándar.
+package tabular
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// Formatos soportados.
+const (
+	FormatCSV    = "csv"
+	FormatXLSX   = "xlsx"
+	FormatNDJSON = "ndjson"
+)
+
+// ErrUnsupportedFormat se devuelve cuando no se reconoce el formato del archivo.
+var ErrUnsupportedFormat = errors.New("formato no soportado")
+
+// utf8BOM es la marca que Excel agrega al inicio de los CSV en UTF-8.
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// FormatFromName deduce el formato por la extensión del archivo.
+func FormatFromName(name string) string {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".csv", ".txt":
+		return FormatCSV
+	case ".xlsx":
+		return FormatXLSX
+	case ".ndjson", ".jsonl":
+		return FormatNDJSON
+	}
+	return ""
+}
+
+// FormatFromContentType deduce el formato por el Content-Type de la petición.
+func FormatFromContentType(contentType string) string {
+	switch {
+	case strings.HasPrefix(contentType, "text/csv"):
+		return FormatCSV
+	case strings.HasPrefix(contentType, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"):
+		return FormatXLSX
+	case strings.HasPrefix(contentType, "application/x-ndjson"):
+		return FormatNDJSON
+	}
+	return ""
+}
+
+// Read lee todas las filas de un archivo CSV o XLSX (primera hoja).
+// La primera fila devuelta es la cabecera.
+func Read(format string, data []byte) ([][]string, error) {
+	switch format {
+	case FormatCSV:
+		return ReadCSV(bytes.NewReader(data))
+	case FormatXLSX:
+		return ReadXLSX(bytes.NewReader(data), int64(len(data)))
+	}
+	return nil, ErrUnsupportedFormat
+}
+
+// ReadCSV lee un CSV separado por comas o por punto y coma
+// (Excel en español guarda con punto y coma). Ignora el BOM inicial.
+func ReadCSV(r io.Reader) ([][]string, error) {
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimPrefix(data, utf8BOM)
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.LazyQuotes = true // Medidas en pulgadas: Clavo 2"
+
+	// El separador se decide por la cabecera
+	firstLine, _, _ := bytes.Cut(data, []byte("\n"))
+	if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		reader.Comma = ';'
+	}
+
+	return reader.ReadAll()
+}
@@ -0,0 +1,158 @@
+package tabular
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Writer escribe filas de una tabla de a una, sin guardar el archivo en memoria.
+// Los valores pueden ser string, números, bool, time.Time o nil.
+type Writer interface {
+	Write(values ...any) error
+	// Close termina el archivo (en XLSX cierra la hoja y el zip).
+	Close() error
+}
+
+// ContentType devuelve el tipo MIME de cada formato.
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	}
+	return "application/octet-stream"
+}
+
+// NewWriter crea un escritor del formato pedido. header son los nombres de las
+// columnas: en CSV y XLSX van en la primera fila y en NDJSON son las claves de cada objeto.
+func NewWriter(format string, w io.Writer, header []string) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w, header)
+	case FormatXLSX:
+		return newXLSXWriter(w, header)
+	case FormatNDJSON:
+		n := &ndjsonWriter{w: bufio.NewWriter(w), header: header}
+		n.enc = json.NewEncoder(&n.buf)
+		n.enc.SetEscapeHTML(false)
+		return n, nil
+	}
+	return nil, ErrUnsupportedFormat
+}
+
+// csvWriter escribe CSV en UTF-8 con BOM para que Excel muestre bien las tildes.
+type csvWriter struct {
+	w      *csv.Writer
+	record []string
+}
+
+func newCSVWriter(w io.Writer, header []string) (*csvWriter, error) {
+	if _, err := w.Write(utf8BOM); err != nil {
+		return nil, err
+	}
+	cw := &csvWriter{w: csv.NewWriter(w)}
+	if err := cw.w.Write(header); err != nil {
+		return nil, err
+	}
+	return cw, nil
+}
+
+func (c *csvWriter) Write(values ...any) error {
+	c.record = c.record[:0]
+	for _, v := range values {
+		if t, ok := v.(string); ok {
+			v = escapeFormula(t)
+		}
+		c.record = append(c.record, formatValue(v))
+	}
+	return c.w.Write(c.record)
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// ndjsonWriter escribe un objeto JSON por línea con las claves de header.
+type ndjsonWriter struct {
+	w      *bufio.Writer
+	buf    bytes.Buffer
+	enc    *json.Encoder // Escribe en buf sin escapar < > &
+	header []string
+}
+
+func (n *ndjsonWriter) Write(values ...any) error {
+	// Se arma el objeto a mano para respetar el orden de las columnas
+	n.w.WriteByte('{')
+	for i, v := range values {
+		if i > 0 {
+			n.w.WriteByte(',')
+		}
+		if err := n.value(n.header[i]); err != nil {
+			return err
+		}
+		n.w.WriteByte(':')
+
+		if t, ok := v.(time.Time); ok {
+			v = t.Format(time.RFC3339)
+		}
+		if err := n.value(v); err != nil {
+			return err
+		}
+	}
+	n.w.WriteByte('}')
+	return n.w.WriteByte('\n')
+}
+
+// value escribe un valor JSON sin el salto de línea que agrega Encoder.
+func (n *ndjsonWriter) value(v any) error {
+	n.buf.Reset()
+	if err := n.enc.Encode(v); err != nil {
+		return err
+	}
+	_, err := n.w.Write(bytes.TrimSuffix(n.buf.Bytes(), []byte("\n")))
+	return err
+}
+
+func (n *ndjsonWriter) Close() error {
+	return n.w.Flush()
+}
+
+// escapeFormula antepone ' a los textos que Excel o LibreOffice tomarían como fórmula
+// (los que empiezan con =, +, -, @, tabulador o retorno de carro).
+func escapeFormula(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+// formatValue convierte un valor a texto para CSV.
+func formatValue(v any) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return x
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case time.Time:
+		return x.Format(time.DateTime)
+	case *int64:
+		if x == nil {
+			return ""
+		}
+		return strconv.FormatInt(*x, 10)
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,219 @@
+package tabular
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// ReadXLSX lee la primera hoja de un libro XLSX como filas de texto.
+// Soporta textos compartidos, textos en línea, números y booleanos;
+// las celdas vacías intermedias se devuelven como "".
+func ReadXLSX(r io.ReaderAt, size int64) ([][]string, error) {
+
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string]*zip.File)
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	shared, err := readSharedStrings(files["xl/sharedStrings.xml"])
+	if err != nil {
+		return nil, err
+	}
+
+	sheetPath, err := firstSheetPath(files)
+	if err != nil {
+		return nil, err
+	}
+
+	sheet, ok := files[sheetPath]
+	if !ok {
+		return nil, errors.New("xlsx: hoja no encontrada")
+	}
+
+	return readSheet(sheet, shared)
+}
+
+// firstSheetPath busca en workbook.xml la primera hoja y su archivo en las relaciones.
+func firstSheetPath(files map[string]*zip.File) (string, error) {
+
+	var workbook struct {
+		Sheets []struct {
+			RID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	if err := decodeXML(files["xl/workbook.xml"], &workbook); err != nil {
+		return "", err
+	}
+	if len(workbook.Sheets) == 0 {
+		return "", errors.New("xlsx: el libro no tiene hojas")
+	}
+
+	var rels struct {
+		Rels []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	if err := decodeXML(files["xl/_rels/workbook.xml.rels"], &rels); err != nil {
+		return "", err
+	}
+
+	for _, rel := range rels.Rels {
+		if rel.ID == workbook.Sheets[0].RID {
+			if strings.HasPrefix(rel.Target, "/") {
+				return strings.TrimPrefix(rel.Target, "/"), nil
+			}
+			return path.Join("xl", rel.Target), nil
+		}
+	}
+
+	return "xl/worksheets/sheet1.xml", nil
+}
+
+// readSharedStrings lee la tabla de textos compartidos (puede no existir).
+func readSharedStrings(f *zip.File) ([]string, error) {
+
+	if f == nil {
+		return nil, nil
+	}
+
+	var sst struct {
+		Items []struct {
+			T    string `xml:"t"`
+			Runs []struct {
+				T string `xml:"t"`
+			} `xml:"r"`
+		} `xml:"si"`
+	}
+	if err := decodeXML(f, &sst); err != nil {
+		return nil, err
+	}
+
+	out := make([]string, len(sst.Items))
+	for i, it := range sst.Items {
+		if len(it.Runs) == 0 {
+			out[i] = it.T
+			continue
+		}
+		// Texto con formato: se une el texto de cada tramo
+		var b strings.Builder
+		for _, run := range it.Runs {
+			b.WriteString(run.T)
+		}
+		out[i] = b.String()
+	}
+
+	return out, nil
+}
+
+// readSheet recorre las filas de la hoja con un decodificador en streaming.
+func readSheet(f *zip.File, shared []string) ([][]string, error) {
+
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	type cell struct {
+		Ref    string `xml:"r,attr"`
+		Type   string `xml:"t,attr"`
+		Value  string `xml:"v"`
+		Inline string `xml:"is>t"`
+	}
+
+	var rows [][]string
+	dec := xml.NewDecoder(rc)
+
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "row" {
+			continue
+		}
+
+		var row struct {
+			Num   int    `xml:"r,attr"`
+			Cells []cell `xml:"c"`
+		}
+		if err := dec.DecodeElement(&row, &start); err != nil {
+			return nil, err
+		}
+
+		// Filas vacías omitidas en el archivo
+		for row.Num > len(rows)+1 {
+			rows = append(rows, nil)
+		}
+
+		var values []string
+		for _, c := range row.Cells {
+			col := len(values)
+			if c.Ref != "" {
+				col = columnIndex(c.Ref)
+			}
+			for len(values) < col {
+				values = append(values, "")
+			}
+
+			switch c.Type {
+			case "s":
+				i, err := strconv.Atoi(c.Value)
+				if err != nil || i < 0 || i >= len(shared) {
+					return nil, errors.New("xlsx: texto compartido inválido en " + c.Ref)
+				}
+				values = append(values, shared[i])
+			case "inlineStr":
+				values = append(values, c.Inline)
+			case "b":
+				values = append(values, map[string]string{"1": "true", "0": "false"}[c.Value])
+			default:
+				values = append(values, c.Value)
+			}
+		}
+
+		rows = append(rows, values)
+	}
+
+	return rows, nil
+}
+
+// columnIndex convierte la referencia de celda (ej: "C12") en índice de columna desde 0.
+func columnIndex(ref string) int {
+	n := 0
+	for _, ch := range ref {
+		if ch < 'A' || ch > 'Z' {
+			break
+		}
+		n = n*26 + int(ch-'A'+1)
+	}
+	return n - 1
+}
+
+func decodeXML(f *zip.File, dst any) error {
+	if f == nil {
+		return errors.New("xlsx: archivo incompleto")
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	return xml.NewDecoder(rc).Decode(dst)
+}
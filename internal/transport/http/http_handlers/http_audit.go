@@ -0,0 +1,75 @@
+package http_handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"ferreteria-inventario-ventas/internal/domain"
+)
+
+// Audit godoc
+// @Summary Auditoría de cambios
+// @Description Altas, cambios y bajas de productos, clientes, ventas y cambios de precio con actor (usuario de la sesión), ID de petición (header X-Request-ID),
+// @Description fecha y los campos que cambiaron ({"precio": {"antes": 5, "despues": 6}}). Por defecto los más recientes primero.
+// @Tags Audit
+// @Produce json
+// @Param actor query string false "Usuario que hizo el cambio"
+// @Param action query string false "create, update, delete, import o barcodes"
+// @Param entity query string false "product, client, sale o price_change"
+// @Param entity_id query int false "ID del registro"
+// @Param request_id query string false "ID de la petición"
+// @Param from query string false "Desde (YYYY-MM-DD o RFC3339)"
+// @Param to query string false "Hasta, inclusive si es YYYY-MM-DD"
+// @Param limit query int false "Filas por página (por defecto 50, máximo 500)"
+// @Param cursor query string false "Cursor devuelto en next_cursor"
+// @Param sort query string false "Campo de orden: id, fecha"
+// @Param order query string false "asc o desc"
+// @Success 200 {object} domain.Page[domain.AuditEntry]
+// @Router /api/audit [get]
+func (h *Handlers) Audit(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	filter, err := parseAuditFilter(r)
+	if err != nil {
+		writeJSON(w, 400, map[string]string{"error": "filtro inválido"})
+		return
+	}
+	page, err := parsePage(r)
+	if err != nil {
+		writeJSON(w, 400, map[string]string{"error": "paginación inválida"})
+		return
+	}
+
+	list, err := h.AuditSvc.List(filter, page)
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+	writeJSON(w, 200, list)
+}
+
+// parseAuditFilter lee actor, action, entity, entity_id, request_id, from y to.
+func parseAuditFilter(r *http.Request) (domain.AuditFilter, error) {
+	q := r.URL.Query()
+
+	f := domain.AuditFilter{
+		Actor:     strings.TrimSpace(q.Get("actor")),
+		Accion:    q.Get("action"),
+		Entidad:   q.Get("entity"),
+		RequestID: strings.TrimSpace(q.Get("request_id")),
+	}
+
+	var err error
+	if f.EntidadID, err = optionalID(q.Get("entity_id")); err != nil {
+		return f, err
+	}
+	if f.Desde, f.Hasta, err = parseDateRange(r); err != nil {
+		return f, err
+	}
+
+	return f, nil
+}
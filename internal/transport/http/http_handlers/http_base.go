@@ -3,15 +3,28 @@ package http_handlers
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
+	"strings"
 
+	"ferreteria-inventario-ventas/internal/domain"
 	"ferreteria-inventario-ventas/internal/service"
 )
 
 // Handlers agrupa los servicios.
 type Handlers struct {
-	ClientsSvc  *service.ClientService
-	ProductsSvc *service.ProductService
-	SalesSvc    *service.SaleService
+	ClientsSvc      *service.ClientService
+	ProductsSvc     *service.ProductService
+	SalesSvc        *service.SaleService
+	CategoriesSvc   *service.CategoryService
+	BrandsSvc       *service.BrandService
+	ReceiptsSvc     *service.ReceiptService
+	AssociationsSvc *service.AssociationService
+	DashboardSvc    *service.DashboardService
+	AuditSvc        *service.AuditService
+	PricesSvc       *service.PriceService
+	PriceListsSvc   *service.PriceListService
+	PromotionsSvc   *service.PromotionService
+	UsersSvc        *service.UserService
 }
 
 // Función auxiliar para responder JSON.
@@ -21,6 +34,55 @@ func writeJSON(w http.ResponseWriter, status int, data any) {
 	_ = json.NewEncoder(w).Encode(data)
 }
 
+// writeListError responde el error de un listado:
+// 400 si los parámetros no son válidos (campo de orden, límite o cursor), 500 en otro caso.
+func writeListError(w http.ResponseWriter, err error) {
+	if err == domain.ErrInvalidInput {
+		writeJSON(w, 400, map[string]string{"error": "parámetros de listado inválidos"})
+		return
+	}
+	writeJSON(w, 500, map[string]string{"error": err.Error()})
+}
+
+// writeError responde un error del servicio con el código HTTP que le corresponde.
+func writeError(w http.ResponseWriter, err error) {
+	switch err {
+	case domain.ErrInvalidInput:
+		writeJSON(w, 400, map[string]string{"error": err.Error()})
+	case domain.ErrNotFound:
+		writeJSON(w, 404, map[string]string{"error": err.Error()})
+	case domain.ErrConflict:
+		writeJSON(w, 409, map[string]string{"error": err.Error()})
+	case domain.ErrUnauthenticated, domain.ErrForbidden:
+		writeAccessError(w, err)
+	default:
+		writeJSON(w, 500, map[string]string{"error": err.Error()})
+	}
+}
+
+// writeAccessError responde 401 sin sesión y 403 si el rol no alcanza.
+func writeAccessError(w http.ResponseWriter, err error) {
+	if err == domain.ErrUnauthenticated {
+		writeJSON(w, 401, map[string]string{"error": "inicie sesión (header Authorization: Bearer <token>)"})
+		return
+	}
+	writeJSON(w, 403, map[string]string{"error": "requiere una sesión de supervisor o gerente"})
+}
+
+// pathID lee el ID que viene después de prefix en la ruta (ej: /api/brands/3).
+// Devuelve 0 si la ruta no trae ID.
+func pathID(r *http.Request, prefix string) (int64, error) {
+	idStr := strings.Trim(strings.TrimPrefix(r.URL.Path, prefix), "/")
+	if idStr == "" {
+		return 0, nil
+	}
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, domain.ErrInvalidInput
+	}
+	return id, nil
+}
+
 // Health verifica que el servidor está funcionando.
 func (h *Handlers) Health(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, 200, map[string]string{
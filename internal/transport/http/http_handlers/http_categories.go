@@ -0,0 +1,149 @@
+package http_handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"ferreteria-inventario-ventas/internal/domain"
+)
+
+// Categories godoc
+// @Summary Árbol de categorías
+// @Description GET lista categorías (formato=arbol devuelve el árbol anidado), POST crea categoría,
+// @Description PUT /api/categories/{id} actualiza (puede mover a otro padre), DELETE /api/categories/{id} elimina una categoría vacía
+// @Tags Categories
+// @Accept json
+// @Produce json
+// @Param formato query string false "plano (por defecto) o arbol"
+// @Param category body domain.Category false "Categoría (POST/PUT)"
+// @Success 200 {array} domain.Category
+// @Success 201 {object} domain.Category
+// @Router /api/categories [get]
+// @Router /api/categories [post]
+// @Router /api/categories/{id} [put]
+// @Router /api/categories/{id} [delete]
+func (h *Handlers) Categories(w http.ResponseWriter, r *http.Request) {
+
+	id, err := pathID(r, "/api/categories")
+	if err != nil {
+		writeJSON(w, 400, map[string]string{"error": "id inválido"})
+		return
+	}
+
+	switch {
+
+	case r.Method == http.MethodGet && id == 0:
+		var list []domain.Category
+		if r.URL.Query().Get("formato") == "arbol" {
+			list, err = h.CategoriesSvc.Tree()
+		} else {
+			list, err = h.CategoriesSvc.List()
+		}
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, 200, list)
+
+	case r.Method == http.MethodPost && id == 0:
+		var input domain.Category
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			writeJSON(w, 400, map[string]string{"error": "JSON inválido"})
+			return
+		}
+		if err := h.CategoriesSvc.Create(&input); err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, 201, input)
+
+	case r.Method == http.MethodPut && id > 0:
+		var input domain.Category
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			writeJSON(w, 400, map[string]string{"error": "JSON inválido"})
+			return
+		}
+		if err := h.CategoriesSvc.Update(id, &input); err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, 200, input)
+
+	case r.Method == http.MethodDelete && id > 0:
+		if err := h.CategoriesSvc.Delete(id); err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, 200, map[string]string{"deleted": "ok"})
+
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// Brands godoc
+// @Summary Marcas
+// @Description GET lista marcas, POST crea marca, PUT /api/brands/{id} actualiza, DELETE /api/brands/{id} elimina una marca sin productos
+// @Tags Brands
+// @Accept json
+// @Produce json
+// @Param brand body domain.Brand false "Marca (POST/PUT)"
+// @Success 200 {array} domain.Brand
+// @Success 201 {object} domain.Brand
+// @Router /api/brands [get]
+// @Router /api/brands [post]
+// @Router /api/brands/{id} [put]
+// @Router /api/brands/{id} [delete]
+func (h *Handlers) Brands(w http.ResponseWriter, r *http.Request) {
+
+	id, err := pathID(r, "/api/brands")
+	if err != nil {
+		writeJSON(w, 400, map[string]string{"error": "id inválido"})
+		return
+	}
+
+	switch {
+
+	case r.Method == http.MethodGet && id == 0:
+		list, err := h.BrandsSvc.List()
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, 200, list)
+
+	case r.Method == http.MethodPost && id == 0:
+		var input domain.Brand
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			writeJSON(w, 400, map[string]string{"error": "JSON inválido"})
+			return
+		}
+		if err := h.BrandsSvc.Create(&input); err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, 201, input)
+
+	case r.Method == http.MethodPut && id > 0:
+		var input domain.Brand
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			writeJSON(w, 400, map[string]string{"error": "JSON inválido"})
+			return
+		}
+		if err := h.BrandsSvc.Update(id, &input); err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, 200, input)
+
+	case r.Method == http.MethodDelete && id > 0:
+		if err := h.BrandsSvc.Delete(id); err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, 200, map[string]string{"deleted": "ok"})
+
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
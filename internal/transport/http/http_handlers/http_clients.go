@@ -3,6 +3,8 @@ package http_handlers
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"ferreteria-inventario-ventas/internal/domain"
 )
@@ -13,12 +15,19 @@ import (
 
 // Clients godoc
 // @Summary Listar o crear clientes
-// @Description GET lista clientes, POST crea cliente
+// @Description GET lista clientes paginados con filtros, POST crea cliente (price_list_id opcional asigna su lista de precios)
 // @Tags Clients
 // @Accept json
 // @Produce json
+// @Param limit query int false "Filas por página (por defecto 50, máximo 500)"
+// @Param cursor query string false "Cursor devuelto en next_cursor"
+// @Param sort query string false "Campo de orden: id, nombre, cedula"
+// @Param order query string false "asc o desc"
+// @Param nombre query string false "Nombre contiene"
+// @Param cedula query string false "Cédula empieza con"
+// @Param email query string false "Email contiene"
 // @Param client body domain.Client false "Cliente (solo POST)"
-// @Success 200 {array} domain.Client
+// @Success 200 {object} domain.Page[domain.Client]
 // @Success 201 {object} domain.Client
 // @Router /clients [get]
 // @Router /clients [post]
@@ -27,9 +36,15 @@ func (h *Handlers) Clients(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 
 	case http.MethodGet:
-		list, err := h.ClientsSvc.List()
+		page, err := parsePage(r)
 		if err != nil {
-			writeJSON(w, 500, map[string]string{"error": err.Error()})
+			writeJSON(w, 400, map[string]string{"error": "paginación inválida"})
+			return
+		}
+
+		list, err := h.ClientsSvc.List(parseClientFilter(r), page)
+		if err != nil {
+			writeListError(w, err)
 			return
 		}
 		writeJSON(w, 200, list)
@@ -43,15 +58,172 @@ func (h *Handlers) Clients(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		err = h.ClientsSvc.Create(&input)
-		if err != nil {
-			writeJSON(w, 400, map[string]string{"error": err.Error()})
-			return
+		err = h.ClientsSvc.Create(r.Context(), &input)
+		switch err {
+		case nil:
+			writeJSON(w, 201, input)
+		case domain.ErrInvalidInput:
+			writeJSON(w, 400, map[string]string{"error": "nombre, cédula y email requeridos"})
+		case domain.ErrConflict:
+			writeJSON(w, 409, map[string]string{"error": "cédula ya registrada"})
+		case domain.ErrNotFound:
+			writeJSON(w, 404, map[string]string{"error": "lista de precios no encontrada"})
+		default:
+			writeJSON(w, 500, map[string]string{"error": err.Error()})
 		}
 
-		writeJSON(w, 201, input)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// ClientSales godoc
+// @Summary Historial de compras de un cliente
+// @Description Resumen (compras, unidades, total, ticket promedio, primera y última compra), productos comprados
+// @Description ordenados por monto y las compras paginadas (más recientes primero) en el rango pedido.
+// @Tags Clients
+// @Produce json
+// @Param id path int true "ID del cliente"
+// @Param from query string false "Desde (YYYY-MM-DD o RFC3339)"
+// @Param to query string false "Hasta (YYYY-MM-DD incluye ese día, o RFC3339 exclusivo)"
+// @Param limit query int false "Compras por página (por defecto 50, máximo 500)"
+// @Param cursor query string false "Cursor devuelto en compras.next_cursor"
+// @Success 200 {object} domain.ClientHistory
+// @Failure 404 {object} map[string]string
+// @Router /api/clients/{id}/sales [get]
+func (h *Handlers) ClientSales(w http.ResponseWriter, r *http.Request) {
 
+	if strings.HasSuffix(r.URL.Path, "/price-list") {
+		h.ClientPriceList(w, r)
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	idStr, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/api/clients/"), "/sales")
+	if !ok {
+		writeJSON(w, 404, map[string]string{"error": "ruta no encontrada"})
+		return
+	}
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || id <= 0 {
+		writeJSON(w, 400, map[string]string{"error": "id inválido"})
+		return
+	}
+
+	desde, hasta, err := parseDateRange(r)
+	if err != nil {
+		writeJSON(w, 400, map[string]string{"error": "fecha inválida"})
+		return
+	}
+	page, err := parsePage(r)
+	if err != nil {
+		writeJSON(w, 400, map[string]string{"error": "paginación inválida"})
+		return
+	}
+
+	history, err := h.SalesSvc.ClientHistory(id, desde, hasta, page)
+	if err != nil {
+		switch err {
+		case domain.ErrInvalidInput:
+			writeJSON(w, 400, map[string]string{"error": "parámetros inválidos"})
+		case domain.ErrNotFound:
+			writeJSON(w, 404, map[string]string{"error": "cliente no encontrado"})
+		default:
+			writeJSON(w, 500, map[string]string{"error": err.Error()})
+		}
+		return
+	}
+
+	writeJSON(w, 200, history)
+}
+
+// ClientPriceList godoc
+// @Summary Asignar lista de precios a un cliente
+// @Description Con price_list_id asigna la lista; con null la quita (el cliente paga el precio de cada producto)
+// @Tags Clients
+// @Accept json
+// @Produce json
+// @Param id path int true "ID del cliente"
+// @Param body body clientPriceListInput true "Lista de precios"
+// @Success 200 {object} domain.Client
+// @Router /api/clients/{id}/price-list [put]
+func (h *Handlers) ClientPriceList(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method != http.MethodPut {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	idStr := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/clients/"), "/price-list")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || id <= 0 {
+		writeJSON(w, 400, map[string]string{"error": "id inválido"})
+		return
+	}
+
+	var input clientPriceListInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeJSON(w, 400, map[string]string{"error": "JSON inválido"})
+		return
+	}
+
+	c, err := h.ClientsSvc.AssignPriceList(r.Context(), id, input.PriceListID)
+	switch err {
+	case nil:
+		writeJSON(w, 200, c)
+	case domain.ErrInvalidInput:
+		writeJSON(w, 400, map[string]string{"error": "price_list_id inválido"})
+	case domain.ErrNotFound:
+		writeJSON(w, 404, map[string]string{"error": "cliente o lista de precios no encontrados"})
 	default:
+		writeJSON(w, 500, map[string]string{"error": err.Error()})
+	}
+}
+
+// clientPriceListInput es el cuerpo para asignar la lista de precios de un cliente.
+type clientPriceListInput struct {
+	PriceListID *int64 `json:"price_list_id"` // null quita la lista
+}
+
+// ReportRFM godoc
+// @Summary Segmentación RFM de clientes
+// @Description Recencia (días desde la última compra), frecuencia y monto de las compras en el rango (por defecto los últimos 12 meses)
+// @Description de cada cliente que compró alguna vez, con puntajes de 1 a 5 por quintiles y segmento:
+// @Description champion, loyal, potential_loyalist, new, need_attention, cant_lose, at_risk, hibernating o lost.
+// @Tags Report
+// @Produce json
+// @Param from query string false "Desde (YYYY-MM-DD o RFC3339); por defecto 12 meses antes de to"
+// @Param to query string false "Hasta (YYYY-MM-DD incluye ese día, o RFC3339 exclusivo); por defecto hoy"
+// @Param segment query string false "Solo los clientes de este segmento"
+// @Success 200 {object} domain.RFMReport
+// @Failure 400 {object} map[string]string
+// @Router /api/report/rfm [get]
+func (h *Handlers) ReportRFM(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method != http.MethodGet {
 		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
 	}
+
+	desde, hasta, err := parseDateRange(r)
+	if err != nil {
+		writeJSON(w, 400, map[string]string{"error": "fecha inválida"})
+		return
+	}
+
+	report, err := h.SalesSvc.RFM(desde, hasta, r.URL.Query().Get("segment"))
+	if err != nil {
+		if err == domain.ErrInvalidInput {
+			writeJSON(w, 400, map[string]string{"error": "segment desconocido o from >= to"})
+			return
+		}
+		writeJSON(w, 500, map[string]string{"error": err.Error()})
+		return
+	}
+
+	writeJSON(w, 200, report)
 }
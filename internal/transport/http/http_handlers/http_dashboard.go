@@ -0,0 +1,51 @@
+package http_handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"ferreteria-inventario-ventas/internal/domain"
+)
+
+// Dashboard godoc
+// @Summary Tablero de la pantalla principal
+// @Description Ventas de hoy, de la semana (desde el lunes) y del mes, comparadas con el mismo tramo del período anterior,
+// @Description cantidad de productos con stock bajo y los más vendidos del mes, en una sola llamada.
+// @Description Las secciones se consultan en paralelo con un tiempo máximo cada una; si alguna falla queda en null
+// @Description y el motivo aparece en errores.
+// @Tags Report
+// @Produce json
+// @Param stock_lt query int false "Umbral de stock bajo (por defecto 5)"
+// @Success 200 {object} domain.Dashboard
+// @Failure 400 {object} map[string]string
+// @Router /api/dashboard [get]
+func (h *Handlers) Dashboard(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	lowStock := 0
+	if v := r.URL.Query().Get("stock_lt"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			writeJSON(w, 400, map[string]string{"error": "stock_lt debe ser un entero positivo"})
+			return
+		}
+		lowStock = n
+	}
+
+	// Con el contexto del request, si el cliente se va se cancelan las consultas
+	d, err := h.DashboardSvc.Dashboard(r.Context(), lowStock)
+	if err != nil {
+		if err == domain.ErrInvalidInput {
+			writeJSON(w, 400, map[string]string{"error": "stock_lt inválido"})
+			return
+		}
+		writeJSON(w, 500, map[string]string{"error": err.Error()})
+		return
+	}
+
+	writeJSON(w, 200, d)
+}
@@ -0,0 +1,286 @@
+package http_handlers
+
+import (
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"ferreteria-inventario-ventas/internal/domain"
+	"ferreteria-inventario-ventas/internal/tabular"
+)
+
+// Exportaciones: aceptan los mismos filtros que los listados y escriben
+// las filas a medida que salen de la base (sin paginar ni juntar en memoria).
+// Formatos: formato=csv (por defecto, UTF-8 con BOM), xlsx o ndjson.
+
+// exportStream arranca la respuesta recién con la primera fila, para que un
+// error de filtros todavía pueda responder con JSON.
+type exportStream struct {
+	w      http.ResponseWriter
+	format string
+	name   string
+	header []string
+	out    tabular.Writer
+}
+
+// newExportStream lee el formato pedido; devuelve nil si no es válido (ya respondió 400).
+func newExportStream(w http.ResponseWriter, r *http.Request, name string, header []string) *exportStream {
+
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return nil
+	}
+
+	format := strings.ToLower(r.URL.Query().Get("formato"))
+	if format == "" {
+		format = tabular.FormatCSV
+	}
+	if format != tabular.FormatCSV && format != tabular.FormatXLSX && format != tabular.FormatNDJSON {
+		writeJSON(w, 400, map[string]string{"error": "formato no soportado: use csv, xlsx o ndjson"})
+		return nil
+	}
+
+	return &exportStream{w: w, format: format, name: name, header: header}
+}
+
+func (e *exportStream) start() error {
+	filename := e.name + "-" + time.Now().Format("20060102") + "." + e.format
+
+	e.w.Header().Set("Content-Type", tabular.ContentType(e.format))
+	e.w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	e.w.WriteHeader(http.StatusOK)
+
+	out, err := tabular.NewWriter(e.format, e.w, e.header)
+	e.out = out
+	return err
+}
+
+// row escribe una fila con los valores en el orden de header.
+func (e *exportStream) row(values ...any) error {
+	if e.out == nil {
+		if err := e.start(); err != nil {
+			return err
+		}
+	}
+	return e.out.Write(values...)
+}
+
+// finish cierra el archivo o informa el error del recorrido.
+func (e *exportStream) finish(err error) {
+
+	if err != nil {
+		if e.out == nil {
+			writeError(e.w, err)
+			return
+		}
+		// Ya se enviaron filas: solo queda cortar la respuesta
+		log.Printf("exportación %s interrumpida: %v", e.name, err)
+		return
+	}
+
+	if e.out == nil {
+		if err := e.start(); err != nil {
+			log.Printf("exportación %s: %v", e.name, err)
+			return
+		}
+	}
+	if err := e.out.Close(); err != nil {
+		log.Printf("exportación %s: %v", e.name, err)
+	}
+}
+
+// ExportProducts godoc
+// @Summary Exportar productos
+// @Description Descarga el catálogo con los mismos filtros que GET /api/products. La columna codigos_barras usa ";" como separador, igual que la importación
+// @Tags Export
+// @Produce text/csv
+// @Param formato query string false "csv (por defecto), xlsx o ndjson"
+// @Param nombre query string false "Nombre contiene"
+// @Param stock_lt query int false "Stock menor a N"
+// @Param precio_min query number false "Precio mínimo"
+// @Param precio_max query number false "Precio máximo"
+// @Param category_id query int false "Categoría (incluye subcategorías)"
+// @Param brand_id query int false "Marca"
+// @Success 200 {file} file
+// @Router /api/export/products [get]
+func (h *Handlers) ExportProducts(w http.ResponseWriter, r *http.Request) {
+
+	e := newExportStream(w, r, "productos", []string{
+		"id", "sku", "nombre", "stock", "precio", "categoria", "marca", "codigos_barras",
+	})
+	if e == nil {
+		return
+	}
+
+	filter, err := parseProductFilter(r)
+	if err != nil {
+		e.finish(err)
+		return
+	}
+
+	e.finish(h.ProductsSvc.Export(filter, func(p domain.Product) error {
+		return e.row(p.ID, p.SKU, p.Nombre, p.Stock, p.Precio, p.Categoria, p.Marca, strings.Join(p.Barcodes, ";"))
+	}))
+}
+
+// ExportClients godoc
+// @Summary Exportar clientes
+// @Description Descarga los clientes con los mismos filtros que GET /api/clients
+// @Tags Export
+// @Produce text/csv
+// @Param formato query string false "csv (por defecto), xlsx o ndjson"
+// @Param nombre query string false "Nombre contiene"
+// @Param cedula query string false "Cédula empieza con"
+// @Param email query string false "Email contiene"
+// @Success 200 {file} file
+// @Router /api/export/clients [get]
+func (h *Handlers) ExportClients(w http.ResponseWriter, r *http.Request) {
+
+	e := newExportStream(w, r, "clientes", []string{"id", "nombre", "cedula", "email"})
+	if e == nil {
+		return
+	}
+
+	e.finish(h.ClientsSvc.Export(parseClientFilter(r), func(c domain.Client) error {
+		return e.row(c.ID, c.Nombre, c.Cedula, c.Email)
+	}))
+}
+
+// ExportSales godoc
+// @Summary Exportar ventas (cabeceras)
+// @Description Descarga una fila por venta con los mismos filtros que GET /api/sales
+// @Tags Export
+// @Produce text/csv
+// @Param formato query string false "csv (por defecto), xlsx o ndjson"
+// @Param client_id query int false "Cliente"
+// @Param from query string false "Desde (YYYY-MM-DD o RFC3339)"
+// @Param to query string false "Hasta (YYYY-MM-DD incluye ese día, o RFC3339 exclusivo)"
+// @Success 200 {file} file
+// @Router /api/export/sales [get]
+func (h *Handlers) ExportSales(w http.ResponseWriter, r *http.Request) {
+
+	e := newExportStream(w, r, "ventas", []string{"id", "fecha", "client_id", "cliente", "cajero", "descuento", "total"})
+	if e == nil {
+		return
+	}
+
+	filter, err := parseSaleFilter(r)
+	if err != nil {
+		e.finish(err)
+		return
+	}
+
+	e.finish(h.SalesSvc.Export(filter, func(s domain.Sale) error {
+		return e.row(s.ID, s.Fecha, s.ClientID, s.ClientName, s.Cajero, s.Descuento, s.Total)
+	}))
+}
+
+// ExportSaleItems godoc
+// @Summary Exportar líneas de venta
+// @Description Descarga una fila por producto vendido, con los datos de la venta, con los mismos filtros que GET /api/sales
+// @Tags Export
+// @Produce text/csv
+// @Param formato query string false "csv (por defecto), xlsx o ndjson"
+// @Param client_id query int false "Cliente"
+// @Param from query string false "Desde (YYYY-MM-DD o RFC3339)"
+// @Param to query string false "Hasta (YYYY-MM-DD incluye ese día, o RFC3339 exclusivo)"
+// @Success 200 {file} file
+// @Router /api/export/sale-items [get]
+func (h *Handlers) ExportSaleItems(w http.ResponseWriter, r *http.Request) {
+
+	e := newExportStream(w, r, "ventas-detalle", []string{
+		"sale_id", "fecha", "client_id", "cliente", "product_id", "sku", "producto",
+		"cantidad", "precio_unitario", "subtotal", "descuento",
+	})
+	if e == nil {
+		return
+	}
+
+	filter, err := parseSaleFilter(r)
+	if err != nil {
+		e.finish(err)
+		return
+	}
+
+	e.finish(h.SalesSvc.ExportLines(filter, func(l domain.SaleLine) error {
+		return e.row(l.SaleID, l.Fecha, l.ClientID, l.ClientName, l.ProductID, l.SKU, l.Producto,
+			l.Cantidad, l.PrecioUnitario, l.Subtotal, l.Descuento)
+	}))
+}
+
+// ExportInventoryValuation godoc
+// @Summary Exportar valorización del inventario
+// @Description Descarga una fila por producto con stock, con los mismos parámetros que /api/report/inventory-valuation
+// @Tags Export
+// @Produce text/csv
+// @Param formato query string false "csv (por defecto), xlsx o ndjson"
+// @Param as_of query string false "Fecha de corte: YYYY-MM-DD (al cierre de ese día) o RFC3339"
+// @Param category_id query int false "Categoría (incluye subcategorías)"
+// @Success 200 {file} file
+// @Router /api/export/inventory-valuation [get]
+func (h *Handlers) ExportInventoryValuation(w http.ResponseWriter, r *http.Request) {
+
+	e := newExportStream(w, r, "valorizacion", []string{
+		"product_id", "sku", "producto", "categoria", "cantidad",
+		"costo_unitario", "precio_unitario", "valor_costo", "valor_venta",
+	})
+	if e == nil {
+		return
+	}
+
+	asOf, categoryID, err := parseValuationQuery(r)
+	if err != nil {
+		e.finish(err)
+		return
+	}
+
+	e.finish(h.ProductsSvc.ExportValuation(asOf, categoryID, func(v domain.ValuationRow) error {
+		return e.row(v.ProductID, v.SKU, v.Producto, v.Categoria, v.Cantidad,
+			v.CostoUnitario, v.PrecioUnitario, v.ValorCosto, v.ValorVenta)
+	}))
+}
+
+// ExportRFM godoc
+// @Summary Exportar segmentación RFM
+// @Description Descarga una fila por cliente con los mismos parámetros que /api/report/rfm (para campañas de marketing)
+// @Tags Export
+// @Produce text/csv
+// @Param formato query string false "csv (por defecto), xlsx o ndjson"
+// @Param from query string false "Desde (YYYY-MM-DD o RFC3339)"
+// @Param to query string false "Hasta (YYYY-MM-DD incluye ese día, o RFC3339 exclusivo)"
+// @Param segment query string false "Solo los clientes de este segmento"
+// @Success 200 {file} file
+// @Router /api/export/rfm [get]
+func (h *Handlers) ExportRFM(w http.ResponseWriter, r *http.Request) {
+
+	e := newExportStream(w, r, "clientes-rfm", []string{
+		"client_id", "nombre", "cedula", "email", "ultima_compra", "recencia_dias",
+		"frecuencia", "monto", "r", "f", "m", "segmento",
+	})
+	if e == nil {
+		return
+	}
+
+	desde, hasta, err := parseDateRange(r)
+	if err != nil {
+		e.finish(err)
+		return
+	}
+
+	report, err := h.SalesSvc.RFM(desde, hasta, r.URL.Query().Get("segment"))
+	if err != nil {
+		e.finish(err)
+		return
+	}
+
+	for _, c := range report.Clientes {
+		err := e.row(c.ClientID, c.Nombre, c.Cedula, c.Email, c.UltimaCompra, c.Recencia,
+			c.Frecuencia, c.Monto, c.R, c.F, c.M, c.Segmento)
+		if err != nil {
+			e.finish(err)
+			return
+		}
+	}
+	e.finish(nil)
+}
@@ -0,0 +1,198 @@
+package http_handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"ferreteria-inventario-ventas/internal/domain"
+)
+
+// ReportInventoryValuation godoc
+// @Summary Valorización del inventario
+// @Description Cantidad, costo promedio, precio, valor al costo y valor a precio de venta de cada producto con stock,
+// @Description con subtotales por categoría y total general.
+// @Description Con as_of valoriza el stock a esa fecha, reconstruido desde las ventas y los movimientos de stock
+// @Description (el precio de venta es el actual).
+// @Tags Report
+// @Produce json
+// @Param as_of query string false "Fecha de corte: YYYY-MM-DD (al cierre de ese día) o RFC3339. Sin fecha = stock actual"
+// @Param category_id query int false "Categoría (incluye subcategorías)"
+// @Success 200 {object} domain.InventoryValuation
+// @Failure 400 {object} map[string]string
+// @Router /api/report/inventory-valuation [get]
+func (h *Handlers) ReportInventoryValuation(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	asOf, categoryID, err := parseValuationQuery(r)
+	if err != nil {
+		writeJSON(w, 400, map[string]string{"error": "as_of o category_id inválido"})
+		return
+	}
+
+	v, err := h.ProductsSvc.Valuation(asOf, categoryID)
+	if err != nil {
+		if err == domain.ErrInvalidInput {
+			writeJSON(w, 400, map[string]string{"error": "as_of no puede ser futura"})
+			return
+		}
+		writeJSON(w, 500, map[string]string{"error": err.Error()})
+		return
+	}
+
+	writeJSON(w, 200, v)
+}
+
+// parseValuationQuery lee as_of (una fecha sin hora es el cierre de ese día) y category_id.
+func parseValuationQuery(r *http.Request) (time.Time, int64, error) {
+	q := r.URL.Query()
+
+	asOf, err := parseDate(q.Get("as_of"), true)
+	if err != nil {
+		return asOf, 0, err
+	}
+	categoryID, err := optionalID(q.Get("category_id"))
+	return asOf, categoryID, err
+}
+
+// ReportABC godoc
+// @Summary Análisis ABC de productos
+// @Description Clasifica todos los productos por su aporte a la métrica en el rango (por defecto los últimos 12 meses):
+// @Description A hasta el 80 % acumulado, B hasta el 95 % y C el resto, incluidos los que no se vendieron.
+// @Description Devuelve el resumen por clase y cada producto con su participación y acumulado.
+// @Tags Report
+// @Produce json
+// @Param from query string false "Desde (YYYY-MM-DD o RFC3339); por defecto 12 meses antes de to"
+// @Param to query string false "Hasta (YYYY-MM-DD incluye ese día, o RFC3339 exclusivo); por defecto ahora"
+// @Param metric query string false "revenue (por defecto), units o margin"
+// @Param category_id query int false "Categoría (incluye subcategorías)"
+// @Param a query number false "% acumulado que cierra la clase A (por defecto 80)"
+// @Param b query number false "% acumulado que cierra la clase B (por defecto 95)"
+// @Success 200 {object} domain.ABCReport
+// @Failure 400 {object} map[string]string
+// @Router /api/report/abc [get]
+func (h *Handlers) ReportABC(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	abc, err := parseABCQuery(r)
+	if err != nil {
+		writeJSON(w, 400, map[string]string{"error": "parámetros inválidos"})
+		return
+	}
+
+	report, err := h.SalesSvc.ABC(abc)
+	if err != nil {
+		if err == domain.ErrInvalidInput {
+			writeJSON(w, 400, map[string]string{"error": "metric revenue, units o margin, 0 < a <= b <= 100 y from < to"})
+			return
+		}
+		writeJSON(w, 500, map[string]string{"error": err.Error()})
+		return
+	}
+
+	writeJSON(w, 200, report)
+}
+
+// ReportDeadStock godoc
+// @Summary Stock sin movimiento
+// @Description Productos con stock que no se venden desde la fecha de corte (por defecto hace 180 días),
+// @Description con su última venta y el capital inmovilizado al costo promedio. No incluye productos creados después del corte.
+// @Tags Report
+// @Produce json
+// @Param days query int false "Días sin ventas (por defecto 180)"
+// @Param since query string false "Fecha de corte (YYYY-MM-DD o RFC3339); reemplaza a days"
+// @Param category_id query int false "Categoría (incluye subcategorías)"
+// @Success 200 {object} domain.DeadStockReport
+// @Failure 400 {object} map[string]string
+// @Router /api/report/dead-stock [get]
+func (h *Handlers) ReportDeadStock(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	q := r.URL.Query()
+
+	since, err := parseDate(q.Get("since"), false)
+	if err == nil && since.IsZero() && q.Get("days") != "" {
+		var days int
+		days, err = strconv.Atoi(q.Get("days"))
+		if err != nil || days <= 0 {
+			err = domain.ErrInvalidInput
+		}
+		since = time.Now().AddDate(0, 0, -days)
+	}
+	var categoryID int64
+	if err == nil {
+		categoryID, err = optionalID(q.Get("category_id"))
+	}
+	if err != nil {
+		writeJSON(w, 400, map[string]string{"error": "days, since o category_id inválido"})
+		return
+	}
+
+	report, err := h.ProductsSvc.DeadStock(since, categoryID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, 200, report)
+}
+
+// ReportReorder godoc
+// @Summary Velocidad de venta y reposición
+// @Description Estima la demanda diaria de cada producto con el historial de ventas (días completos hasta ayer)
+// @Description por promedio móvil (sma) o suavizado exponencial (ses), opcionalmente con estacionalidad (season=7 para el patrón semanal).
+// @Description Devuelve días de cobertura con el stock actual, stock de seguridad para el nivel de servicio,
+// @Description punto de reorden y cantidad sugerida a pedir para cubrir el plazo de entrega más cover_days.
+// @Description Ordenado por días de cobertura (los que se agotan antes primero).
+// @Tags Report
+// @Produce json
+// @Param days query int false "Días de historial (por defecto 90, máximo 730)"
+// @Param method query string false "sma (por defecto) o ses"
+// @Param window query int false "Días del promedio móvil (por defecto 28)"
+// @Param alpha query number false "Factor de suavizado de ses, 0 a 1 (por defecto 0.3)"
+// @Param season query int false "Largo de la temporada en días (7 = semana); sin valor no hay estacionalidad"
+// @Param lead_time query int false "Plazo de entrega del proveedor en días (por defecto 7)"
+// @Param cover_days query int false "Días de demanda a cubrir además del plazo (por defecto 30)"
+// @Param service_level query number false "Nivel de servicio para el stock de seguridad, 0.5 a 0.999 (por defecto 0.95)"
+// @Param category_id query int false "Categoría (incluye subcategorías)"
+// @Param only_reorder query bool false "Solo productos a reponer"
+// @Success 200 {object} domain.ReorderReport
+// @Failure 400 {object} map[string]string
+// @Router /api/report/reorder [get]
+func (h *Handlers) ReportReorder(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	q, err := parseReorderQuery(r)
+	if err != nil {
+		writeJSON(w, 400, map[string]string{"error": "parámetros inválidos"})
+		return
+	}
+
+	report, err := h.SalesSvc.Reorder(q)
+	if err != nil {
+		if err == domain.ErrInvalidInput {
+			writeJSON(w, 400, map[string]string{"error": "days 1-730, method sma o ses, window <= days, alpha 0-1, season <= days/2, lead_time y cover_days hasta 365, service_level 0.5-0.999"})
+			return
+		}
+		writeJSON(w, 500, map[string]string{"error": err.Error()})
+		return
+	}
+
+	writeJSON(w, 200, report)
+}
@@ -0,0 +1,106 @@
+package http_handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"ferreteria-inventario-ventas/internal/domain"
+)
+
+// PriceLists godoc
+// @Summary Listas de precios
+// @Description GET lista todas con sus reglas, GET /api/price-lists/{id} devuelve una, POST crea, PUT /api/price-lists/{id} reemplaza nombre, descripción y reglas,
+// @Description DELETE /api/price-lists/{id} elimina una lista sin clientes asignados ni ventas (409 si se usó).
+// @Description Cada regla aplica a un producto (product_id), una categoría con sus subcategorías (category_id) o a todos los productos (ninguno),
+// @Description desde cantidad_min unidades de la línea, con precio fijo (solo por producto) o porcentaje sobre el precio del producto (negativo = descuento).
+// @Description Al vender gana la regla más específica (producto, categoría más cercana, general) y dentro de ella la escala más alta alcanzada.
+// @Tags PriceLists
+// @Accept json
+// @Produce json
+// @Param list body domain.PriceList false "Lista (POST/PUT)"
+// @Success 200 {array} domain.PriceList
+// @Success 201 {object} domain.PriceList
+// @Router /api/price-lists [get]
+// @Router /api/price-lists [post]
+// @Router /api/price-lists/{id} [get]
+// @Router /api/price-lists/{id} [put]
+// @Router /api/price-lists/{id} [delete]
+func (h *Handlers) PriceLists(w http.ResponseWriter, r *http.Request) {
+
+	id, err := pathID(r, "/api/price-lists")
+	if err != nil {
+		writeJSON(w, 400, map[string]string{"error": "id inválido"})
+		return
+	}
+
+	switch {
+
+	case r.Method == http.MethodGet && id > 0:
+		l, err := h.PriceListsSvc.Get(id)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, 200, l)
+
+	case r.Method == http.MethodGet:
+		list, err := h.PriceListsSvc.List()
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, 200, list)
+
+	case r.Method == http.MethodPost && id == 0:
+		var input domain.PriceList
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			writeJSON(w, 400, map[string]string{"error": "JSON inválido"})
+			return
+		}
+		if err := h.PriceListsSvc.Create(r.Context(), &input); err != nil {
+			writePriceListError(w, err)
+			return
+		}
+		writeJSON(w, 201, input)
+
+	case r.Method == http.MethodPut && id > 0:
+		var input domain.PriceList
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			writeJSON(w, 400, map[string]string{"error": "JSON inválido"})
+			return
+		}
+		if err := h.PriceListsSvc.Update(r.Context(), id, &input); err != nil {
+			writePriceListError(w, err)
+			return
+		}
+		writeJSON(w, 200, input)
+
+	case r.Method == http.MethodDelete && id > 0:
+		err := h.PriceListsSvc.Delete(r.Context(), id)
+		switch err {
+		case nil:
+			writeJSON(w, 200, map[string]string{"deleted": "ok"})
+		case domain.ErrConflict:
+			writeJSON(w, 409, map[string]string{"error": "la lista tiene clientes asignados o ventas"})
+		default:
+			writeError(w, err)
+		}
+
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// writePriceListError responde los errores al crear o modificar una lista.
+func writePriceListError(w http.ResponseWriter, err error) {
+	switch err {
+	case domain.ErrInvalidInput:
+		writeJSON(w, 400, map[string]string{"error": "nombre requerido; cada regla con product_id o category_id (o ninguno), cantidad_min >= 1 y precio > 0 (solo por producto) o porcentaje (distinto de 0, mayor a -100 y hasta 1000)"})
+	case domain.ErrConflict:
+		writeJSON(w, 409, map[string]string{"error": "nombre de lista repetido o regla repetida (mismo producto o categoría y cantidad_min)"})
+	case domain.ErrNotFound:
+		writeJSON(w, 404, map[string]string{"error": "lista, producto o categoría no encontrados"})
+	default:
+		writeJSON(w, 500, map[string]string{"error": err.Error()})
+	}
+}
@@ -0,0 +1,234 @@
+package http_handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"ferreteria-inventario-ventas/internal/domain"
+)
+
+// ProductPrices godoc
+// @Summary Historial de precios de un producto
+// @Description Sin at devuelve el historial (más reciente primero) y los cambios programados pendientes.
+// @Description Con at devuelve el precio que regía en esa fecha (una fecha sin hora toma el precio al final de ese día).
+// @Tags Prices
+// @Produce json
+// @Param id path int true "ID del producto"
+// @Param at query string false "Fecha (YYYY-MM-DD o RFC3339)"
+// @Success 200 {object} domain.PriceHistory
+// @Success 200 {object} domain.PriceAt
+// @Router /api/products/{id}/prices [get]
+func (h *Handlers) ProductPrices(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	idStr := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/products/"), "/prices")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || id <= 0 {
+		writeJSON(w, 400, map[string]string{"error": "id inválido"})
+		return
+	}
+
+	v := r.URL.Query().Get("at")
+	if v == "" {
+		history, err := h.PricesSvc.History(id)
+		if err != nil {
+			if err == domain.ErrNotFound {
+				writeJSON(w, 404, map[string]string{"error": "producto no encontrado"})
+				return
+			}
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, 200, history)
+		return
+	}
+
+	at, err := parseDate(v, true)
+	if err != nil {
+		writeJSON(w, 400, map[string]string{"error": "fecha inválida"})
+		return
+	}
+	if _, dateErr := time.Parse(time.DateOnly, v); dateErr == nil {
+		// Último instante del día (parseDate devuelve el inicio del siguiente)
+		at = at.Add(-time.Second)
+	}
+
+	price, err := h.PricesSvc.PriceAt(id, at)
+	if err != nil {
+		if err == domain.ErrNotFound {
+			writeJSON(w, 404, map[string]string{"error": "producto no encontrado o sin precio en esa fecha"})
+			return
+		}
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, 200, price)
+}
+
+// priceChangeInput es el cuerpo para programar un cambio (la fecha acepta YYYY-MM-DD).
+type priceChangeInput struct {
+	ProductID  int64    `json:"product_id"`
+	Precio     *float64 `json:"precio"`
+	Porcentaje *float64 `json:"porcentaje"`
+	Desde      string   `json:"desde"` // YYYY-MM-DD (inicio del día) o RFC3339; vacío = ya
+}
+
+// bulkPriceInput es el cuerpo de un aumento masivo.
+type bulkPriceInput struct {
+	CategoryID int64   `json:"category_id"`
+	Proveedor  string  `json:"proveedor"`
+	Porcentaje float64 `json:"porcentaje"`
+	Desde      string  `json:"desde"` // YYYY-MM-DD (inicio del día) o RFC3339; vacío = ya
+}
+
+// PriceChanges godoc
+// @Summary Cambios de precio programados
+// @Description GET lista los cambios (por defecto los más recientes primero), GET /api/price-changes/{id} devuelve uno,
+// @Description POST programa un precio fijo o un porcentaje para un producto desde una fecha (vacía o pasada: se aplica ya),
+// @Description DELETE /api/price-changes/{id} cancela un cambio pendiente (409 si ya se aplicó o canceló).
+// @Description Los cambios pendientes se aplican solos cada minuto; el historial registra el momento en que se aplicaron.
+// @Tags Prices
+// @Accept json
+// @Produce json
+// @Param product_id query int false "Cambios de un producto"
+// @Param estado query string false "pendiente, aplicado o cancelado"
+// @Param limit query int false "Filas por página (por defecto 50, máximo 500)"
+// @Param cursor query string false "Cursor devuelto en next_cursor"
+// @Param sort query string false "Campo de orden: id, desde"
+// @Param order query string false "asc o desc"
+// @Param change body priceChangeInput false "Cambio (solo POST): precio o porcentaje"
+// @Success 200 {object} domain.Page[domain.PriceChange]
+// @Success 201 {object} domain.PriceChange
+// @Router /api/price-changes [get]
+// @Router /api/price-changes [post]
+// @Router /api/price-changes/{id} [get]
+// @Router /api/price-changes/{id} [delete]
+func (h *Handlers) PriceChanges(w http.ResponseWriter, r *http.Request) {
+
+	id, err := pathID(r, "/api/price-changes")
+	if err != nil {
+		writeJSON(w, 400, map[string]string{"error": "id inválido"})
+		return
+	}
+
+	switch {
+
+	case r.Method == http.MethodGet && id > 0:
+		c, err := h.PricesSvc.Get(id)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, 200, c)
+
+	case r.Method == http.MethodGet:
+		q := r.URL.Query()
+		f := domain.PriceChangeFilter{Estado: q.Get("estado")}
+		if f.ProductID, err = optionalID(q.Get("product_id")); err != nil {
+			writeJSON(w, 400, map[string]string{"error": "filtro inválido"})
+			return
+		}
+		page, err := parsePage(r)
+		if err != nil {
+			writeJSON(w, 400, map[string]string{"error": "paginación inválida"})
+			return
+		}
+
+		list, err := h.PricesSvc.List(f, page)
+		if err != nil {
+			writeListError(w, err)
+			return
+		}
+		writeJSON(w, 200, list)
+
+	case r.Method == http.MethodPost && id == 0:
+		var input priceChangeInput
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			writeJSON(w, 400, map[string]string{"error": "JSON inválido"})
+			return
+		}
+		c := domain.PriceChange{ProductID: input.ProductID, Precio: input.Precio, Porcentaje: input.Porcentaje}
+		if c.Desde, err = parseDate(input.Desde, false); err != nil {
+			writeJSON(w, 400, map[string]string{"error": "desde inválido"})
+			return
+		}
+
+		err := h.PricesSvc.Schedule(r.Context(), &c)
+		switch err {
+		case nil:
+			writeJSON(w, 201, c)
+		case domain.ErrInvalidInput:
+			writeJSON(w, 400, map[string]string{"error": "product_id y precio > 0 o porcentaje (distinto de 0, mayor a -100 y hasta 1000), no los dos"})
+		case domain.ErrNotFound:
+			writeJSON(w, 404, map[string]string{"error": "producto no encontrado"})
+		default:
+			writeJSON(w, 500, map[string]string{"error": err.Error()})
+		}
+
+	case r.Method == http.MethodDelete && id > 0:
+		err := h.PricesSvc.Cancel(r.Context(), id)
+		switch err {
+		case nil:
+			w.WriteHeader(http.StatusNoContent)
+		case domain.ErrNotFound:
+			writeJSON(w, 404, map[string]string{"error": "cambio no encontrado"})
+		case domain.ErrConflict:
+			writeJSON(w, 409, map[string]string{"error": "el cambio ya se aplicó o se canceló"})
+		default:
+			writeError(w, err)
+		}
+
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// PriceChangesBulk godoc
+// @Summary Aumento masivo de precios
+// @Description Programa un porcentaje (negativo para rebajar) para todos los productos de una categoría (incluye subcategorías)
+// @Description o de un proveedor (productos recibidos de él en alguna recepción), uno de los dos. El porcentaje se calcula
+// @Description sobre el precio que tenga cada producto al aplicarse y se redondea a centavos. Sin desde, o con una fecha pasada, se aplica ya.
+// @Tags Prices
+// @Accept json
+// @Produce json
+// @Param bulk body bulkPriceInput true "Aumento masivo"
+// @Success 201 {object} domain.BulkPriceResult
+// @Router /api/price-changes/bulk [post]
+func (h *Handlers) PriceChangesBulk(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var input bulkPriceInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeJSON(w, 400, map[string]string{"error": "JSON inválido"})
+		return
+	}
+	b := domain.BulkPriceChange{CategoryID: input.CategoryID, Proveedor: input.Proveedor, Porcentaje: input.Porcentaje}
+	var err error
+	if b.Desde, err = parseDate(input.Desde, false); err != nil {
+		writeJSON(w, 400, map[string]string{"error": "desde inválido"})
+		return
+	}
+
+	result, err := h.PricesSvc.Bulk(r.Context(), b)
+	switch err {
+	case nil:
+		writeJSON(w, 201, result)
+	case domain.ErrInvalidInput:
+		writeJSON(w, 400, map[string]string{"error": "category_id o proveedor (uno de los dos) y porcentaje distinto de 0, mayor a -100 y hasta 1000"})
+	case domain.ErrNotFound:
+		writeJSON(w, 404, map[string]string{"error": "ningún producto de esa categoría o proveedor"})
+	default:
+		writeJSON(w, 500, map[string]string{"error": err.Error()})
+	}
+}
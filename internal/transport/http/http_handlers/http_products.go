@@ -2,25 +2,40 @@ package http_handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"strconv"
 	"strings"
 
 	"ferreteria-inventario-ventas/internal/domain"
+	"ferreteria-inventario-ventas/internal/tabular"
 )
 
 // Products maneja:
-// GET  -> listar productos
-// POST -> crear producto
+// GET    -> listar productos (o uno por ID en /api/products/{id})
+// POST   -> crear producto
+// PUT    -> actualizar producto
+// DELETE -> eliminar producto
 
 // Products godoc
 // @Summary Listar o crear productos
-// @Description GET lista productos, POST crea producto
+// @Description GET lista productos paginados con filtros, POST crea producto
 // @Tags Products
 // @Accept json
 // @Produce json
+// @Param limit query int false "Filas por página (por defecto 50, máximo 500)"
+// @Param cursor query string false "Cursor devuelto en next_cursor"
+// @Param sort query string false "Campo de orden: id, nombre, stock, precio"
+// @Param order query string false "asc o desc"
+// @Param nombre query string false "Nombre contiene"
+// @Param stock_lt query int false "Stock menor a N"
+// @Param precio_min query number false "Precio mínimo"
+// @Param precio_max query number false "Precio máximo"
+// @Param category_id query int false "Categoría (incluye subcategorías)"
+// @Param brand_id query int false "Marca"
 // @Param product body domain.Product false "Producto (solo POST)"
-// @Success 200 {array} domain.Product
+// @Success 200 {object} domain.Page[domain.Product]
 // @Success 201 {object} domain.Product
 // @Router /api/products [get]
 // @Router /api/products [post]
@@ -35,7 +50,11 @@ func (h *Handlers) Products(w http.ResponseWriter, r *http.Request) {
 		var input domain.Product
 		json.NewDecoder(r.Body).Decode(&input)
 
-		err := h.ProductsSvc.Update(id, &input)
+		err := h.ProductsSvc.Update(r.Context(), id, &input)
+		if err == domain.ErrConflict {
+			writeJSON(w, 409, map[string]string{"error": "nombre, sku o código de barras repetido"})
+			return
+		}
 		if err != nil {
 			writeJSON(w, 400, map[string]string{"error": err.Error()})
 			return
@@ -46,7 +65,11 @@ func (h *Handlers) Products(w http.ResponseWriter, r *http.Request) {
 		idStr := strings.TrimPrefix(r.URL.Path, "/api/products/")
 		id, _ := strconv.ParseInt(idStr, 10, 64)
 
-		err := h.ProductsSvc.Delete(id)
+		err := h.ProductsSvc.Delete(r.Context(), id)
+		if err == domain.ErrNotFound {
+			writeJSON(w, 404, map[string]string{"error": "producto no encontrado"})
+			return
+		}
 		if err != nil {
 			writeJSON(w, 400, map[string]string{"error": err.Error()})
 			return
@@ -54,9 +77,33 @@ func (h *Handlers) Products(w http.ResponseWriter, r *http.Request) {
 		writeJSON(w, 200, map[string]string{"deleted": "ok"})
 
 	case http.MethodGet:
-		list, err := h.ProductsSvc.List()
+		if strings.HasSuffix(r.URL.Path, "/recommendations") {
+			h.ProductRecommendations(w, r)
+			return
+		}
+		if strings.HasSuffix(r.URL.Path, "/prices") {
+			h.ProductPrices(w, r)
+			return
+		}
+		if idStr := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/products"), "/"); idStr != "" {
+			h.productByID(w, idStr)
+			return
+		}
+
+		filter, err := parseProductFilter(r)
 		if err != nil {
-			writeJSON(w, 500, map[string]string{"error": err.Error()})
+			writeJSON(w, 400, map[string]string{"error": "filtro inválido"})
+			return
+		}
+		page, err := parsePage(r)
+		if err != nil {
+			writeJSON(w, 400, map[string]string{"error": "paginación inválida"})
+			return
+		}
+
+		list, err := h.ProductsSvc.List(filter, page)
+		if err != nil {
+			writeListError(w, err)
 			return
 		}
 		writeJSON(w, 200, list)
@@ -70,7 +117,11 @@ func (h *Handlers) Products(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		err = h.ProductsSvc.Create(&input)
+		err = h.ProductsSvc.Create(r.Context(), &input)
+		if err == domain.ErrConflict {
+			writeJSON(w, 409, map[string]string{"error": "nombre, sku o código de barras repetido"})
+			return
+		}
 		if err != nil {
 			writeJSON(w, 400, map[string]string{"error": err.Error()})
 			return
@@ -82,3 +133,282 @@ func (h *Handlers) Products(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusMethodNotAllowed)
 	}
 }
+
+// ProductSearch godoc
+// @Summary Buscar productos
+// @Description Búsqueda de texto completo por nombre: ignora tildes y mayúsculas, cada palabra se busca como prefijo y los resultados vienen ordenados por relevancia
+// @Tags Products
+// @Produce json
+// @Param q query string true "Texto a buscar (ej: tornillo autoperforante 1/2)"
+// @Param limit query int false "Máximo de resultados (por defecto 20, máximo 100)"
+// @Success 200 {array} domain.Product
+// @Router /api/products/search [get]
+func (h *Handlers) ProductSearch(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	limit := 0
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			writeJSON(w, 400, map[string]string{"error": "limit inválido"})
+			return
+		}
+		limit = n
+	}
+
+	list, err := h.ProductsSvc.Search(r.URL.Query().Get("q"), limit)
+	if err != nil {
+		if err == domain.ErrInvalidInput {
+			writeJSON(w, 400, map[string]string{"error": "q requerido"})
+			return
+		}
+		writeJSON(w, 500, map[string]string{"error": err.Error()})
+		return
+	}
+
+	writeJSON(w, 200, list)
+}
+
+// productByID responde GET /api/products/{id}.
+func (h *Handlers) productByID(w http.ResponseWriter, idStr string) {
+
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || id <= 0 {
+		writeJSON(w, 400, map[string]string{"error": "id inválido"})
+		return
+	}
+
+	p, err := h.ProductsSvc.Get(id)
+	if err != nil {
+		switch err {
+		case domain.ErrNotFound:
+			writeJSON(w, 404, map[string]string{"error": "producto no encontrado"})
+		default:
+			writeJSON(w, 500, map[string]string{"error": err.Error()})
+		}
+		return
+	}
+
+	writeJSON(w, 200, p)
+}
+
+// ProductRecommendations godoc
+// @Summary Productos que se compran juntos
+// @Description Sugerencias para la pantalla de ventas: productos que aparecen en las mismas ventas que el producto
+// @Description (último año, recalculado cada día) más de lo esperado por azar (lift > 1), ordenados por confianza.
+// @Tags Products
+// @Produce json
+// @Param id path int true "ID del producto"
+// @Param limit query int false "Máximo de sugerencias (por defecto 5, máximo 20)"
+// @Param in_stock query bool false "Solo productos con stock (por defecto true)"
+// @Success 200 {array} domain.Recommendation
+// @Failure 404 {object} map[string]string
+// @Router /api/products/{id}/recommendations [get]
+func (h *Handlers) ProductRecommendations(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	idStr := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/products/"), "/recommendations")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || id <= 0 {
+		writeJSON(w, 400, map[string]string{"error": "id inválido"})
+		return
+	}
+
+	q := r.URL.Query()
+
+	limit := 0
+	if v := q.Get("limit"); v != "" {
+		if limit, err = strconv.Atoi(v); err != nil {
+			writeJSON(w, 400, map[string]string{"error": "limit inválido"})
+			return
+		}
+	}
+
+	list, err := h.AssociationsSvc.Recommendations(id, limit, q.Get("in_stock") != "false")
+	if err != nil {
+		switch err {
+		case domain.ErrInvalidInput:
+			writeJSON(w, 400, map[string]string{"error": "limit 1-20"})
+		case domain.ErrNotFound:
+			writeJSON(w, 404, map[string]string{"error": "producto no encontrado"})
+		default:
+			writeJSON(w, 500, map[string]string{"error": err.Error()})
+		}
+		return
+	}
+
+	writeJSON(w, 200, list)
+}
+
+// ProductByBarcode godoc
+// @Summary Buscar producto por código de barras
+// @Description Devuelve el producto de un código EAN-13, UPC-A o EAN-8 (pensado para lectores USB en la pantalla de ventas)
+// @Tags Products
+// @Produce json
+// @Param code path string true "Código de barras"
+// @Success 200 {object} domain.Product
+// @Router /api/products/by-barcode/{code} [get]
+func (h *Handlers) ProductByBarcode(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	code := strings.TrimPrefix(r.URL.Path, "/api/products/by-barcode/")
+
+	p, err := h.ProductsSvc.GetByBarcode(code)
+	if err != nil {
+		switch err {
+		case domain.ErrInvalidInput:
+			writeJSON(w, 400, map[string]string{"error": "código de barras inválido"})
+		case domain.ErrNotFound:
+			writeJSON(w, 404, map[string]string{"error": "código no asignado a ningún producto"})
+		default:
+			writeJSON(w, 500, map[string]string{"error": err.Error()})
+		}
+		return
+	}
+
+	writeJSON(w, 200, p)
+}
+
+// ProductBarcodes godoc
+// @Summary Asignar códigos de barras en bloque
+// @Description Asigna códigos a productos (por product_id o sku). Valida el dígito verificador; si alguna fila falla no se asigna ninguna y se devuelven los errores por fila
+// @Tags Products
+// @Accept json
+// @Produce json
+// @Param barcodes body []domain.BarcodeAssignment true "Asignaciones"
+// @Success 200 {object} map[string]int
+// @Failure 422 {object} map[string]interface{}
+// @Router /api/products/barcodes [post]
+func (h *Handlers) ProductBarcodes(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var input []domain.BarcodeAssignment
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeJSON(w, 400, map[string]string{"error": "JSON inválido"})
+		return
+	}
+
+	rowErrors, err := h.ProductsSvc.AssignBarcodes(r.Context(), input)
+	if len(rowErrors) > 0 {
+		writeJSON(w, 422, map[string]interface{}{
+			"error": "hay filas con errores, no se asignó ningún código",
+			"filas": rowErrors,
+		})
+		return
+	}
+	if err != nil {
+		if err == domain.ErrInvalidInput {
+			writeJSON(w, 400, map[string]string{"error": "lista vacía"})
+			return
+		}
+		writeJSON(w, 500, map[string]string{"error": err.Error()})
+		return
+	}
+
+	writeJSON(w, 200, map[string]int{"asignados": len(input)})
+}
+
+// maxImportSize es el tamaño máximo del archivo de importación.
+const maxImportSize = 20 << 20
+
+// ProductImport godoc
+// @Summary Importar productos desde CSV o XLSX
+// @Description Crea o actualiza productos (por sku y si no por nombre). Las columnas se reconocen por la cabecera: nombre, sku, stock, precio, codigo_barras, categoria, marca. Con dry_run=true solo valida; sin él guarda todas las filas o ninguna. El archivo va en el campo "archivo" (multipart) o como cuerpo con Content-Type text/csv o de XLSX
+// @Tags Products
+// @Accept multipart/form-data
+// @Produce json
+// @Param archivo formData file false "Archivo .csv o .xlsx"
+// @Param dry_run query bool false "Solo validar, sin guardar"
+// @Param formato query string false "csv o xlsx (si no se deduce del archivo)"
+// @Success 200 {object} domain.ImportReport
+// @Failure 422 {object} domain.ImportReport
+// @Router /api/products/import [post]
+func (h *Handlers) ProductImport(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+
+	data, format, err := readImportFile(r)
+	if err != nil {
+		writeJSON(w, 400, map[string]string{"error": err.Error()})
+		return
+	}
+
+	table, err := tabular.Read(format, data)
+	if err != nil {
+		writeJSON(w, 400, map[string]string{"error": "no se pudo leer el archivo: " + err.Error()})
+		return
+	}
+
+	dryRun, _ := strconv.ParseBool(r.URL.Query().Get("dry_run"))
+
+	report, err := h.ProductsSvc.Import(r.Context(), table, dryRun)
+	if err != nil {
+		if err == domain.ErrInvalidInput {
+			writeJSON(w, 400, map[string]string{"error": "archivo vacío"})
+			return
+		}
+		writeJSON(w, 500, map[string]string{"error": err.Error()})
+		return
+	}
+
+	if len(report.Errores) > 0 {
+		writeJSON(w, 422, report)
+		return
+	}
+	writeJSON(w, 200, report)
+}
+
+// readImportFile lee el archivo del campo "archivo" (multipart) o del cuerpo
+// y deduce el formato por el parámetro formato, el nombre o el Content-Type.
+func readImportFile(r *http.Request) ([]byte, string, error) {
+
+	format := strings.ToLower(r.URL.Query().Get("formato"))
+	body := io.Reader(r.Body)
+
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		file, header, err := r.FormFile("archivo")
+		if err != nil {
+			return nil, "", errors.New("falta el campo archivo")
+		}
+		defer file.Close()
+
+		body = file
+		if format == "" {
+			format = tabular.FormatFromName(header.Filename)
+		}
+	} else if format == "" {
+		format = tabular.FormatFromContentType(r.Header.Get("Content-Type"))
+	}
+
+	if format != tabular.FormatCSV && format != tabular.FormatXLSX {
+		return nil, "", errors.New("formato no soportado: use csv o xlsx")
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, "", errors.New("archivo demasiado grande o incompleto")
+	}
+
+	return data, format, nil
+}
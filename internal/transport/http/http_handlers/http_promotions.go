@@ -0,0 +1,107 @@
+package http_handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"ferreteria-inventario-ventas/internal/domain"
+)
+
+// Promotions godoc
+// @Summary Promociones
+// @Description GET lista todas (las más recientes primero; vigentes=true solo las que se aplicarían a una venta ahora), GET /api/promotions/{id} devuelve una,
+// @Description POST crea, PUT /api/promotions/{id} reemplaza, DELETE /api/promotions/{id} elimina una promoción sin ventas (409 si ya hizo descuentos: desactivarla con activa=false).
+// @Description tipo: porcentaje (descuento por línea desde cantidad_min unidades), nxm (lleva compra + gratis y paga compra, por línea)
+// @Description o monto (descuento fijo sobre la venta si los productos alcanzados suman monto_min). Rige desde `desde` hasta `hasta` (exclusivo, opcional).
+// @Description objetivos: productos, categorías (con subcategorías) o marcas; sin objetivos alcanza a todo. El producto debe cumplir cada tipo indicado.
+// @Description Se evalúan al crear la venta de mayor a menor prioridad; una promoción no acumulable no se suma a otros descuentos.
+// @Tags Promotions
+// @Accept json
+// @Produce json
+// @Param vigentes query bool false "Solo vigentes"
+// @Param promotion body domain.Promotion false "Promoción (POST/PUT)"
+// @Success 200 {array} domain.Promotion
+// @Success 201 {object} domain.Promotion
+// @Router /api/promotions [get]
+// @Router /api/promotions [post]
+// @Router /api/promotions/{id} [get]
+// @Router /api/promotions/{id} [put]
+// @Router /api/promotions/{id} [delete]
+func (h *Handlers) Promotions(w http.ResponseWriter, r *http.Request) {
+
+	id, err := pathID(r, "/api/promotions")
+	if err != nil {
+		writeJSON(w, 400, map[string]string{"error": "id inválido"})
+		return
+	}
+
+	switch {
+
+	case r.Method == http.MethodGet && id > 0:
+		p, err := h.PromotionsSvc.Get(id)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, 200, p)
+
+	case r.Method == http.MethodGet:
+		filter := domain.PromotionFilter{Vigentes: r.URL.Query().Get("vigentes") == "true"}
+		list, err := h.PromotionsSvc.List(filter)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, 200, list)
+
+	case r.Method == http.MethodPost && id == 0:
+		input := domain.Promotion{Activa: true} // Sin "activa" queda activa
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			writeJSON(w, 400, map[string]string{"error": "JSON inválido"})
+			return
+		}
+		if err := h.PromotionsSvc.Create(r.Context(), &input); err != nil {
+			writePromotionError(w, err)
+			return
+		}
+		writeJSON(w, 201, input)
+
+	case r.Method == http.MethodPut && id > 0:
+		input := domain.Promotion{Activa: true} // Sin "activa" queda activa
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			writeJSON(w, 400, map[string]string{"error": "JSON inválido"})
+			return
+		}
+		if err := h.PromotionsSvc.Update(r.Context(), id, &input); err != nil {
+			writePromotionError(w, err)
+			return
+		}
+		writeJSON(w, 200, input)
+
+	case r.Method == http.MethodDelete && id > 0:
+		err := h.PromotionsSvc.Delete(r.Context(), id)
+		switch err {
+		case nil:
+			writeJSON(w, 200, map[string]string{"deleted": "ok"})
+		case domain.ErrConflict:
+			writeJSON(w, 409, map[string]string{"error": "la promoción ya se aplicó en ventas; desactivarla con activa=false"})
+		default:
+			writeError(w, err)
+		}
+
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// writePromotionError responde los errores al crear o modificar una promoción.
+func writePromotionError(w http.ResponseWriter, err error) {
+	switch err {
+	case domain.ErrInvalidInput:
+		writeJSON(w, 400, map[string]string{"error": "nombre requerido; tipo porcentaje (0 a 100), nxm (compra y gratis >= 1) o monto (> 0); hasta posterior a desde; objetivos de tipo product, category o brand"})
+	case domain.ErrNotFound:
+		writeJSON(w, 404, map[string]string{"error": "promoción, producto, categoría o marca no encontrados"})
+	default:
+		writeJSON(w, 500, map[string]string{"error": err.Error()})
+	}
+}
@@ -0,0 +1,279 @@
+package http_handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"ferreteria-inventario-ventas/internal/domain"
+)
+
+// Funciones auxiliares para leer parámetros de la query string.
+// Todas devuelven domain.ErrInvalidInput si el valor no se puede interpretar.
+
+// parsePage lee limit, cursor, sort y order.
+// Sin sort se ordena por id descendente (lo más reciente primero).
+func parsePage(r *http.Request) (domain.PageParams, error) {
+	q := r.URL.Query()
+
+	p := domain.PageParams{
+		Cursor: q.Get("cursor"),
+		Sort:   q.Get("sort"),
+	}
+
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return p, domain.ErrInvalidInput
+		}
+		p.Limit = n
+	}
+
+	order := strings.ToLower(q.Get("order"))
+	if p.Sort == "" {
+		p.Sort = "id"
+		if order == "" {
+			order = "desc"
+		}
+	}
+
+	switch order {
+	case "", "asc":
+		p.Desc = false
+	case "desc":
+		p.Desc = true
+	default:
+		return p, domain.ErrInvalidInput
+	}
+
+	return p, nil
+}
+
+// parseProductFilter lee nombre, stock_lt, precio_min, precio_max, category_id y brand_id.
+func parseProductFilter(r *http.Request) (domain.ProductFilter, error) {
+	q := r.URL.Query()
+
+	f := domain.ProductFilter{Nombre: strings.TrimSpace(q.Get("nombre"))}
+
+	if v := q.Get("stock_lt"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return f, domain.ErrInvalidInput
+		}
+		f.StockLT = &n
+	}
+
+	var err error
+	if f.CategoryID, err = optionalID(q.Get("category_id")); err != nil {
+		return f, err
+	}
+	if f.BrandID, err = optionalID(q.Get("brand_id")); err != nil {
+		return f, err
+	}
+	if f.PrecioMin, err = optionalFloat(q.Get("precio_min")); err != nil {
+		return f, err
+	}
+	if f.PrecioMax, err = optionalFloat(q.Get("precio_max")); err != nil {
+		return f, err
+	}
+
+	return f, nil
+}
+
+// parseClientFilter lee nombre, cedula y email.
+func parseClientFilter(r *http.Request) domain.ClientFilter {
+	q := r.URL.Query()
+
+	return domain.ClientFilter{
+		Nombre: strings.TrimSpace(q.Get("nombre")),
+		Cedula: strings.TrimSpace(q.Get("cedula")),
+		Email:  strings.TrimSpace(q.Get("email")),
+	}
+}
+
+// parseSaleFilter lee client_id, from y to.
+func parseSaleFilter(r *http.Request) (domain.SaleFilter, error) {
+	q := r.URL.Query()
+
+	var f domain.SaleFilter
+
+	var err error
+	if f.ClientID, err = optionalID(q.Get("client_id")); err != nil {
+		return f, err
+	}
+	if f.Desde, f.Hasta, err = parseDateRange(r); err != nil {
+		return f, err
+	}
+
+	return f, nil
+}
+
+// parseTopProductsQuery lee limit, metric, order, category_id, from y to.
+func parseTopProductsQuery(r *http.Request) (domain.TopProductsQuery, error) {
+	q := r.URL.Query()
+
+	t := domain.TopProductsQuery{Metric: q.Get("metric")}
+
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return t, domain.ErrInvalidInput
+		}
+		t.Limit = n
+	}
+
+	switch strings.ToLower(q.Get("order")) {
+	case "", "desc":
+	case "asc":
+		t.Asc = true
+	default:
+		return t, domain.ErrInvalidInput
+	}
+
+	var err error
+	if t.CategoryID, err = optionalID(q.Get("category_id")); err != nil {
+		return t, err
+	}
+	if t.Desde, t.Hasta, err = parseDateRange(r); err != nil {
+		return t, err
+	}
+
+	return t, nil
+}
+
+// parseABCQuery lee from, to, metric, category_id, a y b.
+func parseABCQuery(r *http.Request) (domain.ABCQuery, error) {
+	q := r.URL.Query()
+
+	abc := domain.ABCQuery{Metric: q.Get("metric")}
+
+	var err error
+	if abc.Desde, abc.Hasta, err = parseDateRange(r); err != nil {
+		return abc, err
+	}
+	if abc.CategoryID, err = optionalID(q.Get("category_id")); err != nil {
+		return abc, err
+	}
+
+	a, err := optionalFloat(q.Get("a"))
+	if err != nil {
+		return abc, err
+	}
+	if a != nil {
+		abc.LimiteA = *a
+	}
+	b, err := optionalFloat(q.Get("b"))
+	if err != nil {
+		return abc, err
+	}
+	if b != nil {
+		abc.LimiteB = *b
+	}
+
+	return abc, nil
+}
+
+// parseReorderQuery lee days, method, window, alpha, season, lead_time, cover_days,
+// service_level, category_id y only_reorder.
+func parseReorderQuery(r *http.Request) (domain.ReorderQuery, error) {
+	q := r.URL.Query()
+
+	rq := domain.ReorderQuery{
+		Method:      strings.ToLower(q.Get("method")),
+		OnlyReorder: q.Get("only_reorder") == "true",
+	}
+
+	ints := map[string]*int{
+		"days":       &rq.Days,
+		"window":     &rq.Window,
+		"season":     &rq.Season,
+		"lead_time":  &rq.LeadTime,
+		"cover_days": &rq.CoverDays,
+	}
+	for name, dst := range ints {
+		if v := q.Get(name); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return rq, domain.ErrInvalidInput
+			}
+			*dst = n
+		}
+	}
+
+	floats := map[string]*float64{
+		"alpha":         &rq.Alpha,
+		"service_level": &rq.ServiceLevel,
+	}
+	for name, dst := range floats {
+		v, err := optionalFloat(q.Get(name))
+		if err != nil {
+			return rq, err
+		}
+		if v != nil {
+			*dst = *v
+		}
+	}
+
+	var err error
+	rq.CategoryID, err = optionalID(q.Get("category_id"))
+	return rq, err
+}
+
+// parseDateRange lee from y to.
+// Acepta fechas YYYY-MM-DD (to incluye todo ese día) o RFC3339 exactas.
+// El rango resultante es [desde, hasta).
+func parseDateRange(r *http.Request) (desde, hasta time.Time, err error) {
+	q := r.URL.Query()
+
+	if desde, err = parseDate(q.Get("from"), false); err != nil {
+		return
+	}
+	hasta, err = parseDate(q.Get("to"), true)
+	return
+}
+
+// parseDate interpreta una fecha de la query. Una fecha sin hora es el inicio de
+// ese día en la zona horaria de la tienda (time.Local); con endOfDay se convierte
+// en el inicio del día siguiente (límite exclusivo).
+func parseDate(v string, endOfDay bool) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+
+	if t, err := time.ParseInLocation(time.DateOnly, v, time.Local); err == nil {
+		if endOfDay {
+			t = t.AddDate(0, 0, 1)
+		}
+		return t, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, domain.ErrInvalidInput
+	}
+	return t, nil
+}
+
+// optionalID lee un ID positivo; vacío devuelve 0.
+func optionalID(v string) (int64, error) {
+	if v == "" {
+		return 0, nil
+	}
+	id, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, domain.ErrInvalidInput
+	}
+	return id, nil
+}
+
+func optionalFloat(v string) (*float64, error) {
+	if v == "" {
+		return nil, nil
+	}
+	n, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return nil, domain.ErrInvalidInput
+	}
+	return &n, nil
+}
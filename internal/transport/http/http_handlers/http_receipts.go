@@ -0,0 +1,90 @@
+package http_handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"ferreteria-inventario-ventas/internal/domain"
+)
+
+// Receipts godoc
+// @Summary Recepciones de mercadería
+// @Description GET lista recepciones paginadas, GET /api/receipts/{id} devuelve el detalle,
+// @Description POST registra una recepción: suma stock y recalcula el costo promedio ponderado de cada producto (todo o nada)
+// @Tags Receipts
+// @Accept json
+// @Produce json
+// @Param limit query int false "Filas por página (por defecto 50, máximo 500)"
+// @Param cursor query string false "Cursor devuelto en next_cursor"
+// @Param sort query string false "Campo de orden: id, fecha, total"
+// @Param order query string false "asc o desc"
+// @Param proveedor query string false "Proveedor contiene"
+// @Param from query string false "Desde (YYYY-MM-DD o RFC3339)"
+// @Param to query string false "Hasta (YYYY-MM-DD incluye ese día, o RFC3339 exclusivo)"
+// @Param receipt body domain.Receipt false "Recepción (solo POST)"
+// @Success 200 {object} domain.Page[domain.Receipt]
+// @Success 201 {object} domain.Receipt
+// @Router /api/receipts [get]
+// @Router /api/receipts [post]
+// @Router /api/receipts/{id} [get]
+func (h *Handlers) Receipts(w http.ResponseWriter, r *http.Request) {
+
+	id, err := pathID(r, "/api/receipts")
+	if err != nil {
+		writeJSON(w, 400, map[string]string{"error": "id inválido"})
+		return
+	}
+
+	switch {
+
+	case r.Method == http.MethodGet && id > 0:
+		rc, err := h.ReceiptsSvc.Get(id)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, 200, rc)
+
+	case r.Method == http.MethodGet:
+		f := domain.ReceiptFilter{Proveedor: strings.TrimSpace(r.URL.Query().Get("proveedor"))}
+		if f.Desde, f.Hasta, err = parseDateRange(r); err != nil {
+			writeJSON(w, 400, map[string]string{"error": "filtro inválido"})
+			return
+		}
+		page, err := parsePage(r)
+		if err != nil {
+			writeJSON(w, 400, map[string]string{"error": "paginación inválida"})
+			return
+		}
+
+		list, err := h.ReceiptsSvc.List(f, page)
+		if err != nil {
+			writeListError(w, err)
+			return
+		}
+		writeJSON(w, 200, list)
+
+	case r.Method == http.MethodPost && id == 0:
+		var input domain.Receipt
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			writeJSON(w, 400, map[string]string{"error": "JSON inválido"})
+			return
+		}
+
+		err := h.ReceiptsSvc.Create(r.Context(), &input)
+		switch err {
+		case nil:
+			writeJSON(w, 201, input)
+		case domain.ErrInvalidInput:
+			writeJSON(w, 400, map[string]string{"error": "proveedor y productos requeridos (cantidad > 0, costo >= 0, sin repetir)"})
+		case domain.ErrNotFound:
+			writeJSON(w, 404, map[string]string{"error": "producto no encontrado"})
+		default:
+			writeJSON(w, 500, map[string]string{"error": err.Error()})
+		}
+
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
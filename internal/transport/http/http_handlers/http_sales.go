@@ -11,12 +11,27 @@ import (
 
 // Sales godoc
 // @Summary Listar o crear ventas
-// @Description GET lista ventas, POST crea venta
+// @Description GET lista ventas, POST crea venta.
+// @Description Si se envía el header Idempotency-Key, un reintento con la misma llave devuelve la venta original
+// @Description (header Idempotent-Replayed: true) y una llave reutilizada con otro cuerpo responde 422.
+// @Description Descuentos manuales: descuento_manual en cada item o en la venta, con porcentaje o monto y motivo; se aplican después de las promociones.
+// @Description El cajero es el usuario de la sesión (header Authorization): los descuentos manuales requieren sesión (401 sin ella) y el cajero puede dar hasta el
+// @Description descuento máximo de su rol; por encima hace falta autorizacion con usuario y password de un supervisor o gerente o un token
+// @Description de POST /api/discount-approvals (403 si no alcanza o el usuario no es supervisor, 401 si las credenciales no son válidas).
 // @Tags Sales
 // @Accept json
 // @Produce json
-// @Param sale body domain.Sale false "Venta (solo POST)"
-// @Success 200 {array} domain.Sale
+// @Param Idempotency-Key header string false "Llave única por intento de venta (solo POST)"
+// @Param limit query int false "Filas por página (por defecto 50, máximo 500)"
+// @Param cursor query string false "Cursor devuelto en next_cursor"
+// @Param sort query string false "Campo de orden: id, fecha, total"
+// @Param order query string false "asc o desc"
+// @Param client_id query int false "Ventas de un cliente"
+// @Param from query string false "Desde (YYYY-MM-DD o RFC3339)"
+// @Param to query string false "Hasta, inclusive si es YYYY-MM-DD"
+// @Param Authorization header string false "Bearer <token> de la sesión del cajero (solo POST)"
+// @Param sale body saleInput false "Venta (solo POST)"
+// @Success 200 {object} domain.Page[domain.Sale]
 // @Success 201 {object} domain.Sale
 // @Router /api/sales [get]
 // @Router /api/sales [post]
@@ -25,31 +40,64 @@ func (h *Handlers) Sales(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 
 	case http.MethodGet:
-		list, err := h.SalesSvc.List()
+		filter, err := parseSaleFilter(r)
 		if err != nil {
-			writeJSON(w, 500, map[string]string{"error": err.Error()})
+			writeJSON(w, 400, map[string]string{"error": "filtro inválido"})
+			return
+		}
+		page, err := parsePage(r)
+		if err != nil {
+			writeJSON(w, 400, map[string]string{"error": "paginación inválida"})
 			return
 		}
-		writeJSON(w, 200, list)
 
-	case http.MethodPost:
-		var input struct {
-			ClientID int64             `json:"client_id"`
-			Items    []domain.SaleItem `json:"items"`
+		list, err := h.SalesSvc.List(filter, page)
+		if err != nil {
+			writeListError(w, err)
+			return
 		}
+		writeJSON(w, 200, list)
 
+	case http.MethodPost:
+		var input saleInput
 		err := json.NewDecoder(r.Body).Decode(&input)
 		if err != nil {
 			writeJSON(w, 400, map[string]string{"error": "JSON inválido"})
 			return
 		}
 
-		sale, err := h.SalesSvc.Create(input.ClientID, input.Items)
+		key := r.Header.Get("Idempotency-Key")
+
+		sale, replayed, err := h.SalesSvc.Create(r.Context(), domain.NewSale{
+			ClientID:     input.ClientID,
+			Items:        input.Items,
+			Descuento:    input.DescuentoManual,
+			Autorizacion: input.Autorizacion,
+		}, key)
 		if err != nil {
-			writeJSON(w, 400, map[string]string{"error": err.Error()})
+			switch err {
+			case domain.ErrDiscountLimit, domain.ErrBadCredentials, domain.ErrForbidden:
+				writeDiscountAuthError(w, err)
+			case domain.ErrUnauthenticated:
+				writeJSON(w, 401, map[string]string{"error": "los descuentos manuales requieren iniciar sesión (header Authorization: Bearer <token>)"})
+			case domain.ErrNotFound:
+				writeJSON(w, 404, map[string]string{"error": "cliente o producto no encontrado"})
+			case domain.ErrInsufficientStock:
+				writeJSON(w, 409, map[string]string{"error": err.Error()})
+			case domain.ErrIdempotencyMismatch:
+				writeJSON(w, 422, map[string]string{"error": err.Error()})
+			case domain.ErrInvalidInput:
+				writeJSON(w, 400, map[string]string{"error": err.Error()})
+			default:
+				writeJSON(w, 500, map[string]string{"error": err.Error()})
+			}
 			return
 		}
 
+		if replayed {
+			w.Header().Set("Idempotent-Replayed", "true")
+		}
+
 		writeJSON(w, 201, sale)
 
 	default:
@@ -57,6 +105,28 @@ func (h *Handlers) Sales(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// saleInput es el cuerpo de POST /api/sales.
+type saleInput struct {
+	ClientID        int64                       `json:"client_id"`
+	Items           []domain.SaleItem           `json:"items"`
+	DescuentoManual *domain.ManualDiscount      `json:"descuento_manual,omitempty"` // Descuento sobre toda la venta
+	Autorizacion    *domain.DiscountCredentials `json:"autorizacion,omitempty"`     // Supervisor, si el descuento supera el límite del cajero
+}
+
+// writeDiscountAuthError responde los errores de autorización de descuentos manuales.
+func writeDiscountAuthError(w http.ResponseWriter, err error) {
+	switch err {
+	case domain.ErrBadCredentials:
+		writeJSON(w, 401, map[string]string{"error": "usuario, contraseña o token de aprobación inválidos (el token es de un solo uso y vence)"})
+	case domain.ErrDiscountLimit:
+		writeJSON(w, 403, map[string]string{"error": "el descuento supera el máximo permitido: requiere autorización de un supervisor con un límite mayor"})
+	case domain.ErrForbidden:
+		writeJSON(w, 403, map[string]string{"error": "solo un supervisor o gerente puede autorizar descuentos"})
+	default:
+		writeJSON(w, 500, map[string]string{"error": err.Error()})
+	}
+}
+
 // SaleDetail godoc
 // @Summary Obtener detalle de venta
 // @Description Devuelve una venta con sus items
@@ -102,39 +172,223 @@ func (h *Handlers) SaleDetail(w http.ResponseWriter, r *http.Request) {
 
 // ReportVentasHoy godoc
 // @Summary Ventas del día
-// @Description Devuelve cantidad de ventas y total del día
+// @Description Devuelve cantidad de ventas y totales del día
 // @Tags Report
 // @Produce json
-// @Success 200 {object} map[string]interface{}
+// @Success 200 {object} domain.SalesTotals
 // @Router /api/report/ventas-hoy [get]
 func (h *Handlers) ReportVentasHoy(w http.ResponseWriter, r *http.Request) {
 
-	count, total, err := h.SalesSvc.VentasHoy()
+	totals, err := h.SalesSvc.VentasHoy()
+	if err != nil {
+		writeJSON(w, 500, map[string]string{"error": err.Error()})
+		return
+	}
+
+	writeJSON(w, 200, totals)
+}
+
+// ReportSalesSummary godoc
+// @Summary Resumen de ventas por período
+// @Description Cantidad de ventas, bruto, descuentos, impuestos, neto, total y ticket promedio por día, semana (lunes a domingo) o mes. Incluye los períodos sin ventas
+// @Tags Report
+// @Produce json
+// @Param from query string true "Desde (YYYY-MM-DD o RFC3339)"
+// @Param to query string true "Hasta (YYYY-MM-DD incluye ese día, o RFC3339 exclusivo)"
+// @Param group_by query string false "day (por defecto), week o month"
+// @Success 200 {object} domain.SalesSummary
+// @Router /api/report/sales-summary [get]
+func (h *Handlers) ReportSalesSummary(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	desde, hasta, err := parseDateRange(r)
+	if err != nil {
+		writeJSON(w, 400, map[string]string{"error": "fecha inválida"})
+		return
+	}
+
+	groupBy := r.URL.Query().Get("group_by")
+	if groupBy == "" {
+		groupBy = domain.PeriodDay
+	}
+
+	summary, err := h.SalesSvc.SalesSummary(desde, hasta, groupBy)
+	if err != nil {
+		if err == domain.ErrInvalidInput {
+			writeJSON(w, 400, map[string]string{"error": "from y to requeridos (from < to), group_by day, week o month y no más de 1100 períodos"})
+			return
+		}
+		writeJSON(w, 500, map[string]string{"error": err.Error()})
+		return
+	}
+
+	writeJSON(w, 200, summary)
+}
+
+// ReportHeatmap godoc
+// @Summary Mapa de calor de ventas por día y hora
+// @Description Matriz de 7 días (lunes a domingo) × 24 horas en hora local de la tienda: ventas y monto cobrado, con promedio y mediana por ocurrencia (cada lunes de 10 a 11 del rango cuenta, aunque no tenga ventas). Por defecto los últimos 84 días completos
+// @Tags Report
+// @Produce json
+// @Param from query string false "Desde (YYYY-MM-DD o RFC3339)"
+// @Param to query string false "Hasta (YYYY-MM-DD incluye ese día, o RFC3339 exclusivo)"
+// @Success 200 {object} domain.SalesHeatmap
+// @Router /api/report/heatmap [get]
+func (h *Handlers) ReportHeatmap(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	desde, hasta, err := parseDateRange(r)
+	if err != nil {
+		writeJSON(w, 400, map[string]string{"error": "fecha inválida"})
+		return
+	}
+
+	heatmap, err := h.SalesSvc.Heatmap(desde, hasta)
 	if err != nil {
+		if err == domain.ErrInvalidInput {
+			writeJSON(w, 400, map[string]string{"error": "from < to y no más de 1100 días"})
+			return
+		}
 		writeJSON(w, 500, map[string]string{"error": err.Error()})
 		return
 	}
 
-	writeJSON(w, 200, map[string]interface{}{
-		"ventas": count,
-		"total":  total,
-	})
+	writeJSON(w, 200, heatmap)
 }
 
 // ReportTopProductos godoc
 // @Summary Top productos vendidos
-// @Description Devuelve los 5 productos más vendidos
+// @Description Ranking de productos (agrupado por ID) por unidades, monto vendido o utilidad bruta, con rango de fechas y filtro de categoría. Con order=asc devuelve los menos vendidos, incluyendo los que no tienen ventas.
+// @Description Con group_by=category o group_by=brand agrega las ventas por categoría (sumando subcategorías) o por marca.
 // @Tags Report
 // @Produce json
-// @Success 200 {array} map[string]interface{}
+// @Param group_by query string false "product (por defecto), category o brand"
+// @Param limit query int false "Filas (por defecto 5, máximo 100)"
+// @Param metric query string false "units (por defecto), revenue o margin"
+// @Param order query string false "desc (por defecto) o asc"
+// @Param category_id query int false "Categoría (incluye subcategorías)"
+// @Param from query string false "Desde (YYYY-MM-DD o RFC3339)"
+// @Param to query string false "Hasta (YYYY-MM-DD incluye ese día, o RFC3339 exclusivo)"
+// @Success 200 {array} domain.TopProduct
+// @Success 200 {array} domain.GroupSales
 // @Router /api/report/top-productos [get]
 func (h *Handlers) ReportTopProductos(w http.ResponseWriter, r *http.Request) {
 
-	data, err := h.SalesSvc.TopProductos()
+	if groupBy := r.URL.Query().Get("group_by"); groupBy != "" && groupBy != domain.GroupByProduct {
+		data, err := h.SalesSvc.VentasPorGrupo(groupBy)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, 200, data)
+		return
+	}
+
+	q, err := parseTopProductsQuery(r)
+	if err != nil {
+		writeJSON(w, 400, map[string]string{"error": "parámetros inválidos"})
+		return
+	}
+
+	data, err := h.SalesSvc.TopProductos(q)
 	if err != nil {
+		if err == domain.ErrInvalidInput {
+			writeJSON(w, 400, map[string]string{"error": "limit 1-100, metric units, revenue o margin y from < to"})
+			return
+		}
 		writeJSON(w, 500, map[string]string{"error": err.Error()})
 		return
 	}
 
 	writeJSON(w, 200, data)
 }
+
+// ReportMargins godoc
+// @Summary Utilidad bruta y margen
+// @Description Ingresos netos (descuentos repartidos, sin impuestos), costo de lo vendido al momento de cada venta, utilidad y margen %.
+// @Description Agrupa por venta (más recientes primero), producto o categoría (incluye subcategorías). Por día, semana o mes usar /api/report/sales-summary.
+// @Tags Report
+// @Produce json
+// @Param group_by query string true "sale, product o category"
+// @Param from query string false "Desde (YYYY-MM-DD o RFC3339)"
+// @Param to query string false "Hasta (YYYY-MM-DD incluye ese día, o RFC3339 exclusivo)"
+// @Param limit query int false "Filas (por defecto 100, máximo 1000)"
+// @Success 200 {array} domain.MarginRow
+// @Router /api/report/margins [get]
+func (h *Handlers) ReportMargins(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	desde, hasta, err := parseDateRange(r)
+	if err != nil {
+		writeJSON(w, 400, map[string]string{"error": "fecha inválida"})
+		return
+	}
+
+	limit := 0
+	if v := r.URL.Query().Get("limit"); v != "" {
+		if limit, err = strconv.Atoi(v); err != nil {
+			writeJSON(w, 400, map[string]string{"error": "limit inválido"})
+			return
+		}
+	}
+
+	rows, err := h.SalesSvc.Margins(r.URL.Query().Get("group_by"), desde, hasta, limit)
+	if err != nil {
+		if err == domain.ErrInvalidInput {
+			writeJSON(w, 400, map[string]string{"error": "group_by sale, product o category, limit 1-1000 y from < to"})
+			return
+		}
+		writeJSON(w, 500, map[string]string{"error": err.Error()})
+		return
+	}
+
+	writeJSON(w, 200, rows)
+}
+
+// ReportDiscounts godoc
+// @Summary Descuentos manuales por cajero
+// @Description Por cajero (usuario de la sesión con que se hizo la venta): ventas, bruto, ventas con descuento manual, cantidad y monto de descuentos,
+// @Description porcentaje sobre el bruto y cuántas ventas necesitaron autorización de un supervisor. Por defecto los últimos 30 días con hoy.
+// @Tags Report
+// @Produce json
+// @Param from query string false "Desde (YYYY-MM-DD o RFC3339)"
+// @Param to query string false "Hasta (YYYY-MM-DD incluye ese día, o RFC3339 exclusivo)"
+// @Success 200 {object} domain.DiscountReport
+// @Router /api/report/discounts [get]
+func (h *Handlers) ReportDiscounts(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	desde, hasta, err := parseDateRange(r)
+	if err != nil {
+		writeJSON(w, 400, map[string]string{"error": "fecha inválida"})
+		return
+	}
+
+	report, err := h.SalesSvc.DiscountReport(desde, hasta)
+	if err != nil {
+		if err == domain.ErrInvalidInput {
+			writeJSON(w, 400, map[string]string{"error": "from debe ser anterior a to"})
+			return
+		}
+		writeJSON(w, 500, map[string]string{"error": err.Error()})
+		return
+	}
+
+	writeJSON(w, 200, report)
+}
@@ -0,0 +1,252 @@
+package http_handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"ferreteria-inventario-ventas/internal/domain"
+)
+
+// Users godoc
+// @Summary Usuarios
+// @Description GET lista los usuarios, GET /api/users/{id} devuelve uno, POST crea (password de 8 caracteres o más),
+// @Description PUT /api/users/{id} cambia nombre, rol, activo y la contraseña si viene (el usuario no cambia). La contraseña nunca se devuelve.
+// @Description Requiere una sesión de supervisor o gerente (401 sin sesión, 403 con otro rol); mientras no hay usuarios, el primero
+// @Description (supervisor o gerente) se crea sin sesión. El usuario que vende se identifica con su sesión; su rol define el descuento manual máximo.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param Authorization header string false "Bearer <token> de POST /api/sessions"
+// @Param user body domain.User false "Usuario (POST/PUT)"
+// @Success 200 {array} domain.User
+// @Success 201 {object} domain.User
+// @Router /api/users [get]
+// @Router /api/users [post]
+// @Router /api/users/{id} [get]
+// @Router /api/users/{id} [put]
+func (h *Handlers) Users(w http.ResponseWriter, r *http.Request) {
+
+	id, err := pathID(r, "/api/users")
+	if err != nil {
+		writeJSON(w, 400, map[string]string{"error": "id inválido"})
+		return
+	}
+
+	switch {
+
+	case r.Method == http.MethodGet && id > 0:
+		u, err := h.UsersSvc.Get(r.Context(), id)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, 200, u)
+
+	case r.Method == http.MethodGet:
+		list, err := h.UsersSvc.List(r.Context())
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, 200, list)
+
+	case r.Method == http.MethodPost && id == 0:
+		input := domain.User{Activo: true} // Sin "activo" queda activo
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			writeJSON(w, 400, map[string]string{"error": "JSON inválido"})
+			return
+		}
+		if err := h.UsersSvc.Create(r.Context(), &input); err != nil {
+			writeUserError(w, err)
+			return
+		}
+		writeJSON(w, 201, input)
+
+	case r.Method == http.MethodPut && id > 0:
+		input := domain.User{Activo: true}
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			writeJSON(w, 400, map[string]string{"error": "JSON inválido"})
+			return
+		}
+		if err := h.UsersSvc.Update(r.Context(), id, &input); err != nil {
+			writeUserError(w, err)
+			return
+		}
+		writeJSON(w, 200, input)
+
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// writeUserError responde los errores al crear o modificar un usuario.
+func writeUserError(w http.ResponseWriter, err error) {
+	switch err {
+	case domain.ErrInvalidInput:
+		writeJSON(w, 400, map[string]string{"error": "usuario requerido (sin espacios) y password de 8 caracteres o más"})
+	case domain.ErrConflict:
+		writeJSON(w, 409, map[string]string{"error": "usuario ya registrado"})
+	case domain.ErrNotFound:
+		writeJSON(w, 404, map[string]string{"error": "usuario o rol no encontrado"})
+	case domain.ErrUnauthenticated, domain.ErrForbidden:
+		writeAccessError(w, err)
+	default:
+		writeJSON(w, 500, map[string]string{"error": err.Error()})
+	}
+}
+
+// Roles godoc
+// @Summary Roles y descuento máximo
+// @Description GET lista los roles con el descuento manual máximo (%) que dan sin autorización.
+// @Description PUT /api/roles/{nombre} { "descuento_max": 10 } crea el rol o cambia su máximo (0 a 100); requiere una sesión de supervisor o gerente.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param Authorization header string false "Bearer <token> de POST /api/sessions (PUT)"
+// @Param nombre path string false "Rol (PUT)"
+// @Param role body domain.Role false "Descuento máximo (PUT)"
+// @Success 200 {array} domain.Role
+// @Router /api/roles [get]
+// @Router /api/roles/{nombre} [put]
+func (h *Handlers) Roles(w http.ResponseWriter, r *http.Request) {
+
+	nombre := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/roles"), "/")
+
+	switch {
+
+	case r.Method == http.MethodGet && nombre == "":
+		list, err := h.UsersSvc.Roles()
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, 200, list)
+
+	case r.Method == http.MethodPut && nombre != "":
+		var input domain.Role
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			writeJSON(w, 400, map[string]string{"error": "JSON inválido"})
+			return
+		}
+		input.Nombre = nombre
+		if err := h.UsersSvc.SaveRole(r.Context(), &input); err != nil {
+			if err == domain.ErrInvalidInput {
+				writeJSON(w, 400, map[string]string{"error": "descuento_max entre 0 y 100"})
+				return
+			}
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, 200, input)
+
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// discountApprovalInput es el cuerpo de POST /api/discount-approvals.
+type discountApprovalInput struct {
+	Usuario      string  `json:"usuario"`
+	Password     string  `json:"password"`
+	DescuentoMax float64 `json:"descuento_max,omitempty"` // 0 = el máximo del rol
+}
+
+// DiscountApprovals godoc
+// @Summary Aprobar un descuento de antemano
+// @Description Con usuario y password de un supervisor o gerente genera un token de un solo uso, válido 15 minutos, que el cajero envía
+// @Description en autorizacion.token al crear la venta. Autoriza hasta descuento_max (por defecto y como máximo, el de su rol).
+// @Description 401 si las credenciales no son válidas, 403 si el usuario no es supervisor ni gerente.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param approval body discountApprovalInput true "Credenciales del supervisor"
+// @Success 201 {object} domain.DiscountApproval
+// @Router /api/discount-approvals [post]
+func (h *Handlers) DiscountApprovals(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var input discountApprovalInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeJSON(w, 400, map[string]string{"error": "JSON inválido"})
+		return
+	}
+
+	a, err := h.UsersSvc.Approve(domain.DiscountCredentials{Usuario: input.Usuario, Password: input.Password}, input.DescuentoMax)
+	if err != nil {
+		switch err {
+		case domain.ErrInvalidInput:
+			writeJSON(w, 400, map[string]string{"error": "descuento_max entre 0 y 100"})
+		case domain.ErrDiscountLimit:
+			writeJSON(w, 403, map[string]string{"error": "descuento_max supera el máximo del rol del supervisor"})
+		default:
+			writeDiscountAuthError(w, err)
+		}
+		return
+	}
+
+	writeJSON(w, 201, a)
+}
+
+// sessionInput es el cuerpo de POST /api/sessions.
+type sessionInput struct {
+	Usuario  string `json:"usuario"`
+	Password string `json:"password"`
+}
+
+// Sessions godoc
+// @Summary Iniciar o cerrar sesión
+// @Description POST con usuario y password inicia una sesión de 12 horas y devuelve el token, que se envía en el header
+// @Description Authorization: Bearer <token>. Con la sesión se identifica al cajero de las ventas y al actor de la auditoría.
+// @Description DELETE cierra la sesión del header Authorization. 401 si las credenciales o la sesión no son válidas.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param Authorization header string false "Bearer <token> (DELETE)"
+// @Param session body sessionInput false "Credenciales (POST)"
+// @Success 201 {object} domain.Session
+// @Success 204
+// @Router /api/sessions [post]
+// @Router /api/sessions [delete]
+func (h *Handlers) Sessions(w http.ResponseWriter, r *http.Request) {
+
+	switch r.Method {
+
+	case http.MethodPost:
+		var input sessionInput
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			writeJSON(w, 400, map[string]string{"error": "JSON inválido"})
+			return
+		}
+		session, err := h.UsersSvc.Login(input.Usuario, input.Password)
+		if err == domain.ErrBadCredentials {
+			writeJSON(w, 401, map[string]string{"error": "usuario o contraseña inválidos"})
+			return
+		}
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, 201, session)
+
+	case http.MethodDelete:
+		// El middleware ya verificó el token; sin sesión no hay nada que cerrar
+		if domain.UserFrom(r.Context()) == nil {
+			writeAccessError(w, domain.ErrUnauthenticated)
+			return
+		}
+		_, token, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+		if err := h.UsersSvc.Logout(strings.TrimSpace(token)); err != nil {
+			writeError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
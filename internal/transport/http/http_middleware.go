@@ -0,0 +1,82 @@
+package httptransport
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"ferreteria-inventario-ventas/internal/domain"
+)
+
+// Headers de identificación de cada petición.
+const (
+	headerRequestID = "X-Request-ID"  // Se respeta el que envía el cliente o se genera uno
+	headerAuth      = "Authorization" // Bearer <token> de POST /api/sessions
+)
+
+// SessionResolver devuelve el usuario de un token de sesión
+// (ErrBadCredentials si no existe o venció).
+type SessionResolver func(token string) (*domain.User, error)
+
+// maxHeaderID limita el largo del ID de petición que se guarda.
+const maxHeaderID = 100
+
+// withRequestContext agrega al contexto el usuario de la sesión (header Authorization)
+// y, para la auditoría, el actor y el ID de la petición; devuelve el ID en el header
+// X-Request-ID de la respuesta. Un token inválido o vencido responde 401; sin token
+// la petición sigue sin usuario.
+func withRequestContext(next http.Handler, sessions SessionResolver) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		id := cleanHeaderID(r.Header.Get(headerRequestID))
+		if id == "" {
+			id = newRequestID()
+		}
+		w.Header().Set(headerRequestID, id)
+
+		actor := domain.AuditActor{RequestID: id}
+		ctx := r.Context()
+
+		if token := bearerToken(r); token != "" {
+			u, err := sessions(token)
+			if err == domain.ErrBadCredentials {
+				WriteJSON(w, http.StatusUnauthorized, map[string]string{"error": "sesión inválida o vencida"})
+				return
+			}
+			if err != nil {
+				WriteJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+				return
+			}
+			actor.Actor = u.Usuario
+			ctx = domain.WithUser(ctx, u)
+		}
+
+		next.ServeHTTP(w, r.WithContext(domain.WithAuditActor(ctx, actor)))
+	})
+}
+
+// bearerToken devuelve el token del header Authorization: Bearer <token> (vacío si no viene).
+func bearerToken(r *http.Request) string {
+	scheme, token, ok := strings.Cut(r.Header.Get(headerAuth), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+// cleanHeaderID recorta espacios y descarta valores demasiado largos o con caracteres de control.
+func cleanHeaderID(v string) string {
+	v = strings.TrimSpace(v)
+	if len(v) > maxHeaderID || strings.ContainsFunc(v, func(r rune) bool { return r < ' ' || r == 0x7f }) {
+		return ""
+	}
+	return v
+}
+
+// newRequestID genera un ID aleatorio de 16 caracteres hexadecimales.
+func newRequestID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -22,11 +22,49 @@ func NewRouter(h *http_handlers.Handlers) http.Handler {
 
 	// Clientes
 	mux.HandleFunc("/api/clients", h.Clients)
+	// Historial de compras: /api/clients/{id}/sales; lista de precios: /api/clients/{id}/price-list
+	mux.HandleFunc("/api/clients/", h.ClientSales)
 
 	// Productos
 	mux.HandleFunc("/api/products", h.Products)
 	// ✅ IMPORTANTE: habilita /api/products/{id} para PUT/DELETE
 	mux.HandleFunc("/api/products/", h.Products)
+	// Búsqueda de texto completo
+	mux.HandleFunc("/api/products/search", h.ProductSearch)
+	// Códigos de barras: lectura con escáner y asignación en bloque
+	mux.HandleFunc("/api/products/by-barcode/", h.ProductByBarcode)
+	mux.HandleFunc("/api/products/barcodes", h.ProductBarcodes)
+	// Importación masiva desde CSV / XLSX
+	mux.HandleFunc("/api/products/import", h.ProductImport)
+
+	// Historial de precios: /api/products/{id}/prices (lo atiende h.Products)
+	// Cambios de precio programados y aumentos masivos
+	mux.HandleFunc("/api/price-changes", h.PriceChanges)
+	mux.HandleFunc("/api/price-changes/", h.PriceChanges)
+	mux.HandleFunc("/api/price-changes/bulk", h.PriceChangesBulk)
+
+	// Listas de precios (minorista, mayorista, contratista...)
+	mux.HandleFunc("/api/price-lists", h.PriceLists)
+	mux.HandleFunc("/api/price-lists/", h.PriceLists)
+	mux.HandleFunc("/api/promotions", h.Promotions)
+	mux.HandleFunc("/api/promotions/", h.Promotions)
+	mux.HandleFunc("/api/users", h.Users)
+	mux.HandleFunc("/api/users/", h.Users)
+	mux.HandleFunc("/api/roles", h.Roles)
+	mux.HandleFunc("/api/roles/", h.Roles)
+	mux.HandleFunc("/api/discount-approvals", h.DiscountApprovals)
+	// Inicio y cierre de sesión (token para el header Authorization)
+	mux.HandleFunc("/api/sessions", h.Sessions)
+
+	// Categorías y marcas
+	mux.HandleFunc("/api/categories", h.Categories)
+	mux.HandleFunc("/api/categories/", h.Categories)
+	mux.HandleFunc("/api/brands", h.Brands)
+	mux.HandleFunc("/api/brands/", h.Brands)
+
+	// Recepciones de mercadería (stock y costo promedio)
+	mux.HandleFunc("/api/receipts", h.Receipts)
+	mux.HandleFunc("/api/receipts/", h.Receipts)
 
 	// Ventas
 	mux.HandleFunc("/api/sales", h.Sales)
@@ -34,9 +72,32 @@ func NewRouter(h *http_handlers.Handlers) http.Handler {
 	// Detalle de venta por ID
 	mux.HandleFunc("/api/sales/", h.SaleDetail)
 
+	// Auditoría de cambios
+	mux.HandleFunc("/api/audit", h.Audit)
+
+	// Tablero (indicadores de la pantalla principal)
+	mux.HandleFunc("/api/dashboard", h.Dashboard)
+
 	// Reportes
 	mux.HandleFunc("/api/report/ventas-hoy", h.ReportVentasHoy)
 	mux.HandleFunc("/api/report/top-productos", h.ReportTopProductos)
+	mux.HandleFunc("/api/report/sales-summary", h.ReportSalesSummary)
+	mux.HandleFunc("/api/report/heatmap", h.ReportHeatmap)
+	mux.HandleFunc("/api/report/margins", h.ReportMargins)
+	mux.HandleFunc("/api/report/discounts", h.ReportDiscounts)
+	mux.HandleFunc("/api/report/inventory-valuation", h.ReportInventoryValuation)
+	mux.HandleFunc("/api/report/abc", h.ReportABC)
+	mux.HandleFunc("/api/report/dead-stock", h.ReportDeadStock)
+	mux.HandleFunc("/api/report/reorder", h.ReportReorder)
+	mux.HandleFunc("/api/report/rfm", h.ReportRFM)
+
+	// Exportaciones (CSV / XLSX / NDJSON)
+	mux.HandleFunc("/api/export/products", h.ExportProducts)
+	mux.HandleFunc("/api/export/clients", h.ExportClients)
+	mux.HandleFunc("/api/export/sales", h.ExportSales)
+	mux.HandleFunc("/api/export/sale-items", h.ExportSaleItems)
+	mux.HandleFunc("/api/export/inventory-valuation", h.ExportInventoryValuation)
+	mux.HandleFunc("/api/export/rfm", h.ExportRFM)
 
 	// Swagger
 	mux.Handle("/swagger/", httpSwagger.WrapHandler)
@@ -57,5 +118,5 @@ func NewRouter(h *http_handlers.Handlers) http.Handler {
 		fs.ServeHTTP(w, r)
 	})
 
-	return mux
+	return withRequestContext(mux, h.UsersSvc.SessionUser)
 }
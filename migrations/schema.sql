@@ -5,7 +5,27 @@ CREATE TABLE IF NOT EXISTS clients (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
     nombre TEXT NOT NULL,
     cedula TEXT NOT NULL UNIQUE,
-    email TEXT NOT NULL
+    email TEXT NOT NULL,
+    price_list_id INTEGER REFERENCES price_lists(id) -- Lista de precios (NULL = precio del producto)
+);
+
+-- ================================
+-- TABLAS CATEGORÍAS Y MARCAS
+-- ================================
+-- Árbol de categorías: parent_id NULL = raíz.
+CREATE TABLE IF NOT EXISTS categories (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    nombre TEXT NOT NULL,
+    parent_id INTEGER,
+    FOREIGN KEY (parent_id) REFERENCES categories(id)
+);
+
+-- No se repite el nombre entre hermanos
+CREATE UNIQUE INDEX IF NOT EXISTS idx_categories_parent_nombre ON categories(IFNULL(parent_id, 0), nombre);
+
+CREATE TABLE IF NOT EXISTS brands (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    nombre TEXT NOT NULL UNIQUE
 );
 
 -- ================================
@@ -15,7 +35,11 @@ CREATE TABLE IF NOT EXISTS products (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
     nombre TEXT NOT NULL UNIQUE,
     stock INTEGER NOT NULL,
-    precio REAL NOT NULL
+    precio REAL NOT NULL,
+    sku TEXT,
+    category_id INTEGER REFERENCES categories(id),
+    brand_id INTEGER REFERENCES brands(id),
+    costo REAL NOT NULL DEFAULT 0 -- Costo promedio ponderado
 );
 
 -- ================================
@@ -25,7 +49,12 @@ CREATE TABLE IF NOT EXISTS sales (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
     client_id INTEGER NOT NULL,
     fecha TEXT NOT NULL,
-    total REAL NOT NULL,
+    total REAL NOT NULL,               -- Cobrado: suma de líneas - descuento + impuesto
+    descuento REAL NOT NULL DEFAULT 0, -- Descuentos de la venta (de las líneas + de la cabecera)
+    impuesto REAL NOT NULL DEFAULT 0,  -- Impuesto sobre el neto (FERRETERIA_IMPUESTO), incluido en el total
+    descuento_lineas REAL NOT NULL DEFAULT 0, -- Parte del descuento que está en las líneas
+    cajero TEXT,         -- Usuario de la sesión que vendió
+    autorizado_por TEXT, -- Supervisor que autorizó descuentos manuales por encima del límite del cajero
     FOREIGN KEY (client_id) REFERENCES clients(id)
 );
 
@@ -38,7 +67,393 @@ CREATE TABLE IF NOT EXISTS sale_items (
     product_id INTEGER NOT NULL,
     cantidad INTEGER NOT NULL,
     precio_unitario REAL NOT NULL,
-    subtotal REAL NOT NULL,
+    subtotal REAL NOT NULL,                 -- cantidad * precio_unitario (antes de descuentos)
+    costo_unitario REAL NOT NULL DEFAULT 0, -- Costo del producto al momento de la venta
+    descuento REAL NOT NULL DEFAULT 0,      -- Descuento de promociones en la línea
+    price_list_id INTEGER REFERENCES price_lists(id), -- Lista que definió el precio (NULL = precio del producto)
+    FOREIGN KEY (sale_id) REFERENCES sales(id),
+    FOREIGN KEY (product_id) REFERENCES products(id)
+);
+
+-- ================================
+-- TABLA LLAVES DE IDEMPOTENCIA
+-- ================================
+-- Guarda la llave enviada en el header Idempotency-Key junto con la huella
+-- de la petición y la venta que produjo, para repetir la respuesta en reintentos.
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    key TEXT PRIMARY KEY,
+    fingerprint TEXT NOT NULL,
+    sale_id INTEGER NOT NULL,
+    created_at TEXT NOT NULL,
+    FOREIGN KEY (sale_id) REFERENCES sales(id)
+);
+
+-- ================================
+-- ÍNDICES PARA LISTADOS Y FILTROS
+-- ================================
+CREATE INDEX IF NOT EXISTS idx_sales_fecha ON sales(fecha);
+CREATE INDEX IF NOT EXISTS idx_sales_client ON sales(client_id);
+CREATE INDEX IF NOT EXISTS idx_sale_items_sale ON sale_items(sale_id);
+CREATE INDEX IF NOT EXISTS idx_sale_items_product ON sale_items(product_id);
+CREATE INDEX IF NOT EXISTS idx_products_stock ON products(stock);
+
+-- ================================
+-- BÚSQUEDA DE PRODUCTOS (FTS5)
+-- ================================
+-- Índice de texto completo sobre products.nombre.
+-- unicode61 con remove_diacritics 2 ignora tildes y mayúsculas ("tubería" = "TUBERIA").
+-- Se mantiene sincronizado con products mediante los triggers de abajo.
+CREATE VIRTUAL TABLE IF NOT EXISTS products_fts USING fts5(
+    nombre,
+    content='products',
+    content_rowid='id',
+    tokenize='unicode61 remove_diacritics 2'
+);
+
+CREATE TRIGGER IF NOT EXISTS products_fts_ai AFTER INSERT ON products BEGIN
+    INSERT INTO products_fts(rowid, nombre) VALUES (new.id, new.nombre);
+END;
+
+CREATE TRIGGER IF NOT EXISTS products_fts_ad AFTER DELETE ON products BEGIN
+    INSERT INTO products_fts(products_fts, rowid, nombre) VALUES ('delete', old.id, old.nombre);
+END;
+
+CREATE TRIGGER IF NOT EXISTS products_fts_au AFTER UPDATE OF nombre ON products BEGIN
+    INSERT INTO products_fts(products_fts, rowid, nombre) VALUES ('delete', old.id, old.nombre);
+    INSERT INTO products_fts(rowid, nombre) VALUES (new.id, new.nombre);
+END;
+
+-- ================================
+-- SKU Y CÓDIGOS DE BARRAS
+-- ================================
+-- SKU interno único. Si no se envía al crear el producto se genera FER-000123.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_products_sku ON products(sku);
+
+CREATE TRIGGER IF NOT EXISTS products_sku_ai AFTER INSERT ON products
+WHEN new.sku IS NULL OR new.sku = '' BEGIN
+    UPDATE products SET sku = printf('FER-%06d', new.id) WHERE id = new.id;
+END;
+
+-- Productos creados antes de existir el SKU
+UPDATE products SET sku = printf('FER-%06d', id) WHERE sku IS NULL OR sku = '';
+
+-- Códigos EAN-13 / UPC-A / EAN-8 (uno o varios por producto).
+-- Los UPC-A de 12 dígitos se guardan como EAN-13 (con 0 adelante).
+CREATE TABLE IF NOT EXISTS product_barcodes (
+    code TEXT PRIMARY KEY,
+    product_id INTEGER NOT NULL,
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_barcodes_product ON product_barcodes(product_id);
+
+CREATE INDEX IF NOT EXISTS idx_products_category ON products(category_id);
+CREATE INDEX IF NOT EXISTS idx_products_brand ON products(brand_id);
+
+-- ================================
+-- FECHAS EN UTC
+-- Las ventas se guardaban con la zona del servidor (ej: 2026-10-01T19:30:00-05:00).
+-- Se pasan a UTC (2026-10-02T00:30:00Z) para compararlas como texto con el índice.
+-- ================================
+UPDATE sales SET fecha = strftime('%Y-%m-%dT%H:%M:%SZ', fecha)
+WHERE fecha NOT LIKE '%Z' AND strftime('%Y-%m-%dT%H:%M:%SZ', fecha) IS NOT NULL;
+
+UPDATE idempotency_keys SET created_at = strftime('%Y-%m-%dT%H:%M:%SZ', created_at)
+WHERE created_at NOT LIKE '%Z' AND strftime('%Y-%m-%dT%H:%M:%SZ', created_at) IS NOT NULL;
+
+-- ================================
+-- COSTOS: RECEPCIONES DE MERCADERÍA Y MOVIMIENTOS DE STOCK
+-- ================================
+-- Cada recepción suma stock y recalcula el costo promedio ponderado del producto.
+CREATE TABLE IF NOT EXISTS goods_receipts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    fecha TEXT NOT NULL,
+    proveedor TEXT NOT NULL,
+    nota TEXT NOT NULL DEFAULT '',
+    total REAL NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS goods_receipt_items (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    receipt_id INTEGER NOT NULL,
+    product_id INTEGER NOT NULL,
+    cantidad INTEGER NOT NULL,
+    costo_unitario REAL NOT NULL,
+    FOREIGN KEY (receipt_id) REFERENCES goods_receipts(id),
+    FOREIGN KEY (product_id) REFERENCES products(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_goods_receipts_fecha ON goods_receipts(fecha);
+CREATE INDEX IF NOT EXISTS idx_goods_receipt_items_receipt ON goods_receipt_items(receipt_id);
+CREATE INDEX IF NOT EXISTS idx_goods_receipt_items_product ON goods_receipt_items(product_id);
+
+-- Cambios de stock que no son ventas (las ventas están en sale_items).
+-- tipo: inicial (alta del producto), recepcion (ref_id = goods_receipts.id) o ajuste.
+-- cantidad es positiva para entradas y negativa para salidas (0 = solo cambió el costo).
+-- costo_unitario es el costo promedio del producto después del movimiento.
+CREATE TABLE IF NOT EXISTS stock_movements (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    product_id INTEGER NOT NULL,
+    fecha TEXT NOT NULL,
+    tipo TEXT NOT NULL,
+    cantidad INTEGER NOT NULL,
+    costo_unitario REAL NOT NULL DEFAULT 0,
+    ref_id INTEGER,
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_movements_product_fecha ON stock_movements(product_id, fecha);
+
+-- ================================
+-- HISTORIAL DE PRECIOS
+-- ================================
+-- Cada cambio del precio de venta queda con la fecha desde la que rige:
+-- el precio en una fecha es el de la última fila con desde <= esa fecha.
+CREATE TABLE IF NOT EXISTS price_history (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    product_id INTEGER NOT NULL,
+    precio REAL NOT NULL,
+    desde TEXT NOT NULL,
+    origen TEXT NOT NULL,
+    change_id INTEGER, -- Cambio programado que lo aplicó
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_price_history_product_desde ON price_history(product_id, desde);
+
+-- Cambios de precio programados: los aplica un proceso periódico cuando llega su fecha.
+-- Llevan precio fijo o porcentaje sobre el precio que tenga el producto al aplicarse.
+CREATE TABLE IF NOT EXISTS price_changes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    product_id INTEGER NOT NULL,
+    precio REAL,
+    porcentaje REAL,
+    desde TEXT NOT NULL,
+    estado TEXT NOT NULL DEFAULT 'pendiente', -- pendiente, aplicado o cancelado
+    creado TEXT NOT NULL,
+    aplicado TEXT,
+    precio_anterior REAL,
+    precio_nuevo REAL,
+    CHECK ((precio IS NULL) <> (porcentaje IS NULL)),
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_price_changes_estado_desde ON price_changes(estado, desde);
+CREATE INDEX IF NOT EXISTS idx_price_changes_product ON price_changes(product_id);
+
+-- ================================
+-- LISTAS DE PRECIOS
+-- ================================
+-- Listas asignables a clientes (minorista, mayorista, contratista...).
+CREATE TABLE IF NOT EXISTS price_lists (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    nombre TEXT NOT NULL UNIQUE COLLATE NOCASE,
+    descripcion TEXT NOT NULL DEFAULT ''
+);
+
+-- Reglas de cada lista: para un producto, una categoría (con subcategorías) o toda la lista
+-- (product_id y category_id NULL), desde cantidad_min unidades de la línea.
+-- precio fijo (solo por producto) o porcentaje sobre el precio del producto.
+CREATE TABLE IF NOT EXISTS price_list_rules (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    list_id INTEGER NOT NULL,
+    product_id INTEGER,
+    category_id INTEGER,
+    cantidad_min INTEGER NOT NULL DEFAULT 1,
+    precio REAL,
+    porcentaje REAL,
+    CHECK ((precio IS NULL) <> (porcentaje IS NULL)),
+    CHECK (precio IS NULL OR product_id IS NOT NULL),
+    CHECK (product_id IS NULL OR category_id IS NULL),
+    FOREIGN KEY (list_id) REFERENCES price_lists(id) ON DELETE CASCADE,
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE,
+    FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_price_list_rules_unique
+    ON price_list_rules(list_id, IFNULL(product_id, 0), IFNULL(category_id, 0), cantidad_min);
+
+-- ================================
+-- PROMOCIONES
+-- ================================
+-- Reglas de descuento con rango de fechas que se evalúan al crear cada venta.
+-- tipo: porcentaje (por línea), nxm (lleva compra + gratis, paga compra; por línea)
+-- o monto (fijo sobre la venta si los productos alcanzados suman monto_min).
+CREATE TABLE IF NOT EXISTS promotions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    nombre TEXT NOT NULL,
+    tipo TEXT NOT NULL,
+    porcentaje REAL NOT NULL DEFAULT 0,
+    compra INTEGER NOT NULL DEFAULT 0,
+    gratis INTEGER NOT NULL DEFAULT 0,
+    monto REAL NOT NULL DEFAULT 0,
+    cantidad_min INTEGER NOT NULL DEFAULT 0,
+    monto_min REAL NOT NULL DEFAULT 0,
+    desde TEXT NOT NULL,
+    hasta TEXT, -- Exclusivo; NULL = sin fin
+    activa INTEGER NOT NULL DEFAULT 1,
+    acumulable INTEGER NOT NULL DEFAULT 0,
+    prioridad INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_promotions_desde ON promotions(desde);
+
+-- Productos, categorías (con subcategorías) o marcas alcanzados.
+-- Sin filas alcanza a todos; con filas el producto debe cumplir cada tipo presente.
+CREATE TABLE IF NOT EXISTS promotion_targets (
+    promotion_id INTEGER NOT NULL,
+    tipo TEXT NOT NULL, -- product, category o brand
+    ref_id INTEGER NOT NULL,
+    PRIMARY KEY (promotion_id, tipo, ref_id),
+    FOREIGN KEY (promotion_id) REFERENCES promotions(id) ON DELETE CASCADE
+);
+
+-- Descuento que hizo cada promoción en una venta: en una línea (sale_item_id)
+-- o sobre la venta (sale_item_id NULL). Suman sale_items.descuento y sales.descuento.
+CREATE TABLE IF NOT EXISTS sale_promotions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    sale_id INTEGER NOT NULL,
+    sale_item_id INTEGER,
+    promotion_id INTEGER NOT NULL,
+    nombre TEXT NOT NULL, -- Nombre de la promoción al momento de la venta
+    monto REAL NOT NULL,
+    FOREIGN KEY (sale_id) REFERENCES sales(id),
+    FOREIGN KEY (sale_item_id) REFERENCES sale_items(id),
+    FOREIGN KEY (promotion_id) REFERENCES promotions(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_sale_promotions_sale ON sale_promotions(sale_id);
+CREATE INDEX IF NOT EXISTS idx_sale_promotions_promotion ON sale_promotions(promotion_id);
+
+-- ================================
+-- USUARIOS Y DESCUENTOS MANUALES
+-- ================================
+-- Descuento manual máximo (%) que cada rol da sin autorización.
+CREATE TABLE IF NOT EXISTS roles (
+    nombre TEXT PRIMARY KEY COLLATE NOCASE,
+    descuento_max REAL NOT NULL CHECK (descuento_max >= 0 AND descuento_max <= 100)
+);
+
+INSERT OR IGNORE INTO roles(nombre, descuento_max) VALUES
+    ('cajero', 5),
+    ('supervisor', 20),
+    ('gerente', 100);
+
+CREATE TABLE IF NOT EXISTS users (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    usuario TEXT NOT NULL UNIQUE COLLATE NOCASE,
+    nombre TEXT NOT NULL,
+    rol TEXT NOT NULL REFERENCES roles(nombre),
+    password_hash TEXT NOT NULL, -- pbkdf2-sha256$iteraciones$sal$hash (base64)
+    activo INTEGER NOT NULL DEFAULT 1,
+    creado TEXT NOT NULL
+);
+
+-- Tokens de un solo uso con que un supervisor autoriza un descuento de antemano.
+CREATE TABLE IF NOT EXISTS discount_approvals (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    token_hash TEXT NOT NULL UNIQUE, -- sha256 del token (el token no se guarda)
+    supervisor TEXT NOT NULL,
+    descuento_max REAL NOT NULL,
+    creado TEXT NOT NULL,
+    expira TEXT NOT NULL,
+    sale_id INTEGER REFERENCES sales(id) -- Venta que lo usó (NULL = disponible)
+);
+
+-- Sesiones iniciadas con usuario y contraseña.
+CREATE TABLE IF NOT EXISTS sessions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    token_hash TEXT NOT NULL UNIQUE, -- sha256 del token (el token no se guarda)
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    creado TEXT NOT NULL,
+    expira TEXT NOT NULL
+);
+
+-- Descuentos manuales de cada venta: en una línea (sale_item_id) o sobre la venta
+-- (sale_item_id NULL). Están incluidos en sale_items.descuento y sales.descuento.
+CREATE TABLE IF NOT EXISTS sale_discounts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    sale_id INTEGER NOT NULL,
+    sale_item_id INTEGER,
+    porcentaje REAL, -- NULL si se pidió un monto
+    monto REAL NOT NULL,
+    motivo TEXT NOT NULL,
     FOREIGN KEY (sale_id) REFERENCES sales(id),
+    FOREIGN KEY (sale_item_id) REFERENCES sale_items(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_sale_discounts_sale ON sale_discounts(sale_id);
+
+-- ================================
+-- PRODUCTOS QUE SE COMPRAN JUNTOS
+-- ================================
+-- La recalcula completa un proceso periódico a partir de sale_items (una canasta por venta).
+-- Cada par se guarda en los dos sentidos porque la confianza depende del sentido:
+-- confianza(A -> B) = ventas con A y B / ventas con A; lift = confianza / soporte de B.
+CREATE TABLE IF NOT EXISTS product_associations (
+    product_id INTEGER NOT NULL,
+    related_id INTEGER NOT NULL,
+    ventas INTEGER NOT NULL, -- Ventas que tienen los dos productos
+    soporte REAL NOT NULL,   -- ventas / total de ventas del período
+    confianza REAL NOT NULL,
+    lift REAL NOT NULL,
+    PRIMARY KEY (product_id, related_id),
+    FOREIGN KEY (product_id) REFERENCES products(id) ON DELETE CASCADE,
+    FOREIGN KEY (related_id) REFERENCES products(id) ON DELETE CASCADE
+);
+
+-- ================================
+-- RESUMEN DIARIO DE VENTAS POR PRODUCTO
+-- ================================
+-- Totales de sale_items por día (en la zona horaria de la tienda) y producto, para que
+-- los reportes no recorran todas las ventas. Se actualiza en la misma transacción que
+-- crea cada venta; "api rebuild-summary" lo recalcula desde cero.
+CREATE TABLE IF NOT EXISTS daily_product_sales (
+    dia TEXT NOT NULL,         -- YYYY-MM-DD local
+    product_id INTEGER NOT NULL,
+    cantidad INTEGER NOT NULL,
+    ventas INTEGER NOT NULL,   -- Ventas con el producto ese día
+    subtotal REAL NOT NULL,    -- Suma de las líneas
+    neto REAL NOT NULL,        -- Subtotal menos el descuento de la línea y la parte del de la cabecera
+    costo REAL NOT NULL,       -- Costo de lo vendido
+    PRIMARY KEY (dia, product_id),
     FOREIGN KEY (product_id) REFERENCES products(id)
-);
\ No newline at end of file
+);
+
+-- Estado del resumen: zona horaria con la que se armaron los días.
+-- Sin fila (nunca se reconstruyó) o con otra zona, los reportes leen las tablas de ventas.
+CREATE TABLE IF NOT EXISTS daily_sales_state (
+    id INTEGER PRIMARY KEY CHECK (id = 1),
+    zona TEXT NOT NULL,
+    reconstruido TEXT NOT NULL
+);
+
+-- ================================
+-- AUDITORÍA
+-- ================================
+-- Registro de altas, cambios y bajas de productos, clientes y ventas.
+-- Solo se agregan filas: los triggers rechazan cualquier UPDATE o DELETE.
+-- cambios es un JSON con los campos que cambiaron: {"precio": {"antes": 5, "despues": 6}}.
+CREATE TABLE IF NOT EXISTS audit_log (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    fecha TEXT NOT NULL,
+    actor TEXT NOT NULL,
+    request_id TEXT NOT NULL DEFAULT '',
+    accion TEXT NOT NULL,
+    entidad TEXT NOT NULL,
+    entidad_id INTEGER,      -- Sin llave foránea: la entidad puede haberse eliminado
+    cambios TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_entidad ON audit_log(entidad, entidad_id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_fecha ON audit_log(fecha);
+
+CREATE TRIGGER IF NOT EXISTS audit_log_no_update BEFORE UPDATE ON audit_log
+BEGIN
+    SELECT RAISE(ABORT, 'audit_log es solo de escritura');
+END;
+
+CREATE TRIGGER IF NOT EXISTS audit_log_no_delete BEFORE DELETE ON audit_log
+BEGIN
+    SELECT RAISE(ABORT, 'audit_log es solo de escritura');
+END;
@@ -2,8 +2,8 @@ const API = ""; // mismo host/puerto
 
 async function fetchJSON(url, options = {}) {
   const res = await fetch(url, {
-    headers: { "Content-Type": "application/json", ...(options.headers || {}) },
     ...options,
+    headers: { "Content-Type": "application/json", ...(options.headers || {}) },
   });
 
   const text = await res.text();
@@ -41,20 +41,27 @@ function escapeHTML(s){
   }[m]));
 }
 
+// Los listados responden { items, total, next_cursor }.
+// Máximo de filas que se piden en una sola página (límite del servidor).
+const PAGE_MAX = 500;
+
 /* ===================== PRODUCTOS ===================== */
 
 async function loadProducts(){
   setMsg("msgProducts", "Cargando productos...");
   try{
-    const list = await fetchJSON(`${API}/api/products`);
-    renderProducts(list || []);
-    setMsg("msgProducts", `Listo: ${list.length} producto(s).`);
+    const categoryID = document.getElementById("filterCategory")?.value || "";
+    const filter = categoryID ? `&category_id=${categoryID}` : "";
+    const page = await fetchJSON(`${API}/api/products?limit=${PAGE_MAX}${filter}`);
+    const list = page.items || [];
+    renderProducts(list, page.total);
+    setMsg("msgProducts", `Listo: ${list.length} de ${page.total} producto(s).`);
   }catch(e){
     setMsg("msgProducts", e.message, true);
   }
 }
 
-function renderProducts(list){
+function renderProducts(list, totalCount){
   const tbody = document.getElementById("productsBody");
   if(!tbody) return;
 
@@ -64,14 +71,17 @@ function renderProducts(list){
     const tr = document.createElement("tr");
     tr.innerHTML = `
       <td>${p.id}</td>
+      <td>${escapeHTML(p.sku)}</td>
       <td>${escapeHTML(p.nombre)}</td>
+      <td>${escapeHTML(p.categoria || "-")}</td>
+      <td>${escapeHTML(p.marca || "-")}</td>
       <td><span class="badge">${p.stock}</span></td>
       <td>${money(p.precio)}</td>
     `;
     tbody.appendChild(tr);
   }
 
-  const total = list.length;
+  const total = totalCount ?? list.length;
   const stockTotal = list.reduce((acc, p) => acc + Number(p.stock||0), 0);
   const valorAprox = list.reduce((acc, p) => acc + (Number(p.stock||0) * Number(p.precio||0)), 0);
 
@@ -84,6 +94,10 @@ async function onCreateProduct(e){
   e.preventDefault();
 
   const nombre = document.getElementById("pNombre").value.trim();
+  const sku = (document.getElementById("pSku")?.value || "").trim();
+  const barcode = (document.getElementById("pBarcode")?.value || "").trim();
+  const categoryID = Number(document.getElementById("pCategory")?.value || 0);
+  const brandID = Number(document.getElementById("pBrand")?.value || 0);
   const stock = Number(document.getElementById("pStock").value);
   const precio = Number(document.getElementById("pPrecio").value);
 
@@ -97,10 +111,17 @@ async function onCreateProduct(e){
   try{
     await fetchJSON(`${API}/api/products`, {
       method: "POST",
-      body: JSON.stringify({ nombre, stock, precio })
+      body: JSON.stringify({
+        nombre, sku, stock, precio,
+        barcodes: barcode ? [barcode] : [],
+        category_id: categoryID || null,
+        brand_id: brandID || null
+      })
     });
 
     document.getElementById("pNombre").value = "";
+    if(document.getElementById("pSku")) document.getElementById("pSku").value = "";
+    if(document.getElementById("pBarcode")) document.getElementById("pBarcode").value = "";
     document.getElementById("pStock").value = "";
     document.getElementById("pPrecio").value = "";
 
@@ -111,14 +132,51 @@ async function onCreateProduct(e){
   }
 }
 
+/* Categorías (árbol) y marcas para filtros y formulario */
+
+async function loadCatalogOptions(){
+  try{
+    const [categories, brands] = await Promise.all([
+      fetchJSON(`${API}/api/categories`),
+      fetchJSON(`${API}/api/brands`)
+    ]);
+
+    // La lista plana viene ordenada por ruta: "Plomería > Tuberías > PVC"
+    fillSelect("filterCategory", categories, "Todas las categorías", c => c.ruta);
+    fillSelect("pCategory", categories, "Sin categoría", c => c.ruta);
+    fillSelect("pBrand", brands, "Sin marca", b => b.nombre);
+  }catch(e){
+    setMsg("msgProducts", e.message, true);
+  }
+}
+
+function fillSelect(id, list, emptyLabel, label){
+  const sel = document.getElementById(id);
+  if(!sel) return;
+
+  sel.innerHTML = "";
+  const opt0 = document.createElement("option");
+  opt0.value = "";
+  opt0.textContent = emptyLabel;
+  sel.appendChild(opt0);
+
+  for(const x of (list || [])){
+    const opt = document.createElement("option");
+    opt.value = String(x.id);
+    opt.textContent = label(x);
+    sel.appendChild(opt);
+  }
+}
+
 /* ===================== CLIENTES ===================== */
 
 async function loadClients(){
   setMsg("msgClients", "Cargando clientes...");
   try{
-    const list = await fetchJSON(`${API}/api/clients`);
-    renderClients(list || []);
-    setMsg("msgClients", `Listo: ${list.length} cliente(s).`);
+    const page = await fetchJSON(`${API}/api/clients?limit=${PAGE_MAX}`);
+    const list = page.items || [];
+    renderClients(list);
+    setMsg("msgClients", `Listo: ${list.length} de ${page.total} cliente(s).`);
   }catch(e){
     setMsg("msgClients", e.message, true);
   }
@@ -176,18 +234,27 @@ async function onCreateClient(e){
 /* ===================== VENTAS ===================== */
 
 let PRODUCTS_CACHE = [];
+let SEARCH_CACHE = [];
 let CLIENTS_CACHE = [];
 let SALE_ITEMS = [];
+// Llave de idempotencia de la venta en curso: se reutiliza en reintentos
+// para que el servidor no registre la venta dos veces.
+let SALE_KEY = null;
+
+function newSaleKey(){
+  if(window.crypto && crypto.randomUUID) return crypto.randomUUID();
+  return `${Date.now()}-${Math.random().toString(16).slice(2)}`;
+}
 
 async function loadSalesPageData(){
   try{
     const [clients, products] = await Promise.all([
-      fetchJSON(`${API}/api/clients`),
-      fetchJSON(`${API}/api/products`)
+      fetchJSON(`${API}/api/clients?limit=${PAGE_MAX}&sort=nombre`),
+      fetchJSON(`${API}/api/products?limit=${PAGE_MAX}&sort=nombre`)
     ]);
 
-    CLIENTS_CACHE = clients || [];
-    PRODUCTS_CACHE = products || [];
+    CLIENTS_CACHE = clients.items || [];
+    PRODUCTS_CACHE = products.items || [];
 
     fillClientsSelect(CLIENTS_CACHE);
     fillProductsSelect(PRODUCTS_CACHE);
@@ -232,7 +299,63 @@ function fillProductsSelect(list){
   }
 }
 
+/* Búsqueda rápida de productos (GET /api/products/search) */
+
+let searchTimer = null;
+
+function onSaleSearch(e){
+  const q = e.target.value.trim();
+  clearTimeout(searchTimer);
+
+  if(q.length < 2){
+    SEARCH_CACHE = [];
+    fillProductsSelect(PRODUCTS_CACHE);
+    return;
+  }
+
+  searchTimer = setTimeout(async () => {
+    try{
+      const list = await fetchJSON(`${API}/api/products/search?q=${encodeURIComponent(q)}&limit=30`);
+      SEARCH_CACHE = list || [];
+      fillProductsSelect(SEARCH_CACHE);
+
+      // Preselecciona el resultado más relevante
+      const sel = document.getElementById("saleProduct");
+      if(sel && SEARCH_CACHE.length) sel.value = String(SEARCH_CACHE[0].id);
+    }catch(err){
+      setMsg("msgSale", err.message, true);
+    }
+  }, 250);
+}
+
+/* Lector de códigos de barras: el escáner USB escribe el código y envía Enter */
+
+async function onSaleScan(e){
+  if(e.key !== "Enter") return;
+  e.preventDefault();
+
+  const input = e.target;
+  const code = input.value.trim();
+  input.value = "";
+  if(!code) return;
+
+  try{
+    const p = await fetchJSON(`${API}/api/products/by-barcode/${encodeURIComponent(code)}`);
+    addProductToSale(p, 1);
+    setMsg("msgSale", `Agregado: ${p.nombre}`);
+  }catch(err){
+    setMsg("msgSale", `${code}: ${err.message}`, true);
+  }
+  input.focus();
+}
+
+function findProduct(id){
+  return SEARCH_CACHE.find(x => Number(x.id) === id) || PRODUCTS_CACHE.find(x => Number(x.id) === id);
+}
+
 function recalcSale(){
+  // Si cambia el carrito, es otra venta: se necesita otra llave
+  SALE_KEY = null;
   SALE_ITEMS.forEach(it => {
     it.subtotal = Number(it.cantidad) * Number(it.precio_unitario);
   });
@@ -240,6 +363,64 @@ function recalcSale(){
   const totalEl = document.getElementById("saleTotal");
   if(totalEl) totalEl.textContent = money(total);
   renderSaleItems();
+  loadSaleRecommendations();
+}
+
+/* Sugerencias: productos que se compran junto con los del carrito
+   (GET /api/products/{id}/recommendations) */
+
+// Sugerencias ya pedidas por producto (se recalculan una vez al día en el servidor)
+const RECS_CACHE = new Map();
+let recsTimer = null;
+
+function loadSaleRecommendations(){
+  clearTimeout(recsTimer);
+  recsTimer = setTimeout(async () => {
+    const ids = SALE_ITEMS.map(it => it.product_id);
+    try{
+      const lists = await Promise.all(ids.map(async id => {
+        if(!RECS_CACHE.has(id)){
+          RECS_CACHE.set(id, await fetchJSON(`${API}/api/products/${id}/recommendations?limit=5`) || []);
+        }
+        return RECS_CACHE.get(id);
+      }));
+      renderSaleRecommendations(lists.flat());
+    }catch(err){
+      // Sin sugerencias la venta sigue igual
+      renderSaleRecommendations([]);
+    }
+  }, 250);
+}
+
+function renderSaleRecommendations(recs){
+  const box = document.getElementById("saleRecsBox");
+  const list = document.getElementById("saleRecs");
+  if(!box || !list) return;
+
+  // Una sugerencia por producto (la de mayor confianza), sin los que ya están en el carrito
+  const inCart = new Set(SALE_ITEMS.map(it => it.product_id));
+  const best = new Map();
+  for(const r of recs){
+    const id = Number(r.product_id);
+    if(inCart.has(id)) continue;
+    const prev = best.get(id);
+    if(!prev || r.confianza > prev.confianza) best.set(id, r);
+  }
+  const top = [...best.values()].sort((a, b) => b.confianza - a.confianza).slice(0, 5);
+
+  list.innerHTML = "";
+  for(const r of top){
+    const btn = document.createElement("button");
+    btn.type = "button";
+    btn.className = "btn secondary";
+    btn.textContent = `+ ${r.producto} • ${money(r.precio)}`;
+    btn.title = `Comprado junto en ${r.ventas_juntos} venta(s)`;
+    btn.addEventListener("click", () => {
+      addProductToSale({ id: r.product_id, nombre: r.producto, precio: r.precio }, 1);
+    });
+    list.appendChild(btn);
+  }
+  box.style.display = top.length ? "block" : "none";
 }
 
 function renderSaleItems(){
@@ -279,9 +460,16 @@ function addSaleItem(){
 
   if(!productID || !cantidad) return;
 
-  const p = PRODUCTS_CACHE.find(x => Number(x.id) === productID);
+  const p = findProduct(productID);
   if(!p) return;
 
+  addProductToSale(p, cantidad);
+  qtyEl.value = "";
+}
+
+function addProductToSale(p, cantidad){
+  const productID = Number(p.id);
+
   // si ya existe, acumula cantidad
   const ex = SALE_ITEMS.find(it => it.product_id === productID);
   if(ex){
@@ -296,7 +484,6 @@ function addSaleItem(){
     });
   }
 
-  qtyEl.value = "";
   recalcSale();
 }
 
@@ -321,18 +508,19 @@ async function confirmSale(){
 
   setMsg("msgSale", "Registrando venta...");
 
+  if(!SALE_KEY) SALE_KEY = newSaleKey();
+
   try{
-    await fetchJSON(`${API}/api/sales`, {
-      method: "POST",
-      body: JSON.stringify(payload)
-    });
+    await postSaleWithRetry(payload, SALE_KEY);
 
     setMsg("msgSale", "Venta creada ✅");
+    SALE_KEY = null;
     SALE_ITEMS = [];
     recalcSale();
 
     // refrescar stock y lista
-    PRODUCTS_CACHE = await fetchJSON(`${API}/api/products`);
+    const products = await fetchJSON(`${API}/api/products?limit=${PAGE_MAX}&sort=nombre`);
+    PRODUCTS_CACHE = products.items || [];
     fillProductsSelect(PRODUCTS_CACHE);
     await loadSalesList();
   }catch(e){
@@ -340,10 +528,27 @@ async function confirmSale(){
   }
 }
 
+// postSaleWithRetry reintenta solo fallos de red (sin respuesta del servidor)
+// usando la misma Idempotency-Key.
+async function postSaleWithRetry(payload, key, attempts = 3){
+  for(let i = 1; ; i++){
+    try{
+      return await fetchJSON(`${API}/api/sales`, {
+        method: "POST",
+        headers: { "Idempotency-Key": key },
+        body: JSON.stringify(payload)
+      });
+    }catch(e){
+      if(!(e instanceof TypeError) || i >= attempts) throw e;
+      await new Promise(r => setTimeout(r, 500 * i));
+    }
+  }
+}
+
 async function loadSalesList(){
   try{
-    const list = await fetchJSON(`${API}/api/sales`);
-    renderSales(list || []);
+    const page = await fetchJSON(`${API}/api/sales?limit=100`);
+    renderSales(page.items || []);
   }catch(e){
     setMsg("msgSalesList", e.message, true);
   }
@@ -428,9 +633,17 @@ async function loadVentasHoy(){
   box.textContent = "Cargando reporte del día...";
 
   try{
-    const r = await fetchJSON(`${API}/api/report/ventas-hoy`);
-    box.className = "msg";
-    box.textContent = `Hoy: ${r.ventas} venta(s) • Total: ${money(r.total)}`;
+    // Un solo llamado con hoy, semana, mes, stock bajo y más vendidos
+    const d = await fetchJSON(`${API}/api/dashboard`);
+    const vs = p => p && p.variacion_pct != null ? ` (${p.variacion_pct > 0 ? "+" : ""}${p.variacion_pct}%)` : "";
+    const parts = [];
+    if(d.hoy) parts.push(`Hoy: ${d.hoy.actual.ventas} venta(s) • Total: ${money(d.hoy.actual.total)}${vs(d.hoy)}`);
+    if(d.semana) parts.push(`Semana: ${money(d.semana.actual.total)}${vs(d.semana)}`);
+    if(d.mes) parts.push(`Mes: ${money(d.mes.actual.total)}${vs(d.mes)}`);
+    if(d.stock_bajo != null) parts.push(`Stock bajo (< ${d.umbral_stock}): ${d.stock_bajo}`);
+    if(d.top_productos && d.top_productos.length) parts.push(`Más vendido del mes: ${d.top_productos[0].producto}`);
+    box.className = d.errores ? "msg error" : "msg";
+    box.textContent = parts.join(" • ") || "Sin datos";
   }catch(e){
     box.className = "msg error";
     box.textContent = e.message;
@@ -444,7 +657,10 @@ document.addEventListener("DOMContentLoaded", () => {
   const formProduct = document.getElementById("formCreateProduct");
   if(formProduct){
     formProduct.addEventListener("submit", onCreateProduct);
+    loadCatalogOptions();
     loadProducts();
+    const filterCategory = document.getElementById("filterCategory");
+    if(filterCategory) filterCategory.addEventListener("change", loadProducts);
   }
 
   const formClient = document.getElementById("formCreateClient");
@@ -462,6 +678,10 @@ document.addEventListener("DOMContentLoaded", () => {
   if(btnAddItem && btnConfirmSale){
     btnAddItem.addEventListener("click", addSaleItem);
     btnConfirmSale.addEventListener("click", confirmSale);
+    const saleSearch = document.getElementById("saleSearch");
+    if(saleSearch) saleSearch.addEventListener("input", onSaleSearch);
+    const saleScan = document.getElementById("saleScan");
+    if(saleScan) saleScan.addEventListener("keydown", onSaleScan);
     loadSalesPageData();
     loadSalesList();
     recalcSale();